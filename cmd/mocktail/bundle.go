@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+func newBundleCmd() *cobra.Command {
+	var (
+		out         string
+		dereference bool
+		baseURL     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bundle <schema-file>",
+		Short: "Write an OpenAPI schema as a single self-contained file",
+		Long: `Load an OpenAPI schema, pull in any external file references, and write the result
+as one document. With --dereference every $ref is replaced by its definition, for tools
+that can't follow references. --base-url replaces the document's servers with that URL,
+so clients generated from the bundle target it. Unlike scaffold, which has no servers to
+keep and defaults to http://localhost:8080, bundle leaves the spec's servers alone unless
+--base-url is given.
+
+The output is YAML unless --out ends in .json.
+
+Examples:
+  # Bundle a multi-file spec into one YAML file
+  mocktail bundle api.yaml --out bundled.yaml
+
+  # Fully expand all references into JSON
+  mocktail bundle api.yaml --dereference --out expanded.json
+
+  # Point the bundle at staging
+  mocktail bundle api.yaml --base-url https://staging.example.com/v1 --out staging.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if baseURL != "" {
+				if err := checkBaseURL(baseURL); err != nil {
+					return err
+				}
+			}
+
+			schema, err := parser.NewOpenAPIParser().Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
+			doc, ok := schema.Raw.(*openapi3.T)
+			if !ok {
+				return fmt.Errorf("invalid schema format")
+			}
+			if baseURL != "" {
+				doc.Servers = openapi3.Servers{{URL: strings.TrimSuffix(baseURL, "/")}}
+			}
+
+			data, warnings, err := parser.Bundle(doc, dereference)
+			if err != nil {
+				return fmt.Errorf("failed to bundle schema: %w", err)
+			}
+			if !strings.EqualFold(filepath.Ext(out), ".json") {
+				if data, err = parser.JSONToYAML(data); err != nil {
+					return err
+				}
+			}
+
+			for _, warning := range warnings {
+				fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+			}
+
+			if out == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(out, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", out, err)
+			}
+			fmt.Printf("✓ Wrote bundled schema to %s\n", out)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Output file, .json for JSON (default: YAML to stdout)")
+	cmd.Flags().BoolVar(&dereference, "dereference", false, "Inline every $ref so the output has no references")
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "Base URL to set as the bundle's only server (default: keep the spec's servers)")
+
+	return cmd
+}
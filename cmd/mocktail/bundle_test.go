@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestBundleCommand(t *testing.T) {
+	for _, name := range []string{"bundled.yaml", "bundled.json"} {
+		t.Run(name, func(t *testing.T) {
+			outFile := filepath.Join(t.TempDir(), name)
+
+			// Silence the success message
+			discardStdout(t)
+
+			rootCmd := newRootCmd()
+			rootCmd.SetArgs([]string{"bundle", "../../examples/petstore.yaml", "--dereference", "--out", outFile})
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("bundle failed: %v", err)
+			}
+
+			data, err := os.ReadFile(outFile)
+			if err != nil {
+				t.Fatalf("Failed to read bundle: %v", err)
+			}
+			if bytes.Contains(data, []byte("$ref")) {
+				t.Error("Expected a dereferenced spec without $ref")
+			}
+
+			// The bundle must parse to the same operations
+			original, err := parser.NewOpenAPIParser().Parse("../../examples/petstore.yaml")
+			if err != nil {
+				t.Fatalf("Failed to parse original: %v", err)
+			}
+			bundled, err := parser.NewOpenAPIParser().Parse(outFile)
+			if err != nil {
+				t.Fatalf("Bundled spec failed to parse: %v", err)
+			}
+			if len(bundled.Paths) != len(original.Paths) {
+				t.Fatalf("Expected %d paths, got %d", len(original.Paths), len(bundled.Paths))
+			}
+
+			// Payloads generated from either spec must satisfy the other
+			for path := range original.Paths {
+				for _, endpoint := range original.Paths[path] {
+					want := responseSchema(original, path, endpoint.Method)
+					got := responseSchema(bundled, path, endpoint.Method)
+					if (want == nil) != (got == nil) {
+						t.Fatalf("%s %s: response schema presence differs", endpoint.Method, path)
+					}
+					if want == nil {
+						continue
+					}
+					crossValidate(t, want, got)
+					crossValidate(t, got, want)
+				}
+			}
+		})
+	}
+}
+
+// responseSchema returns the 2xx JSON response schema of an operation
+func responseSchema(schema *parser.Schema, path, method string) *openapi3.Schema {
+	operation := schema.Raw.(*openapi3.T).Paths.Find(path).GetOperation(method)
+	return validator.ResponseSchema(operation, successStatus(operation))
+}
+
+// crossValidate checks that a payload generated from one schema satisfies another
+func crossValidate(t *testing.T, from, against *openapi3.Schema) {
+	t.Helper()
+	value, err := generator.NewGenerator(7).GenerateFromSchema(from)
+	if err != nil {
+		t.Fatalf("Failed to generate payload: %v", err)
+	}
+	if err := validator.ValidateValue(against, value); err != nil {
+		t.Errorf("Generated payload doesn't match the other spec: %v", err)
+	}
+}
+
+func TestBundleCommandBaseURL(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "bundled.yaml")
+
+	discardStdout(t)
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"bundle", "../../examples/petstore.yaml", "--base-url", "https://staging.example.com/v1/", "--out", outFile})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("bundle failed: %v", err)
+	}
+
+	schema, err := parser.NewOpenAPIParser().Parse(outFile)
+	if err != nil {
+		t.Fatalf("Bundled spec failed to parse: %v", err)
+	}
+	doc, ok := schema.Raw.(*openapi3.T)
+	if !ok || len(doc.Servers) != 1 || doc.Servers[0].URL != "https://staging.example.com/v1" {
+		t.Errorf("Expected the single server https://staging.example.com/v1, got %+v", doc.Servers)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Vooblin/mocktail/internal/coverage"
+	"github.com/Vooblin/mocktail/internal/mock"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+func newCoverageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "coverage <schema-file> <recording.jsonl>",
+		Short: "Report which endpoints recorded traffic exercised",
+		Long: `Match the requests in a recording made with 'mocktail mock --record' against the
+schema's endpoints by method and path template, and report which endpoints were
+exercised and which weren't.
+
+For each endpoint the table lists the status codes seen next to those the schema
+declares; seen statuses that no declared response covers are marked with "!".
+Requests that match no endpoint are listed separately.
+
+Examples:
+  # Record a test run, then see what it covered
+  mocktail mock examples/petstore.yaml --record traffic.jsonl
+  mocktail coverage examples/petstore.yaml traffic.jsonl`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaFile, recordingFile := args[0], args[1]
+
+			schema, err := parser.NewOpenAPIParser().Parse(schemaFile)
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
+
+			file, err := os.Open(recordingFile)
+			if err != nil {
+				return fmt.Errorf("failed to open recording: %w", err)
+			}
+			defer file.Close()
+
+			exchanges, err := mock.ReadRecording(file)
+			if err != nil {
+				return err
+			}
+
+			requests := make([]coverage.Request, len(exchanges))
+			for i, exchange := range exchanges {
+				requests[i] = coverage.Request{Method: exchange.Method, Path: exchange.Path, Route: exchange.Route, Status: exchange.Status}
+			}
+
+			report := coverage.Compute(schema, requests)
+			fmt.Printf("Coverage: %d/%d endpoints (%.1f%%) from %d request(s)\n\n",
+				report.Covered(), len(report.Endpoints), report.Percent(), len(requests))
+			printCoverage(report)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printCoverage writes the per-endpoint coverage table and any unmatched requests
+func printCoverage(report coverage.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, " \tMETHOD\tPATH\tHITS\tSEEN\tDECLARED")
+	for _, endpoint := range report.Endpoints {
+		mark := "✗"
+		if endpoint.Covered() {
+			mark = "✓"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", mark, endpoint.Method, endpoint.Path, endpoint.Requests,
+			orDash(endpoint.FormatStatuses()), orDash(strings.Join(endpoint.Declared, " ")))
+	}
+	w.Flush()
+
+	if len(report.Unmatched) == 0 {
+		return
+	}
+	requests := make([]string, 0, len(report.Unmatched))
+	for request := range report.Unmatched {
+		requests = append(requests, request)
+	}
+	sort.Strings(requests)
+	fmt.Printf("\n%d request(s) matched no endpoint:\n", len(requests))
+	for _, request := range requests {
+		fmt.Printf("  %s ×%d\n", request, report.Unmatched[request])
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCoverageCommand(t *testing.T) {
+	recording := filepath.Join(t.TempDir(), "traffic.jsonl")
+	lines := `{"method": "GET", "path": "/pets", "status": 200}
+{"method": "GET", "path": "/pets/42", "status": 500}
+{"method": "DELETE", "path": "/owners/1", "status": 404}
+`
+	if err := os.WriteFile(recording, []byte(lines), 0644); err != nil {
+		t.Fatalf("Failed to write recording: %v", err)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = writer
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"coverage", "../../examples/petstore.yaml", recording})
+	err = rootCmd.Execute()
+
+	writer.Close()
+	os.Stdout = oldStdout
+	output, _ := io.ReadAll(reader)
+
+	if err != nil {
+		t.Fatalf("Coverage failed: %v", err)
+	}
+	for _, want := range []string{"Coverage: 2/", "/pets/{petId}", "500!×1", "DELETE /owners/1 ×1"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	rootCmd = newRootCmd()
+	rootCmd.SetArgs([]string{"coverage", "../../examples/petstore.yaml", filepath.Join(t.TempDir(), "missing.jsonl")})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "failed to open recording") {
+		t.Errorf("Expected a missing recording to fail, got %v", err)
+	}
+}
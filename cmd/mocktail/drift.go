@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/drift"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+func newDriftCmd() *cobra.Command {
+	var (
+		baselineFile   string
+		updateBaseline bool
+		paths          []string
+		timeout        time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drift <schema-file> <base-url>",
+		Short: "Detect response drift of a live API against a stored baseline",
+		Long: `Fetch live responses for the schema's GET endpoints and compare their structure with
+a stored baseline. The first run records the baseline; later runs report fields that
+appeared, disappeared, or changed type, and status code changes.
+
+Removed fields, type changes, and status changes are breaking and make the command exit
+non-zero. Added fields are reported but allowed. Pass --update-baseline to accept the
+current responses as the new baseline.
+
+Paths with parameters are skipped unless listed with --path using concrete values.
+
+Examples:
+  # Record a baseline, then check for drift on later runs
+  mocktail drift examples/petstore.yaml https://staging.example.com
+
+  # Watch specific endpoints and accept the current shape
+  mocktail drift examples/petstore.yaml https://staging.example.com --path /pets --path /pets/42 --update-baseline`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaFile, baseURL := args[0], args[1]
+
+			schema, err := parser.NewOpenAPIParser().Parse(schemaFile)
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
+
+			if len(paths) == 0 {
+				paths = watchedPaths(schema)
+			}
+			if len(paths) == 0 {
+				return fmt.Errorf("no GET endpoints without path parameters to watch; use --path")
+			}
+
+			baseline, exists, err := drift.LoadBaseline(baselineFile)
+			if err != nil {
+				return err
+			}
+
+			client := &http.Client{Timeout: timeout}
+			current := make(map[string]drift.Snapshot)
+			failed := 0
+			for _, path := range paths {
+				endpoint := http.MethodGet + " " + path
+				snapshot, err := drift.Fetch(context.Background(), client, baseURL, http.MethodGet, path)
+				if err != nil {
+					failed++
+					fmt.Printf("✗ %s: %v\n", endpoint, err)
+					continue
+				}
+				current[endpoint] = snapshot
+			}
+
+			if !exists {
+				baseline.Endpoints = current
+				if err := baseline.Save(baselineFile); err != nil {
+					return err
+				}
+				fmt.Printf("📸 Recorded baseline for %d endpoint(s) in %s\n", len(current), baselineFile)
+				return fetchFailures(failed)
+			}
+
+			breaking := 0
+			for _, endpoint := range sortedKeys(current) {
+				before, ok := baseline.Endpoints[endpoint]
+				if !ok {
+					fmt.Printf("🆕 %s: not in baseline\n", endpoint)
+					continue
+				}
+				changes := drift.Compare(endpoint, before, current[endpoint])
+				if len(changes) == 0 {
+					fmt.Printf("✓ %s\n", endpoint)
+					continue
+				}
+				for _, change := range changes {
+					if change.Breaking() {
+						breaking++
+						fmt.Printf("⚠️  %s\n", change)
+					} else {
+						fmt.Printf("➕ %s\n", change)
+					}
+				}
+			}
+
+			if updateBaseline {
+				for endpoint, snapshot := range current {
+					baseline.Endpoints[endpoint] = snapshot
+				}
+				if err := baseline.Save(baselineFile); err != nil {
+					return err
+				}
+				fmt.Printf("\n📸 Updated baseline %s\n", baselineFile)
+				return fetchFailures(failed)
+			}
+
+			if breaking > 0 {
+				return fmt.Errorf("%d breaking change(s) since the baseline", breaking)
+			}
+			return fetchFailures(failed)
+		},
+	}
+
+	cmd.Flags().StringVar(&baselineFile, "baseline", "mocktail-baseline.json", "Baseline file to create or compare against")
+	cmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "Accept the current responses as the new baseline")
+	cmd.Flags().StringArrayVarP(&paths, "path", "p", nil, "Concrete path to watch (repeatable; default: every GET path without parameters)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "Per-request timeout")
+
+	return cmd
+}
+
+// watchedPaths returns the schema's GET paths that can be requested as-is
+func watchedPaths(schema *parser.Schema) []string {
+	var paths []string
+	for path, endpoints := range schema.Paths {
+		if strings.Contains(path, "{") {
+			continue
+		}
+		for _, endpoint := range endpoints {
+			if endpoint.Method == http.MethodGet {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// sortedKeys returns a snapshot map's endpoints in sorted order
+func sortedKeys(snapshots map[string]drift.Snapshot) []string {
+	keys := make([]string, 0, len(snapshots))
+	for key := range snapshots {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fetchFailures turns a count of unreachable endpoints into the command's error
+func fetchFailures(failed int) error {
+	if failed > 0 {
+		return fmt.Errorf("%d endpoint(s) could not be fetched", failed)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDriftCommand(t *testing.T) {
+	body := `[{"id": "1", "name": "Rex", "species": "dog"}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	baselineFile := filepath.Join(t.TempDir(), "baseline.json")
+
+	// Silence the report
+	discardStdout(t)
+
+	run := func(extra ...string) error {
+		rootCmd := newRootCmd()
+		args := append([]string{"drift", "../../examples/petstore.yaml", server.URL, "--baseline", baselineFile, "--path", "/pets"}, extra...)
+		rootCmd.SetArgs(args)
+		return rootCmd.Execute()
+	}
+
+	// The first run records the baseline
+	if err := run(); err != nil {
+		t.Fatalf("Recording the baseline failed: %v", err)
+	}
+	if _, err := os.Stat(baselineFile); err != nil {
+		t.Fatalf("Expected baseline file: %v", err)
+	}
+
+	// Additive changes pass
+	body = `[{"id": "1", "name": "Rex", "species": "dog", "color": "brown"}]`
+	if err := run(); err != nil {
+		t.Errorf("Expected an added field to pass, got %v", err)
+	}
+
+	// Removing a field is breaking
+	body = `[{"id": 1, "name": "Rex"}]`
+	err := run()
+	if err == nil || !strings.Contains(err.Error(), "2 breaking change(s)") {
+		t.Errorf("Expected 2 breaking changes, got %v", err)
+	}
+
+	// Accepting the new shape makes later runs pass
+	if err := run("--update-baseline"); err != nil {
+		t.Errorf("Updating the baseline failed: %v", err)
+	}
+	if err := run(); err != nil {
+		t.Errorf("Expected no drift after updating the baseline, got %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Vooblin/mocktail/internal/generator"
@@ -13,10 +15,25 @@ import (
 
 func newGenerateCmd() *cobra.Command {
 	var (
-		path   string
-		method string
-		seed   int64
-		count  int
+		path        string
+		method      string
+		operationID string
+		component   string
+		seedValue   string
+		count       int
+		locale      string
+		emailDomain string
+		nameEmails  bool
+		all         bool
+		useDefaults bool
+		mergeAnyOf  bool
+		coverEnums  bool
+		jsonSafe    bool
+		consistent  bool
+		format      string
+		noCache     bool
+		showSchema  bool
+		baseURL     string
 	)
 
 	cmd := &cobra.Command{
@@ -27,6 +44,10 @@ func newGenerateCmd() *cobra.Command {
 This command creates sample request and response payloads based on your OpenAPI schema,
 useful for contract testing, API documentation, and integration tests.
 
+--base-url labels each payload with the full URL it targets. It has no default, unlike
+scaffold's http://localhost:8080: without it, payloads are labelled with the path alone
+so existing output does not change.
+
 Examples:
   # Generate a response for GET /pets
   mocktail generate examples/petstore.yaml --path /pets --method GET
@@ -34,49 +55,65 @@ Examples:
   # Generate a request body for POST /pets
   mocktail generate examples/petstore.yaml --path /pets --method POST
 
+  # Select the operation by its operationId instead of path and method
+  mocktail generate examples/petstore.yaml --operation listPets
+
+  # Generate payloads for every method of /pets
+  mocktail generate examples/petstore.yaml --path /pets
+
+  # Generate payloads for every operation in the schema
+  mocktail generate examples/petstore.yaml --all
+
+  # Generate a model fixture from a component schema, independent of any route
+  mocktail generate examples/petstore.yaml --schema Pet --seed 42
+
+  # Print the resolved schema next to the payload, to see why a field came out as it did
+  mocktail generate examples/petstore.yaml --path /pets --method GET --show-schema
+
+  # Write fixtures as YAML
+  mocktail generate examples/petstore.yaml --path /pets --method GET --format yaml
+
   # Generate multiple samples with custom seed
-  mocktail generate examples/petstore.yaml --path /pets --method GET --count 3 --seed 42`,
+  mocktail generate examples/petstore.yaml --path /pets --method GET --count 3 --seed 42
+
+  # Label each payload with the full URL it targets on staging
+  mocktail generate examples/petstore.yaml --all --base-url https://staging.example.com/v1`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			schemaFile := args[0]
 
-			// Parse the schema
-			p := parser.NewOpenAPIParser()
+			// Parse the schema, reusing a cached parse of an unchanged file
+			var opts parser.ParseOptions
+			if !noCache {
+				if cacheDir, err := parser.DefaultCacheDir(); err == nil {
+					opts.CacheDir = cacheDir
+				}
+			}
+			p := parser.NewOpenAPIParserWithOptions(opts)
 			schema, err := p.Parse(schemaFile)
 			if err != nil {
 				return fmt.Errorf("failed to parse schema: %w", err)
 			}
 
-			// Validate path and method
-			if path == "" {
-				return fmt.Errorf("--path flag is required")
+			if format != "json" && format != "yaml" && format != "protobuf" {
+				return fmt.Errorf("unsupported format %q (use json, yaml, or protobuf)", format)
 			}
-			if method == "" {
-				return fmt.Errorf("--method flag is required")
-			}
-
-			// Find the endpoint
-			endpoints, exists := schema.Paths[path]
-			if !exists {
-				return fmt.Errorf("path %s not found in schema", path)
-			}
-
-			var endpoint *parser.Endpoint
-			for _, ep := range endpoints {
-				if ep.Method == method {
-					endpoint = &ep
-					break
+			if baseURL != "" {
+				if err := checkBaseURL(baseURL); err != nil {
+					return err
 				}
 			}
 
-			if endpoint == nil {
-				return fmt.Errorf("method %s not found for path %s", method, path)
+			// Use current time as default seed if not specified
+			seed := time.Now().UnixNano()
+			if seedValue != "" {
+				seed = generator.ParseSeed(seedValue)
 			}
 
-			// Use current time as default seed if not specified
-			if seed == 0 {
-				seed = time.Now().UnixNano()
+			if _, ok := generator.LookupLocale(locale); !ok {
+				return fmt.Errorf("unknown locale %q (available: %s)", locale, strings.Join(generator.LocaleNames(), ", "))
 			}
+			genOpts := generator.GenerateOptions{Locale: locale, EmailDomain: emailDomain, NameEmails: nameEmails, UseDefaults: useDefaults, MergeAnyOf: mergeAnyOf, CoverEnums: coverEnums, JSONSafeIntegers: jsonSafe, Consistent: consistent, ProtoJSON: format == "protobuf"}
 
 			// Get the OpenAPI document
 			doc, ok := schema.Raw.(*openapi3.T)
@@ -84,70 +121,79 @@ Examples:
 				return fmt.Errorf("invalid schema format")
 			}
 
-			pathItem := doc.Paths.Find(path)
-			if pathItem == nil {
-				return fmt.Errorf("path item not found")
+			if component != "" {
+				if all || path != "" || method != "" || operationID != "" {
+					return fmt.Errorf("--schema cannot be combined with --path, --method, --operation, or --all")
+				}
+				return generateComponent(doc, component, seed, count, genOpts, format, showSchema)
 			}
 
-			operation := pathItem.Operations()[method]
-			if operation == nil {
-				return fmt.Errorf("operation not found")
+			if len(schema.Paths) == 0 {
+				return fmt.Errorf("schema %s declares no paths; nothing to generate", schemaFile)
 			}
 
-			// Generate payloads
-			fmt.Printf("Generating %d payload(s) for %s %s (seed: %d)\n\n", count, method, path, seed)
-
-			for i := 0; i < count; i++ {
-				gen := generator.NewGenerator(seed + int64(i))
-
-				// Generate request body if this is a POST/PUT/PATCH
-				if method == "POST" || method == "PUT" || method == "PATCH" {
-					if operation.RequestBody != nil && operation.RequestBody.Value != nil {
-						jsonContent := operation.RequestBody.Value.Content.Get("application/json")
-						if jsonContent != nil && jsonContent.Schema != nil {
-							fmt.Printf("=== Request Body #%d ===\n", i+1)
-							payload, err := gen.GenerateFromSchema(jsonContent.Schema.Value)
-							if err != nil {
-								return fmt.Errorf("failed to generate request body: %w", err)
-							}
+			if all && path != "" {
+				return fmt.Errorf("--all cannot be combined with --path")
+			}
+			if operationID != "" && (all || path != "" || method != "") {
+				return fmt.Errorf("--operation cannot be combined with --path, --method, or --all")
+			}
+			if !all && path == "" && operationID == "" {
+				return fmt.Errorf("--path flag is required (or use --operation or --all)")
+			}
 
-							jsonData, err := json.MarshalIndent(payload, "", "  ")
-							if err != nil {
-								return fmt.Errorf("failed to marshal JSON: %w", err)
-							}
-							fmt.Println(string(jsonData))
-							fmt.Println()
-						}
+			// Collect the operations to generate, grouped by path then method
+			var targets []parser.Endpoint
+			if all {
+				paths := make([]string, 0, len(schema.Paths))
+				for p := range schema.Paths {
+					paths = append(paths, p)
+				}
+				sort.Strings(paths)
+				for _, p := range paths {
+					targets = append(targets, schema.Paths[p]...)
+				}
+			} else if operationID != "" {
+				endpoint, found := schema.FindByOperationID(operationID)
+				if !found {
+					return fmt.Errorf("operation %s not found in schema", operationID)
+				}
+				targets = append(targets, endpoint)
+			} else {
+				endpoints, exists := schema.Paths[path]
+				if !exists {
+					return fmt.Errorf("path %s not found in schema", path)
+				}
+				for _, ep := range endpoints {
+					if method == "" || ep.Method == method {
+						targets = append(targets, ep)
 					}
 				}
+				if len(targets) == 0 {
+					return fmt.Errorf("method %s not found for path %s", method, path)
+				}
+			}
+
+			sort.SliceStable(targets, func(i, j int) bool {
+				if targets[i].Path != targets[j].Path {
+					return targets[i].Path < targets[j].Path
+				}
+				return targets[i].Method < targets[j].Method
+			})
 
-				// Generate response for 200/201 status
-				var responseSchema *openapi3.Schema
-				if operation.Responses != nil {
-					if resp := operation.Responses.Status(200); resp != nil && resp.Value != nil {
-						if jsonContent := resp.Value.Content.Get("application/json"); jsonContent != nil {
-							responseSchema = jsonContent.Schema.Value
-						}
-					} else if resp := operation.Responses.Status(201); resp != nil && resp.Value != nil {
-						if jsonContent := resp.Value.Content.Get("application/json"); jsonContent != nil {
-							responseSchema = jsonContent.Schema.Value
-						}
-					}
+			for _, target := range targets {
+				pathItem := doc.Paths.Find(target.Path)
+				if pathItem == nil {
+					return fmt.Errorf("path item not found")
 				}
 
-				if responseSchema != nil {
-					fmt.Printf("=== Response Body #%d ===\n", i+1)
-					payload, err := gen.GenerateFromSchema(responseSchema)
-					if err != nil {
-						return fmt.Errorf("failed to generate response body: %w", err)
-					}
+				operation := pathItem.GetOperation(target.Method)
+				if operation == nil {
+					return fmt.Errorf("operation not found")
+				}
 
-					jsonData, err := json.MarshalIndent(payload, "", "  ")
-					if err != nil {
-						return fmt.Errorf("failed to marshal JSON: %w", err)
-					}
-					fmt.Println(string(jsonData))
-					fmt.Println()
+				if err := generatePayloads(doc, target.Method, target.Path, baseURL, operation, seed, count, genOpts, format, showSchema); err != nil {
+					return err
 				}
 			}
 
@@ -156,9 +202,170 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&path, "path", "p", "", "API path (e.g., /pets)")
-	cmd.Flags().StringVarP(&method, "method", "m", "", "HTTP method (e.g., GET, POST)")
-	cmd.Flags().Int64VarP(&seed, "seed", "s", 0, "Random seed for reproducible output (default: current time)")
+	cmd.Flags().StringVarP(&method, "method", "m", "", "HTTP method (e.g., GET, POST); omit to generate every method of the path")
+	cmd.Flags().StringVar(&operationID, "operation", "", "Operation to generate, by operationId (alternative to --path and --method)")
+	cmd.Flags().StringVar(&component, "schema", "", "Component schema to generate, by name (e.g., Pet for #/components/schemas/Pet)")
+	cmd.Flags().StringVarP(&seedValue, "seed", "s", "", "Random seed for reproducible output, a number or any string (default: current time)")
 	cmd.Flags().IntVarP(&count, "count", "c", 1, "Number of payloads to generate")
+	cmd.Flags().StringVar(&locale, "locale", generator.DefaultLocale, "Word list and domain for generated strings (en, de, es)")
+	cmd.Flags().StringVar(&emailDomain, "email-domain", "", "Domain of generated emails (default: the locale's, e.g. example.com)")
+	cmd.Flags().BoolVar(&nameEmails, "name-emails", false, "Derive the local part of generated emails from a name field in the same object")
+	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format (json|yaml|protobuf); protobuf is JSON following the protobuf JSON mapping")
+	cmd.Flags().BoolVar(&useDefaults, "use-defaults", false, "Use a schema's declared default instead of random data")
+	cmd.Flags().BoolVar(&mergeAnyOf, "merge-any-of", false, "Merge a random selection of anyOf object branches instead of picking one")
+	cmd.Flags().BoolVar(&coverEnums, "cover-enums", false, "Include every enum member at least once in arrays of enum items")
+	cmd.Flags().BoolVar(&jsonSafe, "json-safe-integers", false, "Cap generated integers at ±(2^53-1) so JavaScript clients read them exactly")
+	cmd.Flags().BoolVar(&consistent, "consistent-refs", false, "Make <name>Id fields match the id of a sibling or nested <name> object")
+	cmd.Flags().BoolVar(&showSchema, "show-schema", false, "Print the fully dereferenced schema each payload is generated from")
+	cmd.Flags().BoolVar(&all, "all", false, "Generate payloads for every operation in the schema")
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "Base URL to label each operation with, e.g. http://localhost:8080 (default: the path alone)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Always re-parse the schema instead of using the on-disk cache")
 
 	return cmd
 }
+
+// generatePayloads prints count request/response samples for a single operation, preceded
+// by the dereferenced schemas they come from when showSchema is set. A baseURL turns the
+// operation's path into the full URL it is sent to.
+func generatePayloads(doc *openapi3.T, method, path, baseURL string, operation *openapi3.Operation, seed int64, count int, opts generator.GenerateOptions, format string, showSchema bool) error {
+	fmt.Printf("Generating %d payload(s) for %s %s%s (seed: %d)\n\n", count, method, strings.TrimSuffix(baseURL, "/"), path, seed)
+
+	// Generate request body if this is a POST/PUT/PATCH
+	var requestSchema *openapi3.Schema
+	if method == "POST" || method == "PUT" || method == "PATCH" {
+		if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+			jsonContent := operation.RequestBody.Value.Content.Get("application/json")
+			if jsonContent != nil && jsonContent.Schema != nil {
+				requestSchema = jsonContent.Schema.Value
+			}
+		}
+	}
+
+	// Generate response for 200/201 status
+	var responseSchema *openapi3.Schema
+	if operation.Responses != nil {
+		if resp := operation.Responses.Status(200); resp != nil && resp.Value != nil {
+			if jsonContent := resp.Value.Content.Get("application/json"); jsonContent != nil {
+				responseSchema = jsonContent.Schema.Value
+			}
+		} else if resp := operation.Responses.Status(201); resp != nil && resp.Value != nil {
+			if jsonContent := resp.Value.Content.Get("application/json"); jsonContent != nil {
+				responseSchema = jsonContent.Schema.Value
+			}
+		}
+	}
+
+	if showSchema {
+		if err := printSchema(doc, "Request Schema", requestSchema, format); err != nil {
+			return err
+		}
+		if err := printSchema(doc, "Response Schema", responseSchema, format); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		gen := generator.NewGeneratorWithOptions(seed+int64(i), opts)
+
+		if requestSchema != nil {
+			fmt.Printf("=== Request Body #%d ===\n", i+1)
+			data, err := encodePayload(gen.WithContext(generator.ContextRequest), requestSchema, format)
+			if err != nil {
+				return fmt.Errorf("failed to generate request body: %w", err)
+			}
+			fmt.Println(string(data))
+			fmt.Println()
+		}
+
+		if responseSchema != nil {
+			fmt.Printf("=== Response Body #%d ===\n", i+1)
+			data, err := encodePayload(gen.WithContext(generator.ContextResponse), responseSchema, format)
+			if err != nil {
+				return fmt.Errorf("failed to generate response body: %w", err)
+			}
+			fmt.Println(string(data))
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
+
+// printSchema prints a schema with its references inlined under a section header, as
+// JSON or as YAML to match the payloads; nil schemas print nothing
+func printSchema(doc *openapi3.T, title string, schema *openapi3.Schema, format string) error {
+	if schema == nil {
+		return nil
+	}
+	data, err := parser.DereferenceSchema(doc, schema)
+	if err != nil {
+		return fmt.Errorf("failed to dereference %s: %w", strings.ToLower(title), err)
+	}
+	if format == "yaml" {
+		if data, err = parser.JSONToYAML(data); err != nil {
+			return err
+		}
+		data = bytes.TrimRight(data, "\n")
+	}
+	fmt.Printf("=== %s ===\n", title)
+	fmt.Println(string(data))
+	fmt.Println()
+	return nil
+}
+
+// generateComponent prints count samples of a named component schema, listing the
+// available names when it doesn't exist; showSchema prints the dereferenced schema first
+func generateComponent(doc *openapi3.T, name string, seed int64, count int, opts generator.GenerateOptions, format string, showSchema bool) error {
+	name = strings.TrimPrefix(name, "#/components/schemas/")
+	var schemas openapi3.Schemas
+	if doc.Components != nil {
+		schemas = doc.Components.Schemas
+	}
+	ref, ok := schemas[name]
+	if !ok || ref == nil || ref.Value == nil {
+		names := make([]string, 0, len(schemas))
+		for available := range schemas {
+			names = append(names, available)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("schema %s not found: the spec declares no component schemas", name)
+		}
+		return fmt.Errorf("schema %s not found (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	fmt.Printf("Generating %d payload(s) for schema %s (seed: %d)\n\n", count, name, seed)
+
+	if showSchema {
+		if err := printSchema(doc, name+" Schema", ref.Value, format); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		gen := generator.NewGeneratorWithOptions(seed+int64(i), opts)
+		fmt.Printf("=== %s #%d ===\n", name, i+1)
+		data, err := encodePayload(gen, ref.Value, format)
+		if err != nil {
+			return fmt.Errorf("failed to generate schema %s: %w", name, err)
+		}
+		fmt.Println(string(data))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// encodePayload generates a payload for schema as indented JSON or as YAML; the
+// protobuf format is JSON too, shaped by the generator's ProtoJSON option
+func encodePayload(gen *generator.Generator, schema *openapi3.Schema, format string) ([]byte, error) {
+	data, err := gen.GenerateJSONIndent(schema)
+	if err != nil || format != "yaml" {
+		return data, err
+	}
+	data, err = parser.JSONToYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(data, "\n"), nil
+}
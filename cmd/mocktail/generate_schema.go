@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+func newGenerateSchemaCmd() *cobra.Command {
+	var (
+		seedValue string
+		count     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate-schema <jsonschema-file>",
+		Short: "Generate payloads from a standalone JSON Schema",
+		Long: `Generate realistic payloads from a bare JSON Schema document (JSON or YAML).
+
+Use this when you have a data model but not a full OpenAPI spec. Local references
+into "definitions" or "$defs" are resolved.
+
+Examples:
+  # Generate one payload
+  mocktail generate-schema order.schema.json --seed 42
+
+  # Generate several payloads
+  mocktail generate-schema order.schema.json --count 3`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema, err := parser.LoadJSONSchema(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
+
+			// Use current time as default seed if not specified
+			seed := time.Now().UnixNano()
+			if seedValue != "" {
+				seed = generator.ParseSeed(seedValue)
+			}
+
+			for i := 0; i < count; i++ {
+				gen := generator.NewGenerator(seed + int64(i))
+				jsonData, err := gen.GenerateJSONIndent(schema)
+				if err != nil {
+					return fmt.Errorf("failed to generate payload: %w", err)
+				}
+				fmt.Println(string(jsonData))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&seedValue, "seed", "s", "", "Random seed for reproducible output, a number or any string (default: current time)")
+	cmd.Flags().IntVarP(&count, "count", "c", 1, "Number of payloads to generate")
+
+	return cmd
+}
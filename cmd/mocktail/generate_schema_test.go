@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSchemaCommand(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "user.yaml")
+	content := `type: object
+required: [id, email]
+properties:
+  id:
+    type: integer
+  email:
+    type: string
+    format: email
+`
+	if err := os.WriteFile(schemaFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"generate-schema", schemaFile, "--seed", "42"})
+	err := rootCmd.Execute()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	if err != nil {
+		t.Fatalf("generate-schema failed: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if _, ok := payload["id"]; !ok {
+		t.Errorf("Expected 'id' in payload, got %v", payload)
+	}
+	if _, ok := payload["email"]; !ok {
+		t.Errorf("Expected 'email' in payload, got %v", payload)
+	}
+}
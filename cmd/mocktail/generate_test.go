@@ -3,13 +3,19 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerateCommand(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	// Create a temporary OpenAPI schema file
 	tmpDir := t.TempDir()
 	schemaFile := filepath.Join(tmpDir, "test-schema.yaml")
@@ -39,6 +45,7 @@ paths:
                       type: string
     post:
       summary: Create item
+      operationId: createItem
       requestBody:
         required: true
         content:
@@ -46,8 +53,13 @@ paths:
             schema:
               type: object
               required:
+                - id
                 - name
               properties:
+                id:
+                  type: string
+                  format: uuid
+                  readOnly: true
                 name:
                   type: string
                 count:
@@ -124,6 +136,12 @@ paths:
 				if !strings.Contains(output, "name") {
 					t.Error("Expected 'name' field in output")
 				}
+
+				// readOnly fields must not appear in the request body
+				request := output[strings.Index(output, "Request Body"):strings.Index(output, "Response Body")]
+				if strings.Contains(request, `"id"`) {
+					t.Errorf("Expected readOnly 'id' to be omitted from request body, got:\n%s", request)
+				}
 			},
 		},
 		{
@@ -142,8 +160,45 @@ paths:
 			expectError: true,
 		},
 		{
-			name:        "missing method flag",
-			args:        []string{"generate", schemaFile, "--path", "/items"},
+			name: "all methods of a path",
+			args: []string{"generate", schemaFile, "--path", "/items", "--seed", "42"},
+			validateFunc: func(t *testing.T, output string) {
+				if !strings.Contains(output, "for GET /items") || !strings.Contains(output, "for POST /items") {
+					t.Errorf("Expected both GET and POST sections, got:\n%s", output)
+				}
+			},
+		},
+		{
+			name: "all operations",
+			args: []string{"generate", schemaFile, "--all", "--seed", "42"},
+			validateFunc: func(t *testing.T, output string) {
+				if strings.Count(output, "Generating 1 payload(s)") != 2 {
+					t.Errorf("Expected one section per operation, got:\n%s", output)
+				}
+			},
+		},
+		{
+			name:        "all combined with path",
+			args:        []string{"generate", schemaFile, "--all", "--path", "/items"},
+			expectError: true,
+		},
+		{
+			name: "select by operationId",
+			args: []string{"generate", schemaFile, "--operation", "createItem", "--seed", "42"},
+			validateFunc: func(t *testing.T, output string) {
+				if !strings.Contains(output, "for POST /items") || strings.Contains(output, "for GET /items") {
+					t.Errorf("Expected only the POST /items section, got:\n%s", output)
+				}
+			},
+		},
+		{
+			name:        "unknown operationId",
+			args:        []string{"generate", schemaFile, "--operation", "deleteItem"},
+			expectError: true,
+		},
+		{
+			name:        "operation combined with path",
+			args:        []string{"generate", schemaFile, "--operation", "createItem", "--path", "/items"},
 			expectError: true,
 		},
 		{
@@ -206,6 +261,8 @@ paths:
 }
 
 func TestGenerateCommandReproducibility(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	// Create a temporary OpenAPI schema file
 	tmpDir := t.TempDir()
 	schemaFile := filepath.Join(tmpDir, "test-schema.yaml")
@@ -283,3 +340,245 @@ paths:
 		}
 	}
 }
+
+func TestGenerateCommandYAMLFormat(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	schemaFile := filepath.Join(t.TempDir(), "test-schema.yaml")
+	schemaContent := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /data:
+    get:
+      responses:
+        '200':
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  value:
+                    type: string
+                  number:
+                    type: integer
+                  tags:
+                    type: array
+                    items:
+                      type: string
+`
+	if err := os.WriteFile(schemaFile, []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+
+	// responseBody runs generate and decodes the payload after the response header
+	responseBody := func(format string) interface{} {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		rootCmd := newRootCmd()
+		rootCmd.SetArgs([]string{"generate", schemaFile, "--path", "/data", "--method", "GET", "--seed", "12345", "--format", format})
+		err := rootCmd.Execute()
+
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("Execution failed: %v", err)
+		}
+
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		output := buf.String()
+		body := output[strings.Index(output, "===\n")+4:]
+
+		var data interface{}
+		if format == "yaml" {
+			if err := yaml.Unmarshal([]byte(body), &data); err != nil {
+				t.Fatalf("Output is not valid YAML: %v\n%s", err, body)
+			}
+			// Round-trip through JSON so numbers compare as float64
+			encoded, _ := json.Marshal(data)
+			json.Unmarshal(encoded, &data)
+		} else if err := json.Unmarshal([]byte(body), &data); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, body)
+		}
+		return data
+	}
+
+	jsonData := responseBody("json")
+	yamlData := responseBody("yaml")
+	if !reflect.DeepEqual(jsonData, yamlData) {
+		t.Errorf("Expected YAML output to match JSON output\nJSON: %v\nYAML: %v", jsonData, yamlData)
+	}
+}
+
+func TestGenerateCommandBaseURL(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"generate", "../../examples/petstore.yaml", "--path", "/pets", "--method", "GET", "--seed", "1", "--base-url", "https://staging.example.com/v1/"})
+	err := rootCmd.Execute()
+
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	if !strings.Contains(buf.String(), "for GET https://staging.example.com/v1/pets ") {
+		t.Errorf("Expected the payloads labeled with the full URL, got:\n%s", buf.String())
+	}
+
+	rootCmd = newRootCmd()
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"generate", "../../examples/petstore.yaml", "--path", "/pets", "--base-url", "localhost"})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --base-url") {
+		t.Errorf("Expected an invalid base URL error, got %v", err)
+	}
+}
+
+func TestGenerateCommandNoPaths(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	schemaFile := filepath.Join(t.TempDir(), "empty.yaml")
+	schemaContent := `openapi: 3.0.0
+info:
+  title: Empty API
+  version: 1.0.0
+paths: {}
+`
+	if err := os.WriteFile(schemaFile, []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"generate", schemaFile, "--all"})
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "declares no paths") {
+		t.Errorf("Expected a no-paths error, got %v", err)
+	}
+}
+
+func TestGenerateCommandComponentSchema(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	// run generates from a component of the petstore and returns stdout
+	run := func(args ...string) (string, error) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs(append([]string{"generate", "../../examples/petstore.yaml"}, args...))
+		err := rootCmd.Execute()
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	output, err := run("--schema", "Pet", "--seed", "42")
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+	if !strings.Contains(output, "=== Pet #1 ===") {
+		t.Fatalf("Expected a Pet payload header, got:\n%s", output)
+	}
+
+	var pet map[string]interface{}
+	body := output[strings.Index(output, "===\n")+4:]
+	if err := json.Unmarshal([]byte(body), &pet); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, body)
+	}
+	for _, field := range []string{"id", "name", "species"} {
+		if _, ok := pet[field]; !ok {
+			t.Errorf("Expected required field %q in %v", field, pet)
+		}
+	}
+
+	again, _ := run("--schema", "#/components/schemas/Pet", "--seed", "42")
+	if again != output {
+		t.Errorf("Expected the same seed and a $ref-style name to reproduce the payload")
+	}
+
+	_, err = run("--schema", "Owner")
+	if err == nil || !strings.Contains(err.Error(), "schema Owner not found (available: ") || !strings.Contains(err.Error(), "Pet") {
+		t.Errorf("Expected a not-found error listing available schemas, got %v", err)
+	}
+
+	_, err = run("--schema", "Pet", "--path", "/pets")
+	if err == nil || !strings.Contains(err.Error(), "--schema cannot be combined") {
+		t.Errorf("Expected --schema with --path to be rejected, got %v", err)
+	}
+}
+
+func TestGenerateCommandShowSchema(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	// run generates GET /pets from the petstore and returns stdout
+	run := func(args ...string) (string, error) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs(append([]string{"generate", "../../examples/petstore.yaml", "--path", "/pets", "--method", "GET", "--seed", "42"}, args...))
+		err := rootCmd.Execute()
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	output, err := run()
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+	if strings.Contains(output, "Schema ===") {
+		t.Errorf("Expected no schema section by default, got:\n%s", output)
+	}
+
+	output, err = run("--show-schema")
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+	start := strings.Index(output, "=== Response Schema ===\n")
+	end := strings.Index(output, "=== Response Body #1 ===")
+	if start < 0 || end < start {
+		t.Fatalf("Expected a response schema section before the payload, got:\n%s", output)
+	}
+
+	var schema map[string]interface{}
+	section := output[start+len("=== Response Schema ===\n") : end]
+	if err := json.Unmarshal([]byte(section), &schema); err != nil {
+		t.Fatalf("Schema section is not valid JSON: %v\n%s", err, section)
+	}
+	if strings.Contains(section, "$ref") {
+		t.Errorf("Expected references to be inlined, got:\n%s", section)
+	}
+	items, _ := schema["items"].(map[string]interface{})
+	if _, ok := items["properties"]; !ok {
+		t.Errorf("Expected the Pet item schema to be inlined, got %v", schema)
+	}
+}
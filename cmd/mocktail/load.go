@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/load"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+func newLoadCmd() *cobra.Command {
+	var (
+		path         string
+		method       string
+		rps          int
+		duration     time.Duration
+		schemaFile   string
+		seedValue    string
+		maxErrorRate float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "load <base-url>",
+		Short: "Fire requests at a server and report latency percentiles",
+		Long: `Send requests at a constant rate to a running mock (or any server) and report
+latency percentiles and error counts.
+
+When --schema is given, request bodies for POST/PUT/PATCH are generated from the
+operation's request schema. The command exits non-zero if the error rate exceeds
+--max-error-rate.
+
+Examples:
+  # Smoke-test a running mock for 30 seconds
+  mocktail load http://localhost:8080 --path /pets --rps 100 --duration 30s
+
+  # Send generated request bodies
+  mocktail load http://localhost:8080 --path /pets --method POST --schema examples/petstore.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			baseURL := args[0]
+			method = strings.ToUpper(method)
+
+			if path == "" {
+				return fmt.Errorf("--path flag is required")
+			}
+
+			cfg := load.Config{
+				BaseURL:  baseURL,
+				Path:     path,
+				Method:   method,
+				RPS:      rps,
+				Duration: duration,
+			}
+
+			if schemaFile != "" {
+				seed := time.Now().UnixNano()
+				if seedValue != "" {
+					seed = generator.ParseSeed(seedValue)
+				}
+				body, err := loadRequestBody(schemaFile, path, method, seed)
+				if err != nil {
+					return err
+				}
+				cfg.Body = body
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			fmt.Printf("🚀 Sending %s %s%s at %d req/s for %v\n\n", method, baseURL, path, rps, duration)
+			result, err := load.Run(ctx, cfg)
+			if err != nil {
+				return fmt.Errorf("load run failed: %w", err)
+			}
+
+			fmt.Printf("Requests:   %d (%.1f req/s)\n", result.Requests, float64(result.Requests)/result.Elapsed.Seconds())
+			fmt.Printf("Errors:     %d (%.2f%%)\n", result.Errors, result.ErrorRate()*100)
+			fmt.Printf("Latency p50: %v\n", result.Percentile(50))
+			fmt.Printf("Latency p90: %v\n", result.Percentile(90))
+			fmt.Printf("Latency p99: %v\n", result.Percentile(99))
+			fmt.Printf("Latency max: %v\n", result.Percentile(100))
+
+			if result.ErrorRate() > maxErrorRate {
+				return fmt.Errorf("error rate %.2f%% exceeds threshold %.2f%%", result.ErrorRate()*100, maxErrorRate*100)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "path", "p", "", "Request path (e.g., /pets)")
+	cmd.Flags().StringVarP(&method, "method", "m", "GET", "HTTP method")
+	cmd.Flags().IntVar(&rps, "rps", 10, "Requests per second")
+	cmd.Flags().DurationVarP(&duration, "duration", "d", 10*time.Second, "How long to send requests")
+	cmd.Flags().StringVar(&schemaFile, "schema", "", "OpenAPI schema used to generate request bodies")
+	cmd.Flags().StringVarP(&seedValue, "seed", "s", "", "Random seed for generated bodies, a number or any string (default: current time)")
+	cmd.Flags().Float64Var(&maxErrorRate, "max-error-rate", 0.01, "Fail if the error rate exceeds this fraction")
+
+	return cmd
+}
+
+// loadRequestBody returns a body factory that generates a fresh request payload per call
+func loadRequestBody(schemaFile, path, method string, seed int64) (func() ([]byte, error), error) {
+	schema, err := parser.NewOpenAPIParser().Parse(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	doc, ok := schema.Raw.(*openapi3.T)
+	if !ok {
+		return nil, fmt.Errorf("invalid schema format")
+	}
+
+	// --path is the URL being fired, so match it against the templates
+	method = strings.ToUpper(method)
+	endpoint, ok := schema.FindEndpoint(method, path)
+	if !ok {
+		return nil, fmt.Errorf("no operation matches %s %s", method, path)
+	}
+
+	requestSchema := validator.RequestSchema(doc.Paths.Find(endpoint.Path).GetOperation(endpoint.Method))
+	if requestSchema == nil {
+		return nil, nil
+	}
+
+	gen := generator.NewGenerator(seed).WithContext(generator.ContextRequest)
+
+	// The generator isn't goroutine-safe, so serialize body generation
+	var mu sync.Mutex
+	return func() ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return gen.GenerateJSON(requestSchema)
+	}, nil
+}
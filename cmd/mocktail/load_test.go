@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLoadRequestBody(t *testing.T) {
+	// --path is the concrete URL being fired, and --method may be lowercase
+	body, err := loadRequestBody("../../examples/petstore.yaml", "/pets/42", "put", 1)
+	if err != nil {
+		t.Fatalf("loadRequestBody failed: %v", err)
+	}
+	if body == nil {
+		t.Fatal("Expected a body factory for PUT /pets/{petId}")
+	}
+	data, err := body()
+	if err != nil {
+		t.Fatalf("Failed to generate body: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Errorf("Expected a JSON body, got %s", data)
+	}
+
+	if _, err := loadRequestBody("../../examples/petstore.yaml", "/owners/1", "GET", 1); err == nil {
+		t.Error("Expected an error for a path outside the schema")
+	}
+}
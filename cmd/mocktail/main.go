@@ -37,6 +37,14 @@ tests for your CI. It then watches traffic to detect breaking changes before the
 	rootCmd.AddCommand(newParseCmd())
 	rootCmd.AddCommand(newMockCmd())
 	rootCmd.AddCommand(newGenerateCmd())
+	rootCmd.AddCommand(newGenerateSchemaCmd())
+	rootCmd.AddCommand(newScaffoldCmd())
+	rootCmd.AddCommand(newLoadCmd())
+	rootCmd.AddCommand(newVerifyCmd())
+	rootCmd.AddCommand(newValidateRequestCmd())
+	rootCmd.AddCommand(newBundleCmd())
+	rootCmd.AddCommand(newDriftCmd())
+	rootCmd.AddCommand(newCoverageCmd())
 	// rootCmd.AddCommand(newMonitorCmd())
 
 	return rootCmd
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"os"
 	"strings"
 	"testing"
 )
@@ -26,3 +27,18 @@ func TestVersionConstant(t *testing.T) {
 		t.Error("Version should not be empty")
 	}
 }
+
+// discardStdout points os.Stdout at the null device until the test ends
+func discardStdout(t *testing.T) {
+	t.Helper()
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = devNull
+	t.Cleanup(func() {
+		os.Stdout = oldStdout
+		devNull.Close()
+	})
+}
@@ -3,19 +3,71 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/Vooblin/mocktail/internal/generator"
 	"github.com/Vooblin/mocktail/internal/mock"
 	"github.com/Vooblin/mocktail/internal/parser"
 	"github.com/spf13/cobra"
 )
 
 func newMockCmd() *cobra.Command {
-	var port int
+	var (
+		port              int
+		portRange         string
+		failOnUnknownPath bool
+		varyResponses     bool
+		validateRequests  bool
+		validateParams    bool
+		strictContentType bool
+		strict            bool
+		noSpecEndpoint    bool
+		docs              bool
+		configFile        string
+		latency           time.Duration
+		latencyModel      string
+		latencyJitter     time.Duration
+		replayFile        string
+		speed             float64
+		throughput        int
+		minBodySize       int
+		binarySize        int
+		useDefaults       bool
+		preferExamples    bool
+		mergeAnyOf        bool
+		coverEnums        bool
+		jsonSafeIntegers  bool
+		consistentRefs    bool
+		format            string
+		listSize          string
+		streamLists       int
+		rateLimit         string
+		seedValue         string
+		seedHeader        string
+		seedParam         string
+		noValidate        bool
+		recordFile        string
+		manifestFile      string
+		noRedact          bool
+		deprecatedGone    bool
+		cacheHeaders      bool
+		dryRun            bool
+		stateful          bool
+		maxBodySize       int64
+		mountSpecs        []string
+		proxyURL          string
+		insecure          bool
+		headers           []string
+		responseHeaders   []string
+		quiet             bool
+		logLevel          string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "mock <schema-file>",
@@ -23,50 +75,317 @@ func newMockCmd() *cobra.Command {
 		Long: `Start a mock API server that serves responses based on an OpenAPI or GraphQL schema.
 
 The server will parse the schema and automatically create endpoints with realistic mock responses.
-Press Ctrl+C to stop the server.`,
+Send SIGHUP to re-read the schema files without restarting; press Ctrl+C to stop the server.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			schemaFile := args[0]
 
-			// Validate file exists
-			if _, err := os.Stat(schemaFile); os.IsNotExist(err) {
-				return fmt.Errorf("schema file not found: %s", schemaFile)
+			level, err := mock.ParseLogLevel(logLevel)
+			if err != nil {
+				return err
 			}
+			if quiet {
+				if cmd.Flags().Changed("log-level") {
+					return fmt.Errorf("--quiet cannot be combined with --log-level")
+				}
+				level = mock.LogError
+			}
+			logger := mock.NewLogger(os.Stderr, level)
 
-			// Parse the schema
-			fmt.Printf("📖 Parsing schema: %s\n", schemaFile)
-			p := parser.NewOpenAPIParser()
-			schema, err := p.Parse(schemaFile)
+			// Parse the root schema and any mounted ones, detecting each file's format
+			opts := parser.ParseOptions{SkipValidation: noValidate}
+			mounts, err := parseMounts(schemaFile, mountSpecs, opts, logger)
 			if err != nil {
-				return fmt.Errorf("failed to parse schema: %w", err)
+				return err
+			}
+
+			var config *mock.Config
+			if configFile != "" {
+				config, err = mock.LoadConfig(configFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			if format != "json" && format != "protobuf" {
+				return fmt.Errorf("unsupported format %q (use json or protobuf)", format)
+			}
+
+			model, err := mock.ParseLatencyModel(latencyModel)
+			if err != nil {
+				return err
+			}
+
+			var listRange *mock.ListSize
+			if listSize != "" {
+				parsed, err := mock.ParseListSize(listSize)
+				if err != nil {
+					return err
+				}
+				listRange = &parsed
+			}
+
+			var seed *int64
+			if seedValue != "" {
+				parsed := generator.ParseSeed(seedValue)
+				seed = &parsed
+			}
+
+			var limit *mock.RateLimit
+			if rateLimit != "" {
+				parsed, err := mock.ParseRateLimit(rateLimit)
+				if err != nil {
+					return err
+				}
+				limit = &parsed
+			}
+
+			var ports *mock.PortRange
+			if portRange != "" {
+				if cmd.Flags().Changed("port") {
+					return fmt.Errorf("--port-range cannot be combined with --port")
+				}
+				parsed, err := mock.ParsePortRange(portRange)
+				if err != nil {
+					return err
+				}
+				ports = &parsed
+			}
+
+			var proxy *url.URL
+			if proxyURL != "" {
+				if proxy, err = url.Parse(proxyURL); err != nil || proxy.Scheme == "" || proxy.Host == "" {
+					return fmt.Errorf("invalid --proxy URL %q: expected e.g. https://staging.example.com", proxyURL)
+				}
+			}
+			proxyHeaders, err := mock.ParseHeaders(headers)
+			if err != nil {
+				return err
+			}
+			if len(proxyHeaders) > 0 && proxy == nil {
+				return fmt.Errorf("--header needs --proxy")
+			}
+			if insecure && proxy == nil {
+				return fmt.Errorf("--insecure needs --proxy")
+			}
+			if streamLists > 0 && cacheHeaders {
+				// ETags hash the whole body, which a streamed list never holds
+				return fmt.Errorf("--stream-lists cannot be combined with --cache-headers")
+			}
+
+			extraHeaders, err := mock.ParseHeaders(responseHeaders)
+			if err != nil {
+				return err
+			}
+
+			var replay []mock.RecordedExchange
+			if replayFile != "" {
+				if speed <= 0 {
+					return fmt.Errorf("--speed must be positive, got %g", speed)
+				}
+				f, err := os.Open(replayFile)
+				if err != nil {
+					return fmt.Errorf("failed to open recording: %w", err)
+				}
+				replay, err = mock.ReadRecording(f)
+				f.Close()
+				if err != nil {
+					return err
+				}
+			} else if cmd.Flags().Changed("speed") {
+				return fmt.Errorf("--speed needs --replay-latency")
+			}
+
+			var record io.Writer
+			if recordFile != "" {
+				f, err := os.OpenFile(recordFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					return fmt.Errorf("failed to open recording file: %w", err)
+				}
+				defer f.Close()
+				record = f
 			}
 
 			// Create and start the mock server
-			server := mock.NewServer(schema, port)
+			server := mock.NewServerWithMounts(mounts, port, mock.Options{
+				Logger:              logger,
+				Record:              record,
+				Manifest:            manifestFile,
+				NoRedact:            noRedact,
+				Proxy:               proxy,
+				ProxyHeaders:        proxyHeaders,
+				ProxyInsecure:       insecure,
+				Config:              config,
+				Latency:             latency,
+				LatencyModel:        model,
+				LatencyJitter:       latencyJitter,
+				ReplayLatency:       replay,
+				ReplaySpeed:         speed,
+				Throughput:          throughput,
+				DeprecatedGone:      deprecatedGone,
+				CacheHeaders:        cacheHeaders,
+				Stateful:            stateful,
+				MaxBodySize:         maxBodySize,
+				MinBodySize:         minBodySize,
+				BinarySize:          binarySize,
+				UseDefaults:         useDefaults,
+				PreferExamples:      preferExamples,
+				MergeAnyOf:          mergeAnyOf,
+				CoverEnums:          coverEnums,
+				JSONSafeIntegers:    jsonSafeIntegers,
+				ConsistentRefs:      consistentRefs,
+				ProtoJSON:           format == "protobuf",
+				ListSize:            listRange,
+				StreamLists:         streamLists,
+				PortRange:           ports,
+				ResponseHeaders:     extraHeaders,
+				RateLimit:           limit,
+				Seed:                seed,
+				SeedHeader:          seedHeader,
+				SeedParam:           seedParam,
+				FailOnUnknownPath:   failOnUnknownPath,
+				VaryResponses:       varyResponses,
+				ValidateRequests:    validateRequests,
+				ValidateParams:      validateParams,
+				StrictContentType:   strictContentType,
+				Strict:              strict,
+				DisableSpecEndpoint: noSpecEndpoint,
+				Docs:                docs,
+			})
 
-			// Handle graceful shutdown
+			if dryRun {
+				// Generation failures aren't usage mistakes
+				cmd.SilenceUsage = true
+				return server.DryRun(cmd.OutOrStdout())
+			}
+
+			// Handle graceful shutdown; SIGHUP reloads the schemas instead
 			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+			defer signal.Stop(sigChan)
 
 			errChan := make(chan error, 1)
 			go func() {
 				errChan <- server.Start()
 			}()
 
-			// Wait for interrupt or error
-			select {
-			case sig := <-sigChan:
-				log.Printf("\n📦 Received signal: %v", sig)
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				defer cancel()
-				return server.Stop(ctx)
-			case err := <-errChan:
-				return err
+			// Wait for interrupt or error, reloading on every SIGHUP
+			for {
+				select {
+				case sig := <-sigChan:
+					if sig == syscall.SIGHUP {
+						logger.Infof("🔄 Received %v, reloading %s", sig, schemaFile)
+						reloaded, err := parseMounts(schemaFile, mountSpecs, opts, logger)
+						if err != nil {
+							logger.Errorf("❌ Reload failed, still serving the previous schema: %v", err)
+							continue
+						}
+						server.Reload(reloaded)
+						continue
+					}
+
+					logger.Infof("\n📦 Received signal: %v", sig)
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					return server.Stop(ctx)
+				case err := <-errChan:
+					return err
+				}
 			}
 		},
 	}
 
 	cmd.Flags().IntVarP(&port, "port", "p", 8080, "Port to run the mock server on")
+	cmd.Flags().StringVar(&portRange, "port-range", "", "Listen on the first free port in this range, e.g. 8080-8090 (instead of --port)")
+	cmd.Flags().BoolVar(&failOnUnknownPath, "fail-on-unknown-path", false, "Return a JSON 404 for paths not in the schema and record them at /__mocktail/unknown-paths")
+	cmd.Flags().BoolVar(&varyResponses, "vary-responses", false, "Randomly pick among an operation's declared 2xx responses")
+	cmd.Flags().DurationVar(&latency, "latency", 0, "Delay added before every response (e.g., 200ms); per-path overrides go in --config")
+	cmd.Flags().StringVar(&latencyModel, "latency-model", "fixed", "Latency components, comma-separated: fixed, random (adds up to --latency-jitter), size (adds body size / --throughput)")
+	cmd.Flags().DurationVar(&latencyJitter, "latency-jitter", 0, "Upper bound of the random latency model's extra delay")
+	cmd.Flags().StringVar(&replayFile, "replay-latency", "", "Delay each response as long as the next recorded response of its route took, from a --record JSONL file")
+	cmd.Flags().Float64Var(&speed, "speed", 1, "Multiplier of replayed durations, e.g. 0.5 to replay twice as fast")
+	cmd.Flags().IntVar(&throughput, "throughput", 1<<20, "Simulated bandwidth in bytes per second for the size latency model")
+	cmd.Flags().IntVar(&minBodySize, "min-body-size", 0, "Pad JSON object responses to at least this many bytes (bandwidth testing)")
+	cmd.Flags().StringVarP(&seedValue, "seed", "s", "", "Base seed for response data, a number or any string; each request derives its own from method and path (default: current time)")
+	cmd.Flags().StringVar(&seedHeader, "seed-from-request", "", "Seed each request carrying this header from its value, so retries with the same key get the same response (bare flag: Idempotency-Key)")
+	cmd.Flags().Lookup("seed-from-request").NoOptDefVal = "Idempotency-Key"
+	cmd.Flags().StringVar(&seedParam, "allow-seed-override", "", "Let a request override the seed with this query parameter, e.g. ?__seed=123, to reproduce a payload (bare flag: __seed)")
+	cmd.Flags().Lookup("allow-seed-override").NoOptDefVal = "__seed"
+	cmd.Flags().StringVar(&listSize, "list-size", "", "Items in collection GET responses, a count or a MIN-MAX range (default 2)")
+	cmd.Flags().IntVar(&streamLists, "stream-lists", 0, "Stream collection GETs returning a top-level array with this many items, encoding each as it is generated")
+	cmd.Flags().BoolVar(&preferExamples, "prefer-examples", true, "Serve a response's media-type example instead of generated data when the spec has one")
+	cmd.Flags().BoolVar(&useDefaults, "use-defaults", false, "Return a schema's declared default instead of random data")
+	cmd.Flags().BoolVar(&mergeAnyOf, "merge-any-of", false, "Merge a random selection of anyOf object branches instead of picking one")
+	cmd.Flags().BoolVar(&coverEnums, "cover-enums", false, "Include every enum member at least once in arrays of enum items")
+	cmd.Flags().BoolVar(&jsonSafeIntegers, "json-safe-integers", false, "Cap generated integers at ±(2^53-1) so JavaScript clients read them exactly")
+	cmd.Flags().BoolVar(&consistentRefs, "consistent-refs", false, "Make <name>Id fields match the id of a sibling or nested <name> object")
+	cmd.Flags().StringVar(&format, "format", "json", "Response data conventions: json, or protobuf for the protobuf JSON mapping gRPC-gateway APIs use")
+	cmd.Flags().IntVar(&binarySize, "binary-size", 1024, "Size in bytes of generated file downloads (octet-stream, images, format: binary)")
+	cmd.Flags().BoolVar(&noValidate, "no-validate", false, "Warn instead of failing when the spec doesn't validate")
+	cmd.Flags().BoolVar(&stateful, "stateful", false, "Remember created and updated resources so later reads return them")
+	cmd.Flags().Int64Var(&maxBodySize, "max-body-size", 1<<20, "Reject request bodies larger than this many bytes with 413 (negative disables the limit)")
+	cmd.Flags().BoolVar(&deprecatedGone, "deprecated-gone", false, "Answer deprecated operations with 410 Gone")
+	cmd.Flags().StringVar(&rateLimit, "rate-limit", "", "Answer each client IP's requests beyond this rate with 429 and Retry-After, e.g. 10/s or 100/m")
+	cmd.Flags().BoolVar(&cacheHeaders, "cache-headers", false, "Send ETag and Cache-Control on GET responses and answer matching If-None-Match with 304")
+	cmd.Flags().StringVar(&proxyURL, "proxy", "", "Forward every request to this upstream instead of mocking (combine with --record to capture real traffic)")
+	cmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification of the --proxy upstream, e.g. for self-signed staging certificates")
+	cmd.Flags().StringArrayVar(&responseHeaders, "response-header", nil, "Header added to every mock response, e.g. 'X-Env: mock' (repeatable)")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Header added to proxied requests, e.g. 'Authorization: Bearer xxx' (repeatable; redacted in logs)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Generate one response per endpoint, print the routes, and exit without serving")
+	cmd.Flags().BoolVar(&quiet, "quiet", false, "Log only errors: no startup banner or per-request lines (same as --log-level error)")
+	cmd.Flags().StringVar(&logLevel, "log-level", "info", "Least severe messages to log: info (startup and every request), warn, error, or silent")
+	cmd.Flags().StringVar(&recordFile, "record", "", "Append each request and response to this JSONL file")
+	cmd.Flags().StringVar(&manifestFile, "manifest", "", "Write a JSON manifest of the served routes (method, path, statuses, validation) to this file on start")
+	cmd.Flags().BoolVar(&noRedact, "no-redact", false, "Record writeOnly and password request fields as sent instead of \"***\"")
+	cmd.Flags().StringVar(&configFile, "config", "", "YAML config file with per-endpoint overrides")
+	cmd.Flags().StringArrayVar(&mountSpecs, "mount", nil, "Also serve another schema (OpenAPI or GraphQL) under a prefix, as /prefix=file; repeatable")
+	cmd.Flags().BoolVar(&validateRequests, "validate-requests", false, "Reject request bodies that don't match the schema with a 400")
+	cmd.Flags().BoolVar(&validateParams, "validate-params", false, "Reject path parameters that don't match their schema (e.g. outside an enum) with a 400")
+	cmd.Flags().BoolVar(&strictContentType, "strict-content-type", false, "Reject request bodies whose Content-Type isn't a declared request media type with a 415")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Answer with a 500 detailing the error when a response can't be generated, instead of a placeholder body")
+	cmd.Flags().BoolVar(&noSpecEndpoint, "no-spec-endpoint", false, "Don't serve the loaded spec at /openapi.json and /openapi.yaml")
+	cmd.Flags().BoolVar(&docs, "docs", false, "Serve interactive Swagger UI docs for the spec at /docs")
 
 	return cmd
 }
+
+// parseMounts parses the root schema and each --mount schema, as /prefix=file
+func parseMounts(schemaFile string, mountSpecs []string, opts parser.ParseOptions, logger *mock.Logger) ([]mock.Mount, error) {
+	schema, err := parseSchemaFile(schemaFile, opts, logger)
+	if err != nil {
+		return nil, err
+	}
+	mounts := []mock.Mount{{Schema: schema}}
+
+	for _, spec := range mountSpecs {
+		prefix, file, ok := strings.Cut(spec, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") || prefix == "/" {
+			return nil, fmt.Errorf("invalid --mount %q (expected /prefix=schema-file)", spec)
+		}
+		mounted, err := parseSchemaFile(file, opts, logger)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, mock.Mount{Prefix: strings.TrimSuffix(prefix, "/"), Schema: mounted})
+	}
+	return mounts, nil
+}
+
+// parseSchemaFile parses a schema with the parser matching its format, announcing it
+// unless the logger is quieter than info
+func parseSchemaFile(schemaFile string, opts parser.ParseOptions, logger *mock.Logger) (*parser.Schema, error) {
+	if _, err := os.Stat(schemaFile); os.IsNotExist(err) {
+		return nil, fmt.Errorf("schema file not found: %s", schemaFile)
+	}
+
+	logger.Infof("📖 Parsing schema: %s", schemaFile)
+	p, err := parser.Detect(schemaFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	schema, err := p.Parse(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return schema, nil
+}
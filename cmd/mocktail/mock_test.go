@@ -1,8 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestMockCommand(t *testing.T) {
@@ -51,3 +60,208 @@ func TestMockCommandRequiresArg(t *testing.T) {
 		t.Errorf("Expected error about missing argument, got: %v", err)
 	}
 }
+
+func TestMockCommandDryRun(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	tests := []struct {
+		name        string
+		schema      string
+		expectError bool
+		expected    []string
+	}{
+		{
+			name: "every route printed",
+			schema: `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+    delete:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Deleted
+`,
+			expected: []string{"GET     /items →", "DELETE  /items/{id} →", "GET     /items/{id} →", "3 endpoints, 0 failed"},
+		},
+		{
+			name: "unsatisfiable schema",
+			schema: `openapi: 3.0.0
+info:
+  title: Broken API
+  version: 1.0.0
+paths:
+  /broken:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                minProperties: 3
+                additionalProperties: false
+                properties:
+                  only:
+                    type: string
+`,
+			expectError: true,
+			expected:    []string{"❌ GET     /broken →", "1 endpoints, 1 failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaFile := filepath.Join(t.TempDir(), "api.yaml")
+			if err := os.WriteFile(schemaFile, []byte(tt.schema), 0644); err != nil {
+				t.Fatalf("Failed to create test schema: %v", err)
+			}
+
+			var out bytes.Buffer
+			rootCmd := newRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"mock", schemaFile, "--dry-run"})
+
+			err := rootCmd.Execute()
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Expected error=%v, got %v\n%s", tt.expectError, err, out.String())
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMockCommandReloadOnSIGHUP(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP isn't delivered on Windows")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	schemaFor := func(path string) string {
+		return fmt.Sprintf(`openapi: 3.0.0
+info:
+  title: Reload API
+  version: 1.0.0
+paths:
+  %s:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+`, path)
+	}
+
+	schemaFile := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(schemaFile, []byte(schemaFor("/items")), 0644); err != nil {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"mock", schemaFile, "--port", "8137"})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rootCmd.Execute()
+	}()
+
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("Failed to find own process: %v", err)
+	}
+	defer func() {
+		http.DefaultClient.CloseIdleConnections()
+		self.Signal(os.Interrupt)
+		if err := <-done; err != nil {
+			t.Errorf("mock command failed: %v", err)
+		}
+	}()
+
+	// status polls a path until it answers with want or the deadline passes
+	status := func(path string, want int) int {
+		got := 0
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			resp, err := http.Get("http://localhost:8137" + path)
+			if err != nil {
+				continue
+			}
+			resp.Body.Close()
+			if got = resp.StatusCode; got == want {
+				break
+			}
+		}
+		return got
+	}
+
+	if got := status("/items", http.StatusOK); got != http.StatusOK {
+		t.Fatalf("Expected /items to be served before the reload, got %d", got)
+	}
+
+	if err := os.WriteFile(schemaFile, []byte(schemaFor("/orders")), 0644); err != nil {
+		t.Fatalf("Failed to update test schema: %v", err)
+	}
+	if err := self.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("Failed to send SIGHUP: %v", err)
+	}
+
+	if got := status("/orders", http.StatusOK); got != http.StatusOK {
+		t.Errorf("Expected /orders to be served after the reload, got %d", got)
+	}
+	if got := status("/items", http.StatusNotFound); got != http.StatusNotFound {
+		t.Errorf("Expected /items to be gone after the reload, got %d", got)
+	}
+
+	// A schema that no longer parses keeps the previous one serving
+	if err := os.WriteFile(schemaFile, []byte("not: [valid"), 0644); err != nil {
+		t.Fatalf("Failed to break test schema: %v", err)
+	}
+	if err := self.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("Failed to send SIGHUP: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := status("/orders", http.StatusOK); got != http.StatusOK {
+		t.Errorf("Expected /orders to keep being served after a failed reload, got %d", got)
+	}
+}
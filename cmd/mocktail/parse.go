@@ -2,13 +2,20 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/Vooblin/mocktail/internal/parser"
 	"github.com/spf13/cobra"
 )
 
 func newParseCmd() *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		noValidate   bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "parse <schema-file>",
@@ -22,31 +29,52 @@ and displays a summary of the parsed content.`,
 			filepath := args[0]
 
 			// Create parser based on file extension or content
-			// For now, we only support OpenAPI
-			parser := parser.NewOpenAPIParser()
+			p, err := parser.Detect(filepath, parser.ParseOptions{SkipValidation: noValidate})
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
 
-			schema, err := parser.Parse(filepath)
+			schema, err := p.Parse(filepath)
 			if err != nil {
 				return fmt.Errorf("failed to parse schema: %w", err)
 			}
 
+			// The route table is printed alone so it can be grepped
+			if outputFormat == "routes" {
+				return printRoutes(schema)
+			}
+
 			// Display summary
 			fmt.Printf("✓ Successfully parsed %s schema\n\n", schema.Type)
 			fmt.Printf("Title:   %s\n", schema.Title)
 			fmt.Printf("Version: %s\n", schema.Version)
 			fmt.Printf("Paths:   %d\n\n", len(schema.Paths))
+			if len(schema.Paths) == 0 {
+				fmt.Printf("⚠️  The schema declares no paths: a mock will only serve /health\n\n")
+			}
 
 			if outputFormat == "verbose" {
 				fmt.Println("Endpoints:")
-				for path, endpoints := range schema.Paths {
-					for _, endpoint := range endpoints {
-						fmt.Printf("  %s %s\n", endpoint.Method, path)
-						if endpoint.Summary != "" {
-							fmt.Printf("    Summary: %s\n", endpoint.Summary)
-						}
-						if len(endpoint.Parameters) > 0 {
-							fmt.Printf("    Parameters: %d\n", len(endpoint.Parameters))
-						}
+				for _, endpoint := range sortedEndpoints(schema) {
+					fmt.Printf("  %s %s\n", endpoint.Method, endpoint.Path)
+					if endpoint.OperationID != "" {
+						fmt.Printf("    OperationId: %s\n", endpoint.OperationID)
+					}
+					if endpoint.Deprecated {
+						fmt.Println("    Deprecated: true")
+					}
+					if endpoint.Summary != "" {
+						fmt.Printf("    Summary: %s\n", endpoint.Summary)
+					}
+					if len(endpoint.Parameters) > 0 {
+						fmt.Printf("    Parameters: %d\n", len(endpoint.Parameters))
+					}
+				}
+
+				if len(schema.Warnings) > 0 {
+					fmt.Println("\nWarnings:")
+					for _, warning := range schema.Warnings {
+						fmt.Printf("  ⚠️  %s\n", warning)
 					}
 				}
 			}
@@ -55,7 +83,43 @@ and displays a summary of the parsed content.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "summary", "Output format (summary|verbose)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "summary", "Output format (summary|verbose|routes)")
+	cmd.Flags().BoolVar(&noValidate, "no-validate", false, "Warn instead of failing when the spec doesn't validate")
 
 	return cmd
 }
+
+// sortedEndpoints lists every endpoint of a schema sorted by path, then method
+func sortedEndpoints(schema *parser.Schema) []parser.Endpoint {
+	var endpoints []parser.Endpoint
+	for _, pathEndpoints := range schema.Paths {
+		endpoints = append(endpoints, pathEndpoints...)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints
+}
+
+// printRoutes prints an aligned table of every endpoint: method, path, operationId,
+// parameter count, and summary
+func printRoutes(schema *parser.Schema) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tOPERATION\tPARAMS\tSUMMARY")
+	for _, endpoint := range sortedEndpoints(schema) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", endpoint.Method, endpoint.Path,
+			orDash(endpoint.OperationID), len(endpoint.Parameters), orDash(strings.Join(strings.Fields(endpoint.Summary), " ")))
+	}
+	return w.Flush()
+}
+
+// orDash returns s, or "-" for an empty cell so columns stay greppable
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
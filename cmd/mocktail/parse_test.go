@@ -1,6 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -26,3 +30,83 @@ func TestParseCommand(t *testing.T) {
 		t.Errorf("Expected shorthand 'o', got '%s'", outputFlag.Shorthand)
 	}
 }
+
+func TestParseCommandRoutes(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	schemaFile := filepath.Join(t.TempDir(), "users.yaml")
+	schemaContent := `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUserById
+      summary: Get a user
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+  /users:
+    post:
+      responses:
+        '201':
+          description: Created
+    get:
+      operationId: listUsers
+      summary: List users
+      responses:
+        '200':
+          description: OK
+`
+	if err := os.WriteFile(schemaFile, []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"parse", schemaFile, "--output", "routes"})
+	err := rootCmd.Execute()
+
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	expected := [][]string{
+		{"METHOD", "PATH", "OPERATION", "PARAMS", "SUMMARY"},
+		{"GET", "/users", "listUsers", "0", "List", "users"},
+		{"POST", "/users", "-", "0", "-"},
+		{"GET", "/users/{id}", "getUserById", "1", "Get", "a", "user"},
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(expected), len(lines), buf.String())
+	}
+	for i, want := range expected {
+		if got := strings.Fields(lines[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("Line %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	// Columns are aligned, so every row's path starts at the same offset
+	pathColumn := strings.Index(lines[0], "PATH")
+	for _, line := range lines[1:] {
+		if line[pathColumn] != '/' {
+			t.Errorf("Expected the path column aligned at %d, got %q", pathColumn, line)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// pathParamPattern matches templated path segments such as {id}
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+func newScaffoldCmd() *cobra.Command {
+	var (
+		paths   []string
+		methods []string
+		title   string
+		baseURL string
+		out     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Generate a minimal OpenAPI stub from a list of endpoints",
+		Long: `Generate a minimal, valid OpenAPI 3 document from a list of endpoints.
+
+Each --path is paired with the --method at the same position. Placeholder schemas are
+filled in per method so the result can be served with 'mocktail mock' right away.
+--base-url becomes the document's server, so clients generated from the stub point at
+the mock, staging, or production with one switch.
+
+Examples:
+  # Scaffold a list and a get-by-id endpoint
+  mocktail scaffold --path /users --method GET --path /users/{id} --method GET --out api.yaml
+
+  # Point the stub at a staging host
+  mocktail scaffold --path /users --method GET --base-url https://staging.example.com/v1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(paths) == 0 {
+				return fmt.Errorf("at least one --path is required")
+			}
+			if len(paths) != len(methods) {
+				return fmt.Errorf("each --path needs a matching --method (got %d paths, %d methods)", len(paths), len(methods))
+			}
+
+			if err := checkBaseURL(baseURL); err != nil {
+				return err
+			}
+
+			doc, err := buildScaffold(title, baseURL, paths, methods)
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(doc); err != nil {
+				return fmt.Errorf("failed to marshal YAML: %w", err)
+			}
+			data := buf.Bytes()
+
+			if out == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(out, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", out, err)
+			}
+			fmt.Printf("✓ Wrote OpenAPI stub with %d endpoint(s) to %s\n", len(paths), out)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&paths, "path", "p", nil, "API path (repeatable, e.g., /users/{id})")
+	cmd.Flags().StringArrayVarP(&methods, "method", "m", nil, "HTTP method for the path at the same position (repeatable)")
+	cmd.Flags().StringVarP(&title, "title", "t", "Scaffolded API", "API title")
+	cmd.Flags().StringVar(&baseURL, "base-url", "http://localhost:8080", "Base URL of the server the stub targets")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
+
+// scaffoldDoc fixes the order of the top-level sections in the emitted YAML
+type scaffoldDoc struct {
+	OpenAPI    string                 `yaml:"openapi"`
+	Info       map[string]interface{} `yaml:"info"`
+	Servers    []map[string]string    `yaml:"servers"`
+	Paths      map[string]interface{} `yaml:"paths"`
+	Components map[string]interface{} `yaml:"components"`
+}
+
+// buildScaffold assembles an OpenAPI document from plain maps so it marshals cleanly to YAML
+func buildScaffold(title, baseURL string, paths, methods []string) (*scaffoldDoc, error) {
+	pathItems := make(map[string]interface{})
+
+	for i, path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("path %s must start with /", path)
+		}
+
+		method := strings.ToLower(methods[i])
+		operation, err := scaffoldOperation(method, path)
+		if err != nil {
+			return nil, err
+		}
+
+		pathItem, ok := pathItems[path].(map[string]interface{})
+		if !ok {
+			pathItem = make(map[string]interface{})
+			pathItems[path] = pathItem
+		}
+		if _, exists := pathItem[method]; exists {
+			return nil, fmt.Errorf("duplicate endpoint %s %s", strings.ToUpper(method), path)
+		}
+		pathItem[method] = operation
+	}
+
+	return &scaffoldDoc{
+		OpenAPI: "3.0.3",
+		Info: map[string]interface{}{
+			"title":   title,
+			"version": "0.1.0",
+		},
+		Servers: []map[string]string{{"url": strings.TrimSuffix(baseURL, "/")}},
+		Paths:   pathItems,
+		Components: map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Resource": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"id", "name"},
+					"properties": map[string]interface{}{
+						"id":        map[string]interface{}{"type": "string", "format": "uuid"},
+						"name":      map[string]interface{}{"type": "string"},
+						"createdAt": map[string]interface{}{"type": "string", "format": "date-time"},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// checkBaseURL rejects base URLs that lack a scheme or host
+func checkBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid --base-url %q: expected an absolute URL such as http://localhost:8080", baseURL)
+	}
+	return nil
+}
+
+// scaffoldOperation builds a placeholder operation with sensible defaults for the method
+func scaffoldOperation(method, path string) (map[string]interface{}, error) {
+	resourceRef := map[string]interface{}{"$ref": "#/components/schemas/Resource"}
+	jsonBody := func(schema interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		}
+	}
+
+	operation := map[string]interface{}{
+		"summary": fmt.Sprintf("%s %s", strings.ToUpper(method), path),
+	}
+
+	var params []interface{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+
+	isCollection := !strings.Contains(path, "{")
+
+	switch method {
+	case "get":
+		schema := interface{}(resourceRef)
+		if isCollection {
+			schema = map[string]interface{}{"type": "array", "items": resourceRef}
+		}
+		operation["responses"] = map[string]interface{}{
+			"200": map[string]interface{}{"description": "Successful response", "content": jsonBody(schema)},
+		}
+	case "post":
+		operation["requestBody"] = map[string]interface{}{"required": true, "content": jsonBody(resourceRef)}
+		operation["responses"] = map[string]interface{}{
+			"201": map[string]interface{}{"description": "Created", "content": jsonBody(resourceRef)},
+		}
+	case "put", "patch":
+		operation["requestBody"] = map[string]interface{}{"required": true, "content": jsonBody(resourceRef)}
+		operation["responses"] = map[string]interface{}{
+			"200": map[string]interface{}{"description": "Updated", "content": jsonBody(resourceRef)},
+		}
+	case "delete":
+		operation["responses"] = map[string]interface{}{
+			"204": map[string]interface{}{"description": "Deleted"},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported method %s", strings.ToUpper(method))
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestScaffoldCommand(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "api.yaml")
+
+	// Silence the success message
+	discardStdout(t)
+
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{
+		"scaffold",
+		"--path", "/users", "--method", "GET",
+		"--path", "/users", "--method", "POST",
+		"--path", "/users/{id}", "--method", "GET",
+		"--path", "/users/{id}", "--method", "DELETE",
+		"--out", outFile,
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("scaffold failed: %v", err)
+	}
+
+	// The stub must round-trip through the parser
+	schema, err := parser.NewOpenAPIParser().Parse(outFile)
+	if err != nil {
+		t.Fatalf("Scaffolded spec failed to parse: %v", err)
+	}
+
+	if len(schema.Paths["/users"]) != 2 {
+		t.Errorf("Expected 2 endpoints for /users, got %d", len(schema.Paths["/users"]))
+	}
+	if len(schema.Paths["/users/{id}"]) != 2 {
+		t.Errorf("Expected 2 endpoints for /users/{id}, got %d", len(schema.Paths["/users/{id}"]))
+	}
+	for _, endpoint := range schema.Paths["/users/{id}"] {
+		if len(endpoint.Parameters) != 1 || endpoint.Parameters[0].Name != "id" {
+			t.Errorf("Expected %s /users/{id} to declare the id path parameter", endpoint.Method)
+		}
+	}
+}
+
+func TestScaffoldCommandMismatchedFlags(t *testing.T) {
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"scaffold", "--path", "/users", "--path", "/pets", "--method", "GET"})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("Expected error for mismatched --path/--method counts")
+	}
+}
+
+func TestScaffoldCommandBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "default", expected: "http://localhost:8080"},
+		{name: "custom", args: []string{"--base-url", "https://staging.example.com/v1/"}, expected: "https://staging.example.com/v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outFile := filepath.Join(t.TempDir(), "api.yaml")
+
+			discardStdout(t)
+
+			rootCmd := newRootCmd()
+			rootCmd.SetArgs(append([]string{"scaffold", "--path", "/users", "--method", "GET", "--out", outFile}, tt.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("scaffold failed: %v", err)
+			}
+
+			schema, err := parser.NewOpenAPIParser().Parse(outFile)
+			if err != nil {
+				t.Fatalf("Scaffolded spec failed to parse: %v", err)
+			}
+			doc, ok := schema.Raw.(*openapi3.T)
+			if !ok || len(doc.Servers) != 1 || doc.Servers[0].URL != tt.expected {
+				t.Errorf("Expected a single server %s, got %+v", tt.expected, doc.Servers)
+			}
+		})
+	}
+}
+
+func TestScaffoldCommandInvalidBaseURL(t *testing.T) {
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs([]string{"scaffold", "--path", "/users", "--method", "GET", "--base-url", "localhost"})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("Expected error for a base URL without a scheme")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+func newValidateRequestCmd() *cobra.Command {
+	var (
+		path        string
+		method      string
+		bodyFile    string
+		contentType string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate-request <schema-file>",
+		Short: "Check a request body against an endpoint's request schema",
+		Long: `Check whether a JSON payload would be accepted by an endpoint, using the same
+validation as 'mocktail mock --validate-requests', and print any field errors.
+
+The path may be a template (/pets/{petId}) or a concrete URL path (/pets/42). Pass
+--body - to read the payload from stdin; without --body the request has no body. A
+JSON Patch or merge patch --content-type validates the payload as that patch format.
+
+The command exits non-zero if the payload is rejected.
+
+Examples:
+  mocktail validate-request examples/petstore.yaml --path /pets --method POST --body payload.json
+  echo '[{"op":"remove","path":"/tag"}]' | mocktail validate-request spec.yaml \
+    --path /pets/42 --method PATCH --body - --content-type application/json-patch+json`,
+		Args: cobra.ExactArgs(1),
+		// A rejected payload is a validation failure, not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema, err := parser.NewOpenAPIParser().Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
+			doc, ok := schema.Raw.(*openapi3.T)
+			if !ok {
+				return fmt.Errorf("invalid schema format")
+			}
+
+			method = strings.ToUpper(method)
+			endpoint, ok := schema.FindEndpoint(method, path)
+			if !ok {
+				return fmt.Errorf("no operation matches %s %s", method, path)
+			}
+			operation := doc.Paths.Find(endpoint.Path).GetOperation(endpoint.Method)
+
+			body, err := readRequestBody(bodyFile, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			operationName := endpoint.Method + " " + endpoint.Path
+			if err := validator.ValidateRequest(operation, contentType, body); err != nil {
+				fmt.Printf("✗ %s rejects the request\n", operationName)
+				printValidationError(err)
+				return fmt.Errorf("request does not match the schema")
+			}
+			fmt.Printf("✓ %s accepts the request\n", operationName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "path", "", "Request path, as a template or a concrete URL path")
+	cmd.Flags().StringVarP(&method, "method", "m", "POST", "HTTP method")
+	cmd.Flags().StringVar(&bodyFile, "body", "", "File holding the request body (- for stdin)")
+	cmd.Flags().StringVar(&contentType, "content-type", "application/json", "Content-Type the body is sent with")
+	cmd.MarkFlagRequired("path")
+
+	return cmd
+}
+
+// readRequestBody reads the payload from a file, from stdin for "-", or returns none
+func readRequestBody(file string, stdin io.Reader) ([]byte, error) {
+	var (
+		body []byte
+		err  error
+	)
+	switch file {
+	case "":
+		return nil, nil
+	case "-":
+		body, err = io.ReadAll(stdin)
+	default:
+		body, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return body, nil
+}
+
+// printValidationError lists the field errors of a validation failure, one per line
+func printValidationError(err error) {
+	var validationErr *validator.ValidationError
+	if !errors.As(err, &validationErr) {
+		fmt.Printf("    %v\n", err)
+		return
+	}
+	for _, fieldErr := range validationErr.Errors {
+		field := fieldErr.Field
+		if field == "" {
+			field = "(root)"
+		}
+		fmt.Printf("    %s: %s\n", field, fieldErr.Message)
+	}
+}
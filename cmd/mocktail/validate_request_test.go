@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateRequestCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		body    string
+		wantErr string
+	}{
+		{
+			name: "valid payload",
+			args: []string{"--path", "/pets", "--method", "POST"},
+			body: `{"name": "Rex", "species": "dog", "age": 3}`,
+		},
+		{
+			name:    "invalid payload",
+			args:    []string{"--path", "/pets", "--method", "POST"},
+			body:    `{"name": "Rex", "species": "dragon"}`,
+			wantErr: "request does not match the schema",
+		},
+		{
+			name:    "missing required body",
+			args:    []string{"--path", "/pets"},
+			wantErr: "request does not match the schema",
+		},
+		{
+			name: "concrete path",
+			args: []string{"--path", "/pets/3fa85f64-5717-4562-b3fc-2c963f66afa6", "-m", "put"},
+			body: `{"name": "Tom", "species": "cat"}`,
+		},
+		{
+			name:    "unknown operation",
+			args:    []string{"--path", "/owners", "--method", "POST"},
+			body:    `{}`,
+			wantErr: "no operation matches POST /owners",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"validate-request", "../../examples/petstore.yaml"}, tt.args...)
+			if tt.body != "" {
+				bodyFile := filepath.Join(t.TempDir(), "payload.json")
+				if err := os.WriteFile(bodyFile, []byte(tt.body), 0644); err != nil {
+					t.Fatalf("Failed to write payload: %v", err)
+				}
+				args = append(args, "--body", bodyFile)
+			}
+
+			// Silence the report
+			discardStdout(t)
+
+			rootCmd := newRootCmd()
+			rootCmd.SetArgs(args)
+			err := rootCmd.Execute()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected the payload to validate, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestCommandStdin(t *testing.T) {
+	discardStdout(t)
+
+	rootCmd := newRootCmd()
+	rootCmd.SetIn(strings.NewReader(`{"name": "Rex"}`))
+	rootCmd.SetArgs([]string{"validate-request", "../../examples/petstore.yaml", "--path", "/pets", "--body", "-"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("Expected the payload missing species to be rejected")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+// statusSuffix matches the optional ".<status>" before a response file's extension
+var statusSuffix = regexp.MustCompile(`\.(\d{3}|default)$`)
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <schema-file> <responses-dir>",
+		Short: "Validate recorded responses against the schema",
+		Long: `Validate captured API responses against the responses declared in an OpenAPI schema.
+
+Each .json file in the directory holds one response body and is named after its
+operation: the method and the path segments joined by underscores, with parameter
+braces dropped. An optional status code goes before the extension; without one the
+operation's first 2xx response is used.
+
+  GET_pets.json           GET /pets, first 2xx response
+  GET_pets_petId.404.json GET /pets/{petId}, 404 response
+
+The command exits non-zero if any response doesn't match, making it suitable as a
+contract test in CI.`,
+		Args: cobra.ExactArgs(2),
+		// A mismatch is a test failure, not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaFile, dir := args[0], args[1]
+
+			schema, err := parser.NewOpenAPIParser().Parse(schemaFile)
+			if err != nil {
+				return fmt.Errorf("failed to parse schema: %w", err)
+			}
+			doc, ok := schema.Raw.(*openapi3.T)
+			if !ok {
+				return fmt.Errorf("invalid schema format")
+			}
+
+			files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+			if err != nil {
+				return fmt.Errorf("failed to list responses: %w", err)
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no .json response files found in %s", dir)
+			}
+			sort.Strings(files)
+
+			operations := operationsByFileKey(schema)
+			failed := 0
+			for _, file := range files {
+				name := filepath.Base(file)
+				if err := verifyResponseFile(doc, operations, file); err != nil {
+					failed++
+					fmt.Printf("✗ %s\n", name)
+					printValidationError(err)
+					continue
+				}
+				fmt.Printf("✓ %s\n", name)
+			}
+
+			fmt.Printf("\n%d of %d responses match the schema\n", len(files)-failed, len(files))
+			if failed > 0 {
+				return fmt.Errorf("%d response(s) do not match the schema", failed)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// responseFileKey builds the file name stem for an operation, e.g. GET_pets_petId
+func responseFileKey(method, path string) string {
+	parts := []string{strings.ToUpper(method)}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment = strings.Trim(segment, "{}"); segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// operationsByFileKey indexes every endpoint of a schema by its response file key
+func operationsByFileKey(schema *parser.Schema) map[string]parser.Endpoint {
+	operations := make(map[string]parser.Endpoint)
+	for _, endpoints := range schema.Paths {
+		for _, endpoint := range endpoints {
+			operations[responseFileKey(endpoint.Method, endpoint.Path)] = endpoint
+		}
+	}
+	return operations
+}
+
+// verifyResponseFile validates one recorded response against its operation's schema
+func verifyResponseFile(doc *openapi3.T, operations map[string]parser.Endpoint, file string) error {
+	stem := strings.TrimSuffix(filepath.Base(file), ".json")
+	status := ""
+	if match := statusSuffix.FindStringSubmatch(stem); match != nil {
+		status = match[1]
+		stem = strings.TrimSuffix(stem, match[0])
+	}
+
+	endpoint, ok := operations[stem]
+	if !ok {
+		return fmt.Errorf("no operation matches %q", stem)
+	}
+	pathItem := doc.Paths.Find(endpoint.Path)
+	if pathItem == nil {
+		return fmt.Errorf("path %s not found in schema", endpoint.Path)
+	}
+	operation := pathItem.GetOperation(endpoint.Method)
+	if operation == nil {
+		return fmt.Errorf("operation %s %s not found in schema", endpoint.Method, endpoint.Path)
+	}
+
+	if status == "" {
+		status = successStatus(operation)
+	}
+
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	return validator.ValidateResponseBody(operation, status, body)
+}
+
+// successStatus returns the lowest 2xx status an operation declares, or "default"
+func successStatus(operation *openapi3.Operation) string {
+	if operation.Responses == nil {
+		return "default"
+	}
+	var codes []string
+	for code := range operation.Responses.Map() {
+		if len(code) == 3 && code[0] == '2' {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "default"
+	}
+	sort.Strings(codes)
+	return codes[0]
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "matching responses",
+			files: map[string]string{
+				"GET_pets_petId.json": `{"id": "3fa85f64-5717-4562-b3fc-2c963f66afa6", "name": "Rex", "species": "dog"}`,
+				"GET_pets.json":       `[{"id": "3fa85f64-5717-4562-b3fc-2c963f66afa6", "name": "Tom", "species": "cat"}]`,
+			},
+		},
+		{
+			name: "schema mismatch",
+			files: map[string]string{
+				"GET_pets_petId.json": `{"id": "3fa85f64-5717-4562-b3fc-2c963f66afa6", "name": "Rex", "species": "dragon"}`,
+			},
+			wantErr: "1 response(s) do not match",
+		},
+		{
+			name: "unknown operation",
+			files: map[string]string{
+				"GET_owners.json": `{}`,
+			},
+			wantErr: "1 response(s) do not match",
+		},
+		{
+			name: "undeclared status",
+			files: map[string]string{
+				"GET_pets_petId.500.json": `{}`,
+			},
+			wantErr: "1 response(s) do not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write response file: %v", err)
+				}
+			}
+
+			// Silence the per-file report
+			discardStdout(t)
+
+			rootCmd := newRootCmd()
+			rootCmd.SetArgs([]string{"verify", "../../examples/petstore.yaml", dir})
+			err := rootCmd.Execute()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected responses to verify, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResponseFileKey(t *testing.T) {
+	tests := []struct {
+		method, path, expected string
+	}{
+		{method: "GET", path: "/pets", expected: "GET_pets"},
+		{method: "get", path: "/pets/{petId}", expected: "GET_pets_petId"},
+		{method: "DELETE", path: "/", expected: "DELETE"},
+	}
+
+	for _, tt := range tests {
+		if got := responseFileKey(tt.method, tt.path); got != tt.expected {
+			t.Errorf("responseFileKey(%s, %s) = %s, want %s", tt.method, tt.path, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Request is one observed request and the status it was answered with
+type Request struct {
+	Method string
+	Path   string // concrete URL path, e.g. /pets/42
+	Route  string // path template the mock matched, mount prefix included; empty if unknown
+	Status int
+}
+
+// Endpoint is the coverage of one operation declared in the schema
+type Endpoint struct {
+	Method   string
+	Path     string      // path template, e.g. /pets/{petId}
+	Declared []string    // declared response codes, sorted, e.g. 200, 404, default
+	Seen     map[int]int // status -> requests answered with it
+	Requests int
+}
+
+// Covered reports whether any request exercised the endpoint
+func (e Endpoint) Covered() bool {
+	return e.Requests > 0
+}
+
+// Undeclared returns the statuses seen that no declared response covers, sorted
+func (e Endpoint) Undeclared() []int {
+	var statuses []int
+	for status := range e.Seen {
+		if !declares(e.Declared, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Ints(statuses)
+	return statuses
+}
+
+// FormatStatuses renders seen statuses as "200×3 404×1", marking undeclared ones with "!"
+func (e Endpoint) FormatStatuses() string {
+	statuses := make([]int, 0, len(e.Seen))
+	for status := range e.Seen {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		mark := ""
+		if !declares(e.Declared, status) {
+			mark = "!"
+		}
+		parts[i] = fmt.Sprintf("%d%s×%d", status, mark, e.Seen[status])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Report is the coverage of a schema by a set of requests
+type Report struct {
+	Endpoints []Endpoint     // sorted by path, then method
+	Unmatched map[string]int // "METHOD /path" of requests no endpoint matches -> count
+}
+
+// Covered returns how many endpoints were exercised
+func (r Report) Covered() int {
+	covered := 0
+	for _, endpoint := range r.Endpoints {
+		if endpoint.Covered() {
+			covered++
+		}
+	}
+	return covered
+}
+
+// Percent returns the share of endpoints exercised, from 0 to 100
+func (r Report) Percent() float64 {
+	if len(r.Endpoints) == 0 {
+		return 0
+	}
+	return 100 * float64(r.Covered()) / float64(len(r.Endpoints))
+}
+
+// Compute matches each request to a schema endpoint and tallies which endpoints were
+// exercised and with which statuses. A request with a recorded Route goes to the
+// endpoint whose template is that route, or ends it after a mount prefix; otherwise the
+// template that fits its path with the most static segments wins.
+func Compute(schema *parser.Schema, requests []Request) Report {
+	doc, _ := schema.Raw.(*openapi3.T)
+
+	var report Report
+	for _, endpoints := range schema.Paths {
+		for _, endpoint := range endpoints {
+			report.Endpoints = append(report.Endpoints, Endpoint{
+				Method:   endpoint.Method,
+				Path:     endpoint.Path,
+				Declared: declaredStatuses(doc, endpoint),
+				Seen:     make(map[int]int),
+			})
+		}
+	}
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		a, b := report.Endpoints[i], report.Endpoints[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	for _, request := range requests {
+		best := routeEndpoint(report.Endpoints, request)
+		if best < 0 {
+			best = pathEndpoint(report.Endpoints, request)
+		}
+		if best < 0 {
+			if report.Unmatched == nil {
+				report.Unmatched = make(map[string]int)
+			}
+			report.Unmatched[strings.ToUpper(request.Method)+" "+request.Path]++
+			continue
+		}
+		report.Endpoints[best].Requests++
+		report.Endpoints[best].Seen[request.Status]++
+	}
+
+	return report
+}
+
+// routeEndpoint returns the index of the endpoint whose template is the request's
+// recorded route, or the longest one ending it after a mount prefix, or -1
+func routeEndpoint(endpoints []Endpoint, request Request) int {
+	best := -1
+	if request.Route == "" {
+		return best
+	}
+	for i, endpoint := range endpoints {
+		if !strings.EqualFold(endpoint.Method, request.Method) {
+			continue
+		}
+		if strings.HasSuffix(request.Route, endpoint.Path) && (best < 0 || len(endpoint.Path) > len(endpoints[best].Path)) {
+			best = i
+		}
+	}
+	return best
+}
+
+// pathEndpoint returns the index of the endpoint whose template fits the request's
+// path with the most static segments, or -1
+func pathEndpoint(endpoints []Endpoint, request Request) int {
+	best, bestScore := -1, -1
+	for i, endpoint := range endpoints {
+		if !strings.EqualFold(endpoint.Method, request.Method) {
+			continue
+		}
+		if score, ok := parser.MatchPath(endpoint.Path, request.Path); ok && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// declaredStatuses returns the response codes an operation declares, sorted
+func declaredStatuses(doc *openapi3.T, endpoint parser.Endpoint) []string {
+	if doc == nil || doc.Paths == nil {
+		return nil
+	}
+	pathItem := doc.Paths.Value(endpoint.Path)
+	if pathItem == nil {
+		return nil
+	}
+	operation := pathItem.GetOperation(endpoint.Method)
+	if operation == nil || operation.Responses == nil {
+		return nil
+	}
+	var codes []string
+	for code := range operation.Responses.Map() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// declares reports whether a declared response code covers status: exactly, through a
+// range such as 4XX, or through default
+func declares(declared []string, status int) bool {
+	code := strconv.Itoa(status)
+	for _, d := range declared {
+		d = strings.ToUpper(d)
+		if d == code || d == "DEFAULT" || (len(d) == 3 && strings.HasSuffix(d, "XX") && d[0] == code[0]) {
+			return true
+		}
+	}
+	return false
+}
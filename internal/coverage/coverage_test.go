@@ -0,0 +1,82 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+func TestCompute(t *testing.T) {
+	schema, err := parser.NewOpenAPIParser().Parse("../../examples/petstore.yaml")
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	report := Compute(schema, []Request{
+		{Method: "GET", Path: "/pets", Status: 200},
+		{Method: "GET", Path: "/pets", Status: 200},
+		{Method: "get", Path: "/pets/42", Status: 404},
+		{Method: "GET", Path: "/pets/42", Status: 500},
+		{Method: "GET", Path: "/owners", Status: 404},
+		// Mounted under a prefix, only the recorded route names the endpoint
+		{Method: "POST", Path: "/v1/pets", Route: "/v1/pets", Status: 201},
+	})
+
+	endpoints := make(map[string]Endpoint)
+	for _, endpoint := range report.Endpoints {
+		endpoints[endpoint.Method+" "+endpoint.Path] = endpoint
+	}
+
+	list := endpoints["GET /pets"]
+	if list.Requests != 2 || list.Seen[200] != 2 {
+		t.Errorf("Expected GET /pets hit twice with 200, got %+v", list)
+	}
+	if len(list.Declared) == 0 || list.Declared[0] != "200" {
+		t.Errorf("Expected declared statuses from the schema, got %v", list.Declared)
+	}
+	if len(list.Undeclared()) != 0 {
+		t.Errorf("Expected no undeclared statuses, got %v", list.Undeclared())
+	}
+
+	get := endpoints["GET /pets/{petId}"]
+	if get.Requests != 2 {
+		t.Errorf("Expected GET /pets/{petId} hit twice, got %d", get.Requests)
+	}
+	if undeclared := get.Undeclared(); len(undeclared) != 1 || undeclared[0] != 500 {
+		t.Errorf("Expected 500 to be undeclared, got %v", undeclared)
+	}
+
+	if create := endpoints["POST /pets"]; create.Requests != 1 || create.Seen[201] != 1 {
+		t.Errorf("Expected POST /pets covered through its mounted route, got %+v", create)
+	}
+	if report.Covered() != 3 {
+		t.Errorf("Expected 3 covered endpoints, got %d", report.Covered())
+	}
+	if want := 100 * 3 / float64(len(report.Endpoints)); report.Percent() != want {
+		t.Errorf("Expected %.1f%%, got %.1f%%", want, report.Percent())
+	}
+	if report.Unmatched["GET /owners"] != 1 {
+		t.Errorf("Expected GET /owners to be unmatched, got %v", report.Unmatched)
+	}
+}
+
+func TestDeclares(t *testing.T) {
+	tests := []struct {
+		declared []string
+		status   int
+		expected bool
+	}{
+		{declared: []string{"200"}, status: 200, expected: true},
+		{declared: []string{"200"}, status: 201, expected: false},
+		{declared: []string{"4XX"}, status: 404, expected: true},
+		{declared: []string{"4xx"}, status: 500, expected: false},
+		{declared: []string{"default"}, status: 503, expected: true},
+		{declared: nil, status: 200, expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := declares(tt.declared, tt.status); got != tt.expected {
+			t.Errorf("declares(%v, %d) = %v, expected %v", tt.declared, tt.status, got, tt.expected)
+		}
+	}
+}
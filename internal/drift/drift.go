@@ -0,0 +1,242 @@
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Shape is the inferred structure of a response: each field path, such as
+// "data[].id", mapped to its JSON type
+type Shape map[string]string
+
+// Snapshot is one endpoint's observed status and response shape
+type Snapshot struct {
+	Status int   `json:"status"`
+	Fields Shape `json:"fields"`
+}
+
+// Baseline holds the snapshots of every watched endpoint, keyed by "METHOD /path"
+type Baseline struct {
+	Endpoints map[string]Snapshot `json:"endpoints"`
+}
+
+// Change is one difference between a baseline snapshot and a live one
+type Change struct {
+	Endpoint string
+	Field    string // empty for status changes
+	Kind     string // "added", "removed", "type changed", or "status changed"
+	Before   string
+	After    string
+}
+
+// Breaking reports whether clients relying on the baseline may fail; new fields are safe
+func (c Change) Breaking() bool {
+	return c.Kind != "added"
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case "added":
+		return fmt.Sprintf("%s: field %s added (%s)", c.Endpoint, c.Field, c.After)
+	case "removed":
+		return fmt.Sprintf("%s: field %s removed (was %s)", c.Endpoint, c.Field, c.Before)
+	case "status changed":
+		return fmt.Sprintf("%s: status changed from %s to %s", c.Endpoint, c.Before, c.After)
+	default:
+		return fmt.Sprintf("%s: field %s changed type from %s to %s", c.Endpoint, c.Field, c.Before, c.After)
+	}
+}
+
+// Infer walks a decoded JSON value and records the type at every field path. Array
+// items are merged under "[]"; a path seen with several types is recorded as "mixed".
+func Infer(value interface{}) Shape {
+	shape := make(Shape)
+	infer(shape, "", value)
+	return shape
+}
+
+// infer records value's type at path and recurses into objects and arrays
+func infer(shape Shape, path string, value interface{}) {
+	key := path
+	if key == "" {
+		key = "$"
+	}
+	typ := jsonType(value)
+	if existing, ok := shape[key]; ok && existing != typ && typ != "null" {
+		if existing == "null" {
+			shape[key] = typ
+		} else {
+			shape[key] = "mixed"
+		}
+	} else if !ok {
+		shape[key] = typ
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			childPath := name
+			if path != "" {
+				childPath = path + "." + name
+			}
+			infer(shape, childPath, child)
+		}
+	case []interface{}:
+		for _, item := range v {
+			infer(shape, path+"[]", item)
+		}
+	}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Compare lists how a live snapshot differs from the baseline, sorted by field
+func Compare(endpoint string, before, after Snapshot) []Change {
+	var changes []Change
+	if before.Status != after.Status {
+		changes = append(changes, Change{
+			Endpoint: endpoint,
+			Kind:     "status changed",
+			Before:   fmt.Sprint(before.Status),
+			After:    fmt.Sprint(after.Status),
+		})
+	}
+
+	for field, typ := range before.Fields {
+		now, ok := after.Fields[field]
+		switch {
+		case !ok:
+			if !unobserved(field, after.Fields) {
+				changes = append(changes, Change{Endpoint: endpoint, Field: field, Kind: "removed", Before: typ})
+			}
+		case now == "null" || typ == "null":
+			// Live data may leave any field empty; null says nothing about its type
+		case now != typ:
+			changes = append(changes, Change{Endpoint: endpoint, Field: field, Kind: "type changed", Before: typ, After: now})
+		}
+	}
+	for field, typ := range after.Fields {
+		if _, ok := before.Fields[field]; !ok {
+			changes = append(changes, Change{Endpoint: endpoint, Field: field, Kind: "added", After: typ})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Field != changes[j].Field {
+			return changes[i].Field < changes[j].Field
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// unobserved reports whether a field is missing from shape only because nothing could
+// carry it this time: an enclosing array came back empty, or an ancestor was null
+func unobserved(field string, shape Shape) bool {
+	for i := 0; i < len(field); i++ {
+		var parent string
+		switch {
+		case strings.HasPrefix(field[i:], "[]"):
+			parent = field[:i]
+			if parent == "" {
+				parent = "$"
+			}
+			if _, hasItems := shape[field[:i+2]]; shape[parent] == "array" && !hasItems {
+				return true
+			}
+		case field[i] == '.':
+			parent = field[:i]
+		default:
+			continue
+		}
+		if shape[parent] == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch requests an endpoint and snapshots its status and response shape
+func Fetch(ctx context.Context, client *http.Client, baseURL, method, path string) (Snapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(baseURL, "/")+path, nil)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	snapshot := Snapshot{Status: resp.StatusCode, Fields: Shape{}}
+	if len(body) == 0 {
+		return snapshot, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return Snapshot{}, fmt.Errorf("response is not JSON: %w", err)
+	}
+	snapshot.Fields = Infer(value)
+	return snapshot, nil
+}
+
+// LoadBaseline reads a baseline file; ok is false if it doesn't exist yet
+func LoadBaseline(path string) (baseline *Baseline, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Baseline{Endpoints: make(map[string]Snapshot)}, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	baseline = &Baseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, false, fmt.Errorf("failed to decode baseline %s: %w", path, err)
+	}
+	if baseline.Endpoints == nil {
+		baseline.Endpoints = make(map[string]Snapshot)
+	}
+	return baseline, true, nil
+}
+
+// Save writes the baseline as indented JSON
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
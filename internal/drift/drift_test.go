@@ -0,0 +1,134 @@
+package drift
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	var value interface{}
+	body := `{"data": [{"id": 1, "name": "a", "tag": null}, {"id": 2, "name": "b", "tag": "x"}], "total": 2, "next": null}`
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+
+	expected := Shape{
+		"$":           "object",
+		"data":        "array",
+		"data[]":      "object",
+		"data[].id":   "number",
+		"data[].name": "string",
+		"data[].tag":  "string",
+		"total":       "number",
+		"next":        "null",
+	}
+	if got := Infer(value); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := Snapshot{Status: 200, Fields: Shape{"$": "object", "id": "number", "name": "string", "legacy": "string"}}
+
+	list := Snapshot{Status: 200, Fields: Shape{"$": "object", "data": "array", "data[]": "object", "data[].id": "number"}}
+
+	tests := []struct {
+		name     string
+		before   *Snapshot // nil compares against before
+		after    Snapshot
+		expected []string
+		breaking bool
+	}{
+		{
+			name:  "unchanged",
+			after: before,
+		},
+		{
+			name:     "field added",
+			after:    Snapshot{Status: 200, Fields: Shape{"$": "object", "id": "number", "name": "string", "legacy": "string", "email": "string"}},
+			expected: []string{"GET /users: field email added (string)"},
+		},
+		{
+			name:  "field removed and retyped",
+			after: Snapshot{Status: 200, Fields: Shape{"$": "object", "id": "string", "name": "string"}},
+			expected: []string{
+				"GET /users: field id changed type from number to string",
+				"GET /users: field legacy removed (was string)",
+			},
+			breaking: true,
+		},
+		{
+			name:  "field null in live data",
+			after: Snapshot{Status: 200, Fields: Shape{"$": "object", "id": "number", "name": "null", "legacy": "string"}},
+		},
+		{
+			// An empty page has no items to infer fields from; that isn't a removal
+			name:   "list empty in live data",
+			before: &list,
+			after:  Snapshot{Status: 200, Fields: Shape{"$": "object", "data": "array"}},
+		},
+		{
+			name:   "list removed",
+			before: &list,
+			after:  Snapshot{Status: 200, Fields: Shape{"$": "object"}},
+			expected: []string{
+				"GET /users: field data removed (was array)",
+				"GET /users: field data[] removed (was object)",
+				"GET /users: field data[].id removed (was number)",
+			},
+			breaking: true,
+		},
+		{
+			name:     "status changed",
+			after:    Snapshot{Status: 404, Fields: before.Fields},
+			expected: []string{"GET /users: status changed from 200 to 404"},
+			breaking: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := before
+			if tt.before != nil {
+				baseline = *tt.before
+			}
+			changes := Compare("GET /users", baseline, tt.after)
+			var got []string
+			breaking := false
+			for _, change := range changes {
+				got = append(got, change.String())
+				breaking = breaking || change.Breaking()
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if breaking != tt.breaking {
+				t.Errorf("Expected breaking=%v, got %v", tt.breaking, breaking)
+			}
+		})
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	baseline, exists, err := LoadBaseline(path)
+	if err != nil || exists {
+		t.Fatalf("Expected a missing baseline, got exists=%v err=%v", exists, err)
+	}
+
+	baseline.Endpoints["GET /pets"] = Snapshot{Status: 200, Fields: Shape{"$": "array"}}
+	if err := baseline.Save(path); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded, exists, err := LoadBaseline(path)
+	if err != nil || !exists {
+		t.Fatalf("Expected a saved baseline, got exists=%v err=%v", exists, err)
+	}
+	if !reflect.DeepEqual(loaded, baseline) {
+		t.Errorf("Expected %v, got %v", baseline, loaded)
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// defaultBinarySize is the length of generated file bodies when nothing else sets it
+const defaultBinarySize = 1024
+
+// binaryMagic holds file signatures so generated files are recognized by type sniffers
+var binaryMagic = map[string][]byte{
+	"image/png":       {0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'},
+	"image/jpeg":      {0xff, 0xd8, 0xff},
+	"image/gif":       []byte("GIF89a"),
+	"application/pdf": []byte("%PDF-1.7\n"),
+}
+
+// FindBinaryContent returns the media type and schema of a file download declared for
+// statusCode: application/octet-stream, image/* without a schema, or any media type whose
+// schema is a format: binary string. JSON responses take precedence.
+func FindBinaryContent(operation *openapi3.Operation, statusCode string) (string, *openapi3.Schema, bool) {
+	if operation == nil || operation.Responses == nil {
+		return "", nil, false
+	}
+	responseRef := findResponse(operation.Responses, statusCode)
+	if responseRef == nil || responseRef.Value == nil || responseRef.Value.Content == nil {
+		return "", nil, false
+	}
+	content := responseRef.Value.Content
+	if mediaType, _ := JSONMediaType(content); mediaType != "" {
+		return "", nil, false
+	}
+
+	for _, mediaType := range sortedMediaTypes(content) {
+		var schema *openapi3.Schema
+		if media := content[mediaType]; media != nil && media.Schema != nil {
+			schema = media.Schema.Value
+		}
+		isBinarySchema := schema != nil && schema.Format == "binary"
+		isImage := strings.HasPrefix(mediaType, "image/") && schema == nil
+		if mediaType == "application/octet-stream" || isBinarySchema || isImage {
+			return mediaType, schema, true
+		}
+	}
+	return "", nil, false
+}
+
+// GenerateBinary produces a deterministic byte blob for a file download. Its size is
+// GenerateOptions.BinarySize, clamped to the schema's minLength/maxLength.
+func (g *Generator) GenerateBinary(mediaType string, schema *openapi3.Schema) []byte {
+	return g.generateBinary(mediaType, schema)
+}
+
+// generateBinary fills a blob with seeded random bytes behind the media type's signature
+func (g *Generator) generateBinary(mediaType string, schema *openapi3.Schema) []byte {
+	size := g.opts.BinarySize
+	if size <= 0 {
+		size = defaultBinarySize
+	}
+	if schema != nil {
+		if schema.MaxLength != nil && uint64(size) > *schema.MaxLength {
+			size = int(*schema.MaxLength)
+		}
+		if uint64(size) < schema.MinLength {
+			size = int(schema.MinLength)
+		}
+	}
+
+	data := make([]byte, size)
+	g.rng.Read(data)
+	copy(data, binaryMagic[mediaType])
+	return data
+}
+
+// sortedMediaTypes returns a content map's media types in sorted order
+func sortedMediaTypes(content openapi3.Content) []string {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGenerateBinary(t *testing.T) {
+	maxLength := uint64(16)
+
+	tests := []struct {
+		name      string
+		opts      GenerateOptions
+		mediaType string
+		schema    *openapi3.Schema
+		check     func(t *testing.T, data []byte)
+	}{
+		{
+			name:      "default size",
+			mediaType: "application/octet-stream",
+			check: func(t *testing.T, data []byte) {
+				if len(data) != defaultBinarySize {
+					t.Errorf("Expected %d bytes, got %d", defaultBinarySize, len(data))
+				}
+			},
+		},
+		{
+			name:      "configured size",
+			opts:      GenerateOptions{BinarySize: 64},
+			mediaType: "application/octet-stream",
+			check: func(t *testing.T, data []byte) {
+				if len(data) != 64 {
+					t.Errorf("Expected 64 bytes, got %d", len(data))
+				}
+			},
+		},
+		{
+			name:      "maxLength clamps size",
+			mediaType: "application/octet-stream",
+			schema:    &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "binary", MaxLength: &maxLength},
+			check: func(t *testing.T, data []byte) {
+				if len(data) != 16 {
+					t.Errorf("Expected 16 bytes, got %d", len(data))
+				}
+			},
+		},
+		{
+			name:      "image signature",
+			mediaType: "image/png",
+			check: func(t *testing.T, data []byte) {
+				if !bytes.HasPrefix(data, binaryMagic["image/png"]) {
+					t.Errorf("Expected PNG signature, got % x", data[:8])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := NewGeneratorWithOptions(1, tt.opts).GenerateBinary(tt.mediaType, tt.schema)
+			again := NewGeneratorWithOptions(1, tt.opts).GenerateBinary(tt.mediaType, tt.schema)
+			if !bytes.Equal(data, again) {
+				t.Error("Expected the same seed to produce the same bytes")
+			}
+			tt.check(t, data)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// referenceSuffixes mark a field as referencing another object's id, e.g. userId,
+// userID, or user_id referencing the object under "user"
+var referenceSuffixes = []string{"Id", "ID", "_id"}
+
+// alignReferences makes each <name>Id field of obj match the id of the object held
+// under <name>, beside the field or nested deeper in obj. Arrays aren't searched, since
+// which element a reference points at would be a guess.
+func alignReferences(obj map[string]interface{}) {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, ok := referenceName(key)
+		if !ok || obj[key] == nil {
+			continue
+		}
+		target, ok := findObject(obj, name)
+		if !ok {
+			continue
+		}
+		if value, ok := convertID(target["id"], obj[key]); ok {
+			obj[key] = value
+		}
+	}
+}
+
+// referenceName returns the object name a reference field points at
+func referenceName(key string) (string, bool) {
+	for _, suffix := range referenceSuffixes {
+		if name, ok := strings.CutSuffix(key, suffix); ok && name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// findObject searches obj breadth-first for an object stored under name, so the
+// nearest one wins
+func findObject(obj map[string]interface{}, name string) (map[string]interface{}, bool) {
+	queue := []map[string]interface{}{obj}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if target, ok := current[name].(map[string]interface{}); ok {
+			return target, true
+		}
+
+		keys := make([]string, 0, len(current))
+		for key := range current {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if child, ok := current[key].(map[string]interface{}); ok {
+				queue = append(queue, child)
+			}
+		}
+	}
+	return nil, false
+}
+
+// convertID converts an object's id to the type of the reference field replacing it;
+// ok is false when there is no id or it can't be represented in that type
+func convertID(id, reference interface{}) (interface{}, bool) {
+	if id == nil {
+		return nil, false
+	}
+	switch reference.(type) {
+	case string:
+		return fmt.Sprint(id), true
+	case int64:
+		switch v := id.(type) {
+		case int64:
+			return v, true
+		case string:
+			n, err := strconv.ParseInt(v, 10, 64)
+			return n, err == nil
+		}
+	case float64:
+		switch v := id.(type) {
+		case float64:
+			return v, true
+		case int64:
+			return float64(v), true
+		}
+	}
+	return nil, false
+}
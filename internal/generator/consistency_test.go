@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestConsistentReferences(t *testing.T) {
+	user := openapi3.NewObjectSchema().
+		WithProperty("id", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}).
+		WithProperty("name", openapi3.NewStringSchema())
+
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+		check  func(t *testing.T, obj map[string]interface{})
+	}{
+		{
+			name: "sibling userId and user.id",
+			schema: openapi3.NewObjectSchema().
+				WithProperty("userId", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}).
+				WithProperty("user", user),
+			check: func(t *testing.T, obj map[string]interface{}) {
+				if id := obj["user"].(map[string]interface{})["id"]; obj["userId"] != id {
+					t.Errorf("Expected userId %v to match user.id %v", obj["userId"], id)
+				}
+			},
+		},
+		{
+			name: "snake_case reference to a nested object",
+			schema: openapi3.NewObjectSchema().
+				WithProperty("owner_id", openapi3.NewStringSchema()).
+				WithProperty("details", openapi3.NewObjectSchema().WithProperty("owner", user)),
+			check: func(t *testing.T, obj map[string]interface{}) {
+				owner := obj["details"].(map[string]interface{})["owner"].(map[string]interface{})
+				if obj["owner_id"] != owner["id"] {
+					t.Errorf("Expected owner_id %v to match details.owner.id %v", obj["owner_id"], owner["id"])
+				}
+			},
+		},
+		{
+			name: "integer id converted to a string reference",
+			schema: openapi3.NewObjectSchema().
+				WithProperty("accountID", openapi3.NewStringSchema()).
+				WithProperty("account", openapi3.NewObjectSchema().WithProperty("id", openapi3.NewInt64Schema())),
+			check: func(t *testing.T, obj map[string]interface{}) {
+				id := obj["account"].(map[string]interface{})["id"].(int64)
+				if obj["accountID"] != strconv.FormatInt(id, 10) {
+					t.Errorf("Expected accountID %v to match account.id %d", obj["accountID"], id)
+				}
+			},
+		},
+		{
+			name: "references inside array items stay per item",
+			schema: openapi3.NewArraySchema().WithItems(openapi3.NewObjectSchema().
+				WithProperty("userId", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}).
+				WithProperty("user", user)),
+			check: func(t *testing.T, obj map[string]interface{}) {
+				items := obj["items"].([]interface{})
+				for _, item := range items {
+					entry := item.(map[string]interface{})
+					if id := entry["user"].(map[string]interface{})["id"]; entry["userId"] != id {
+						t.Errorf("Expected userId %v to match user.id %v", entry["userId"], id)
+					}
+				}
+				if items[0].(map[string]interface{})["userId"] == items[1].(map[string]interface{})["userId"] {
+					t.Error("Expected each item to keep its own user")
+				}
+			},
+		},
+		{
+			name: "unrelated fields untouched",
+			schema: openapi3.NewObjectSchema().
+				WithProperty("orderId", openapi3.NewStringSchema()).
+				WithProperty("user", user),
+			check: func(t *testing.T, obj map[string]interface{}) {
+				if obj["orderId"] == obj["user"].(map[string]interface{})["id"] {
+					t.Error("Expected orderId not to be aligned with user.id")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGeneratorWithOptions(11, GenerateOptions{Consistent: true})
+			value, err := gen.GenerateFromSchema(tt.schema)
+			if err != nil {
+				t.Fatalf("Failed to generate: %v", err)
+			}
+			if items, ok := value.([]interface{}); ok {
+				value = map[string]interface{}{"items": items}
+			}
+			tt.check(t, value.(map[string]interface{}))
+		})
+	}
+}
+
+func TestConsistentReferencesDisabled(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("userId", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}).
+		WithProperty("user", openapi3.NewObjectSchema().WithProperty("id", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}))
+
+	value, err := NewGenerator(11).GenerateFromSchema(schema)
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	obj := value.(map[string]interface{})
+	if obj["userId"] == obj["user"].(map[string]interface{})["id"] {
+		t.Error("Expected independent ids without Consistent")
+	}
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// fillerAttempts bounds how often a filler item matching contains is regenerated
+// before maxContains is reported unsatisfiable
+const fillerAttempts = 10
+
+// containsBounds returns an array's minContains and maxContains, -1 when unbounded, if
+// the parser rewrote a contains constraint into its items as
+// anyOf [items, allOf [items, contains]]
+func containsBounds(schema *openapi3.Schema) (int, int, bool) {
+	minContains, ok := extensionInt(schema, parser.ContainsExtension)
+	if !ok || schema.Items.Value == nil || len(schema.Items.Value.AnyOf) != 2 {
+		return 0, 0, false
+	}
+	maxContains, ok := extensionInt(schema, parser.MaxContainsExtension)
+	if !ok {
+		maxContains = -1
+	}
+	return minContains, maxContains, true
+}
+
+// generateContains generates an array holding at least minContains items matching both
+// items and contains, at seeded positions, with the rest drawn from the items schema. Filler items
+// that happen to match contains count towards maxContains.
+func (g *Generator) generateContains(schema *openapi3.Schema, minContains, maxContains int) ([]interface{}, error) {
+	branches := schema.Items.Value.AnyOf
+	if branches[1] == nil || branches[1].Value == nil {
+		return nil, fmt.Errorf("array contains schema is unresolved")
+	}
+	containsSchema := branches[1].Value
+	itemSchema := &openapi3.Schema{}
+	if branches[0] != nil && branches[0].Value != nil {
+		itemSchema = branches[0].Value
+	}
+
+	if maxContains >= 0 && minContains > maxContains {
+		return nil, fmt.Errorf("array minContains %d exceeds maxContains %d", minContains, maxContains)
+	}
+	if schema.MaxItems != nil && uint64(minContains) > *schema.MaxItems {
+		return nil, fmt.Errorf("array minContains %d exceeds maxItems %d", minContains, *schema.MaxItems)
+	}
+
+	length := max(g.arrayLength(schema), minContains)
+	matching := make([]bool, length)
+	for _, i := range g.rng.Perm(length)[:minContains] {
+		matching[i] = true
+	}
+
+	result := make([]interface{}, length)
+	matched := minContains
+	for i := range result {
+		if matching[i] {
+			item, err := g.GenerateFromSchema(containsSchema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate contains item: %w", err)
+			}
+			result[i] = item
+			continue
+		}
+
+		item, isMatch, err := g.fillerItem(itemSchema, containsSchema, maxContains >= 0 && matched >= maxContains)
+		if err != nil {
+			return nil, err
+		}
+		if isMatch {
+			matched++
+		}
+		result[i] = item
+	}
+	return result, nil
+}
+
+// fillerItem generates an item from the items schema, retrying while it matches the
+// contains schema if avoid is set, and reports whether the item matches contains
+func (g *Generator) fillerItem(itemSchema, containsSchema *openapi3.Schema, avoid bool) (interface{}, bool, error) {
+	for attempt := 0; attempt < fillerAttempts; attempt++ {
+		item, err := g.GenerateFromSchema(itemSchema)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to generate array item: %w", err)
+		}
+		isMatch := containsSchema.VisitJSON(item) == nil
+		if !isMatch || !avoid {
+			return item, isMatch, nil
+		}
+	}
+	return nil, false, fmt.Errorf("array items keep matching contains beyond maxContains")
+}
+
+// extensionInt reads a numeric schema extension, decoded from JSON as float64
+func extensionInt(schema *openapi3.Schema, name string) (int, bool) {
+	switch n := schema.Extensions[name].(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// containsSchema builds an array whose items.anyOf holds the items schema and the items
+// and contains schemas combined, as the parser rewrites contains
+func containsSchema(items, contains *openapi3.Schema, minContains, maxContains int) *openapi3.Schema {
+	combined := &openapi3.Schema{AllOf: openapi3.SchemaRefs{items.NewRef(), contains.NewRef()}}
+	schema := openapi3.NewArraySchema().WithItems(&openapi3.Schema{AnyOf: openapi3.SchemaRefs{items.NewRef(), combined.NewRef()}})
+	schema.Extensions = map[string]interface{}{parser.ContainsExtension: float64(minContains)}
+	if maxContains >= 0 {
+		schema.Extensions[parser.MaxContainsExtension] = float64(maxContains)
+	}
+	return schema
+}
+
+func TestGenerateContains(t *testing.T) {
+	admin := openapi3.NewStringSchema().WithEnum("admin")
+	words := openapi3.NewStringSchema().WithPattern("^[a-z]{3}$")
+
+	tests := []struct {
+		name    string
+		schema  *openapi3.Schema
+		minHits int
+		maxHits int // -1 unbounded
+		wantErr string
+	}{
+		{name: "default minContains", schema: containsSchema(words, admin, 1, -1), minHits: 1, maxHits: -1},
+		{name: "minContains beyond the default length", schema: containsSchema(words, admin, 7, -1), minHits: 7, maxHits: -1},
+		{name: "maxContains caps matching fillers", schema: containsSchema(openapi3.NewStringSchema().WithEnum("admin", "guest"), admin, 1, 1), minHits: 1, maxHits: 1},
+		{name: "minContains over maxItems", schema: containsSchema(words, admin, 4, -1).WithMaxItems(3), wantErr: "exceeds maxItems 3"},
+		{name: "minContains over maxContains", schema: containsSchema(words, admin, 3, 2), wantErr: "exceeds maxContains 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				value, err := NewGenerator(seed).GenerateFromSchema(tt.schema)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				hits := 0
+				for _, item := range value.([]interface{}) {
+					if item == "admin" {
+						hits++
+					}
+				}
+				if hits < tt.minHits || (tt.maxHits >= 0 && hits > tt.maxHits) {
+					t.Fatalf("Seed %d: expected %d to %d admin items, got %v", seed, tt.minHits, tt.maxHits, value)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateContainsMatchesItems(t *testing.T) {
+	// contains narrows items rather than replacing them: an untyped contains schema
+	// still yields integers
+	schema := containsSchema(openapi3.NewIntegerSchema(), openapi3.NewSchema().WithMin(5), 2, -1)
+
+	for seed := int64(1); seed <= 20; seed++ {
+		value, err := NewGenerator(seed).GenerateFromSchema(schema)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		hits := 0
+		for _, item := range value.([]interface{}) {
+			n, ok := item.(int64)
+			if !ok {
+				t.Fatalf("Seed %d: expected only integer items, got %v", seed, value)
+			}
+			if n >= 5 {
+				hits++
+			}
+		}
+		if hits < 2 {
+			t.Fatalf("Seed %d: expected at least 2 items of at least 5, got %v", seed, value)
+		}
+	}
+}
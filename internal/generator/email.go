@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// emailNameFields lists sibling properties, in order of preference, whose values give
+// a generated email its local part; a pair is joined, as in first.last
+var emailNameFields = [][]string{
+	{"name"},
+	{"fullName"},
+	{"full_name"},
+	{"firstName", "lastName"},
+	{"first_name", "last_name"},
+	{"displayName"},
+	{"display_name"},
+	{"username"},
+}
+
+// emailTransliterations spells the locales' accented letters in ASCII for local parts
+var emailTransliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ñ", "n",
+)
+
+// alignEmails rewrites the local part of generated email properties to follow a name in
+// the same object, so {"name": "John Doe"} gets an email like john.doe@example.com.
+// Emails from an enum or pattern, or that would outgrow maxLength, are left alone.
+func alignEmails(schema *openapi3.Schema, obj map[string]interface{}) {
+	local := emailLocalPart(obj)
+	if local == "" {
+		return
+	}
+
+	for name, ref := range schema.Properties {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		prop := ref.Value
+		if prop.Format != "email" || len(prop.Enum) > 0 || prop.Pattern != "" {
+			continue
+		}
+		email, ok := obj[name].(string)
+		if !ok {
+			continue
+		}
+		at := strings.LastIndex(email, "@")
+		if at < 0 {
+			continue
+		}
+		aligned := local + email[at:]
+		if prop.MaxLength != nil && uint64(len(aligned)) > *prop.MaxLength {
+			continue
+		}
+		obj[name] = aligned
+	}
+}
+
+// emailLocalPart derives a dot-separated local part from the first name field an
+// object has, keeping lowercase ASCII letters and digits; empty if there is none
+func emailLocalPart(obj map[string]interface{}) string {
+	for _, fields := range emailNameFields {
+		var parts []string
+		for _, field := range fields {
+			value, ok := obj[field].(string)
+			if !ok {
+				parts = nil
+				break
+			}
+			parts = append(parts, value)
+		}
+		if local := localPart(strings.Join(parts, " ")); local != "" {
+			return local
+		}
+	}
+	return ""
+}
+
+// localPart lowercases and transliterates a name, joining its words with dots
+func localPart(name string) string {
+	words := strings.FieldsFunc(emailTransliterations.Replace(strings.ToLower(name)), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	return strings.Join(words, ".")
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// localPartPattern matches the dot-atom local parts generated emails use
+var localPartPattern = regexp.MustCompile(`^[a-z0-9]+(\.[a-z0-9]+)*$`)
+
+func TestEmailDomain(t *testing.T) {
+	schema := openapi3.NewStringSchema().WithFormat("email")
+
+	tests := []struct {
+		name     string
+		opts     GenerateOptions
+		expected string
+	}{
+		{name: "default", expected: "@example.com"},
+		{name: "locale domain", opts: GenerateOptions{Locale: "de"}, expected: "@beispiel.example"},
+		{name: "custom domain", opts: GenerateOptions{EmailDomain: "acme.test", NameEmails: true}, expected: "@acme.test"},
+		{name: "custom domain beats locale", opts: GenerateOptions{Locale: "es", EmailDomain: "acme.test"}, expected: "@acme.test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := NewGeneratorWithOptions(42, tt.opts).GenerateFromSchema(schema)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			email := value.(string)
+			local, found := strings.CutSuffix(email, tt.expected)
+			if !found || !localPartPattern.MatchString(local) {
+				t.Errorf("Expected a valid local part and %s, got %q", tt.expected, email)
+			}
+		})
+	}
+}
+
+func TestEmailFollowsName(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties openapi3.Schemas
+		values     map[string]interface{}
+		expected   string
+	}{
+		{
+			name:     "name",
+			values:   map[string]interface{}{"name": "John Doe"},
+			expected: "john.doe",
+		},
+		{
+			name:     "first and last name",
+			values:   map[string]interface{}{"firstName": "Jörg", "lastName": "Müller-Lüdenscheidt"},
+			expected: "joerg.mueller.luedenscheidt",
+		},
+		{
+			name:     "no name field",
+			values:   map[string]interface{}{"title": "Dr"},
+			expected: "user7",
+		},
+		{
+			name:       "enum email left alone",
+			properties: openapi3.Schemas{"email": openapi3.NewStringSchema().WithFormat("email").WithEnum("admin@example.com").NewRef()},
+			values:     map[string]interface{}{"name": "John Doe", "email": "admin@example.com"},
+			expected:   "admin",
+		},
+		{
+			name:       "too long for maxLength",
+			properties: openapi3.Schemas{"email": openapi3.NewStringSchema().WithFormat("email").WithMaxLength(12).NewRef()},
+			values:     map[string]interface{}{"name": "Johnathan Doe"},
+			expected:   "user7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := openapi3.NewObjectSchema().WithProperty("email", openapi3.NewStringSchema().WithFormat("email"))
+			for name, ref := range tt.properties {
+				schema.Properties[name] = ref
+			}
+			obj := map[string]interface{}{"email": "user7@example.com"}
+			for key, value := range tt.values {
+				obj[key] = value
+			}
+
+			alignEmails(schema, obj)
+			if email := obj["email"]; email != tt.expected+"@example.com" {
+				t.Errorf("Expected %s@example.com, got %v", tt.expected, email)
+			}
+		})
+	}
+}
+
+func TestEmailFollowsGeneratedName(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("email", openapi3.NewStringSchema().WithFormat("email"))
+	schema.Required = []string{"name", "email"}
+
+	first, err := NewGeneratorWithOptions(7, GenerateOptions{EmailDomain: "acme.test", NameEmails: true}).GenerateFromSchema(schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	obj := first.(map[string]interface{})
+	if obj["email"] != obj["name"].(string)+"@acme.test" {
+		t.Errorf("Expected the email to follow the name, got %v", obj)
+	}
+
+	second, _ := NewGeneratorWithOptions(7, GenerateOptions{EmailDomain: "acme.test", NameEmails: true}).GenerateFromSchema(schema)
+	if second.(map[string]interface{})["email"] != obj["email"] {
+		t.Errorf("Expected the same seed to generate the same email")
+	}
+
+	// Without NameEmails the email is generated on its own
+	plain, _ := NewGeneratorWithOptions(7, GenerateOptions{EmailDomain: "acme.test"}).GenerateFromSchema(schema)
+	if email := plain.(map[string]interface{})["email"]; email == obj["email"] {
+		t.Errorf("Expected the email not to follow the name without NameEmails, got %v", email)
+	}
+}
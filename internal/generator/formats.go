@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// FormatGenerator produces a string for a schema with a particular format. It must draw
+// all randomness from rng so seeded output stays reproducible.
+type FormatGenerator func(rng *rand.Rand, schema *openapi3.Schema) string
+
+// RegisterFormat adds or replaces the generator used for strings with the named format
+func (g *Generator) RegisterFormat(name string, fn FormatGenerator) {
+	g.formats[name] = fn
+}
+
+// builtinFormats returns the formats every generator starts with; uri uses the locale's
+// domain, and email too unless emailDomain overrides it. Dates count back from now().
+func builtinFormats(locale Locale, emailDomain string, now func() time.Time) map[string]FormatGenerator {
+	if emailDomain == "" {
+		emailDomain = locale.Domain
+	}
+	return map[string]FormatGenerator{
+		"date-time": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return now().Add(-time.Duration(rng.Intn(365*24)) * time.Hour).Format(time.RFC3339)
+		},
+		"date": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return now().Add(-time.Duration(rng.Intn(365)) * 24 * time.Hour).Format("2006-01-02")
+		},
+		"email": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return fmt.Sprintf("user%d@%s", rng.Intn(1000), emailDomain)
+		},
+		"uuid": generateUUID,
+		"uri": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return fmt.Sprintf("https://%s/resource/%d", locale.Domain, rng.Intn(1000))
+		},
+		"iri": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return "https://" + locale.Domain + generateIRIPath(rng, locale)
+		},
+		"iri-reference": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			// Half absolute IRIs, half paths relative to the document's base
+			if rng.Intn(2) == 0 {
+				return "https://" + locale.Domain + generateIRIPath(rng, locale)
+			}
+			return generateIRIPath(rng, locale)
+		},
+		"int64":     generateStringID,
+		"snowflake": generateStringID,
+		"decimal":   generateDecimal,
+		"color": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return fmt.Sprintf("#%06x", rng.Intn(1<<24))
+		},
+		"slug": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			words := make([]string, 3)
+			for i := range words {
+				words[i] = strings.ToLower(locale.Words[rng.Intn(len(locale.Words))])
+			}
+			return strings.Join(words, "-")
+		},
+		"username": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return fmt.Sprintf("%s_%d", strings.ToLower(locale.Words[rng.Intn(len(locale.Words))]), rng.Intn(1000))
+		},
+		"mac":         generateMAC,
+		"credit-card": generateCreditCard,
+		"json-pointer": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			return generateJSONPointer(rng, locale)
+		},
+		"relative-json-pointer": func(rng *rand.Rand, _ *openapi3.Schema) string {
+			// A non-negative level count, then a pointer from that ancestor or "#" for its key
+			levels := strconv.Itoa(rng.Intn(3))
+			if rng.Intn(4) == 0 {
+				return levels + "#"
+			}
+			return levels + generateJSONPointer(rng, locale)
+		},
+	}
+}
+
+// generateJSONPointer generates an RFC 6901 JSON Pointer such as "/items/0/name": one to
+// three reference tokens of locale words, the middle ones sometimes array indexes
+func generateJSONPointer(rng *rand.Rand, locale Locale) string {
+	tokens := make([]string, 1+rng.Intn(3))
+	for i := range tokens {
+		if i > 0 && i < len(tokens)-1 && rng.Intn(2) == 0 {
+			tokens[i] = strconv.Itoa(rng.Intn(10))
+			continue
+		}
+		tokens[i] = jsonPointerEscaper.Replace(strings.ToLower(locale.Words[rng.Intn(len(locale.Words))]))
+	}
+	return "/" + strings.Join(tokens, "/")
+}
+
+// iriSegments are path segments with non-ASCII characters, which IRIs allow unencoded
+var iriSegments = []string{"café", "straße", "façade", "документы", "ελληνικά", "東京", "서울"}
+
+// generateIRIPath generates an IRI path such as "/café/42": a locale word or
+// internationalized segment, then a number
+func generateIRIPath(rng *rand.Rand, locale Locale) string {
+	segment := iriSegments[rng.Intn(len(iriSegments))]
+	if rng.Intn(2) == 0 {
+		segment = strings.ToLower(locale.Words[rng.Intn(len(locale.Words))])
+	}
+	return fmt.Sprintf("/%s/%d", segment, rng.Intn(1000))
+}
+
+// jsonPointerEscaper escapes "~" and "/" inside a reference token as "~0" and "~1"
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// generateMAC generates a colon-separated MAC address with the locally administered
+// bit set and the multicast bit clear, so it never collides with a vendor's address
+func generateMAC(rng *rand.Rand, _ *openapi3.Schema) string {
+	octets := make([]string, 6)
+	for i := range octets {
+		b := byte(rng.Intn(256))
+		if i == 0 {
+			b = b&0xfc | 0x02
+		}
+		octets[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(octets, ":")
+}
+
+// cardTestNumbers are card numbers the networks and payment processors publish for
+// testing; they pass the Luhn check but are never issued to real cards
+var cardTestNumbers = []string{
+	"4111111111111111", // Visa
+	"4242424242424242", // Visa
+	"4012888888881881", // Visa
+	"5555555555554444", // Mastercard
+	"5105105105105100", // Mastercard
+	"378282246310005",  // American Express
+	"371449635398431",  // American Express
+	"6011111111111117", // Discover
+	"6011000990139424", // Discover
+}
+
+// generateCreditCard picks one of the published test card numbers, so client validators
+// accept it while payment processors never treat it as a real card
+func generateCreditCard(rng *rand.Rand, _ *openapi3.Schema) string {
+	return cardTestNumbers[rng.Intn(len(cardTestNumbers))]
+}
+
+// generateStringID generates a 64-bit numeric id serialized as a string, as APIs do to
+// avoid JavaScript precision loss. Values are 18-19 digits, like snowflake ids.
+func generateStringID(rng *rand.Rand, _ *openapi3.Schema) string {
+	const minID = int64(100000000000000000) // 18 digits
+	return strconv.FormatInt(minID+rng.Int63n(math.MaxInt64-minID), 10)
+}
+
+// generateDecimal generates a money-style decimal string with two fraction digits,
+// respecting minimum/maximum when present (default range 0-10000)
+func generateDecimal(rng *rand.Rand, schema *openapi3.Schema) string {
+	minCents := int64(0)
+	maxCents := int64(1000000)
+
+	if schema.Min != nil {
+		minCents = int64(math.Ceil(*schema.Min * 100))
+	}
+	if schema.Max != nil {
+		maxCents = int64(math.Floor(*schema.Max * 100))
+	}
+
+	cents := minCents
+	if maxCents > minCents {
+		cents = minCents + rng.Int63n(maxCents-minCents+1)
+	}
+
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// generateUUID returns a random (version 4, RFC 4122 variant) UUID drawn from rng
+func generateUUID(rng *rand.Rand, _ *openapi3.Schema) string {
+	var b [16]byte
+	rng.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // variant 10xx
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
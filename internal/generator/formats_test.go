@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFormatRegistry(t *testing.T) {
+	tests := []struct {
+		format   string
+		register FormatGenerator
+		pattern  string
+	}{
+		{format: "color", pattern: `^#[0-9a-f]{6}$`},
+		{format: "slug", pattern: `^[a-z]+-[a-z]+-[a-z]+$`},
+		{format: "username", pattern: `^[a-z]+_\d+$`},
+		{format: "uuid", pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`},
+		{
+			format: "sku",
+			register: func(rng *rand.Rand, _ *openapi3.Schema) string {
+				return "SKU-" + string(rune('A'+rng.Intn(26)))
+			},
+			pattern: `^SKU-[A-Z]$`,
+		},
+		{
+			format: "email",
+			register: func(rng *rand.Rand, _ *openapi3.Schema) string {
+				return "fixed@override.test"
+			},
+			pattern: `^fixed@override\.test$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			gen := NewGenerator(3)
+			if tt.register != nil {
+				gen.RegisterFormat(tt.format, tt.register)
+			}
+
+			// Registered formats also apply to derived generators
+			value, err := gen.WithContext(ContextResponse).GenerateFromSchema(&openapi3.Schema{Type: &openapi3.Types{"string"}, Format: tt.format})
+			if err != nil {
+				t.Fatalf("Failed to generate: %v", err)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(value.(string)) {
+				t.Errorf("Expected %s value matching %s, got %q", tt.format, tt.pattern, value)
+			}
+		})
+	}
+}
+
+func TestUUIDVersion4(t *testing.T) {
+	for seed := int64(0); seed < 500; seed++ {
+		value := generateUUID(rand.New(rand.NewSource(seed)), nil)
+		if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`).MatchString(value) {
+			t.Fatalf("Seed %d: expected canonical UUID, got %q", seed, value)
+		}
+		b, err := hex.DecodeString(strings.ReplaceAll(value, "-", ""))
+		if err != nil {
+			t.Fatalf("Seed %d: failed to decode %q: %v", seed, value, err)
+		}
+		if version := b[6] >> 4; version != 4 {
+			t.Errorf("Seed %d: expected version 4, got %d in %q", seed, version, value)
+		}
+		if variant := b[8] >> 6; variant != 0b10 {
+			t.Errorf("Seed %d: expected RFC 4122 variant, got %02b in %q", seed, variant, value)
+		}
+	}
+
+	// The same seed gives the same UUID
+	if a, b := generateUUID(rand.New(rand.NewSource(7)), nil), generateUUID(rand.New(rand.NewSource(7)), nil); a != b {
+		t.Errorf("Expected deterministic UUIDs, got %q and %q", a, b)
+	}
+}
+
+func TestMACFormat(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{2}(:[0-9a-f]{2}){5}$`)
+	for seed := int64(0); seed < 100; seed++ {
+		value := generateMAC(rand.New(rand.NewSource(seed)), nil)
+		if !pattern.MatchString(value) {
+			t.Fatalf("Seed %d: expected a MAC address, got %q", seed, value)
+		}
+		first, _ := hex.DecodeString(value[:2])
+		if first[0]&0x02 == 0 || first[0]&0x01 != 0 {
+			t.Errorf("Seed %d: expected a locally administered unicast address, got %q", seed, value)
+		}
+	}
+}
+
+func TestCreditCardFormat(t *testing.T) {
+	// luhnValid checks a whole number, check digit included
+	luhnValid := func(number string) bool {
+		sum := 0
+		for i := len(number) - 1; i >= 0; i-- {
+			d := int(number[i] - '0')
+			if (len(number)-i)%2 == 0 {
+				if d *= 2; d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		return sum%10 == 0
+	}
+
+	if !luhnValid("4111111111111111") || luhnValid("4111111111111112") {
+		t.Fatal("luhnValid disagrees with a published test number")
+	}
+	for _, number := range cardTestNumbers {
+		if !regexp.MustCompile(`^\d{15,16}$`).MatchString(number) || !luhnValid(number) {
+			t.Errorf("Expected a Luhn-valid 15-16 digit test number, got %q", number)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for seed := int64(0); seed < 200; seed++ {
+		value := generateCreditCard(rand.New(rand.NewSource(seed)), nil)
+		if !slices.Contains(cardTestNumbers, value) {
+			t.Fatalf("Seed %d: expected a published test number, got %q", seed, value)
+		}
+		seen[value] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Expected seeds to pick different test numbers, got %v", seen)
+	}
+}
+
+func TestJSONPointerFormats(t *testing.T) {
+	// Each token is any run of characters other than "/" and "~", or an escape
+	token := `([^/~]|~[01])*`
+	tests := []struct {
+		format  string
+		pattern string
+	}{
+		{format: "json-pointer", pattern: `^(/` + token + `)+$`},
+		{format: "relative-json-pointer", pattern: `^(0|[1-9][0-9]*)(#|(/` + token + `)+)$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			schema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: tt.format}
+			re := regexp.MustCompile(tt.pattern)
+			for seed := int64(0); seed < 50; seed++ {
+				for _, locale := range []string{"en", "de", "es"} {
+					value, err := NewGeneratorWithOptions(seed, GenerateOptions{Locale: locale}).GenerateFromSchema(schema)
+					if err != nil {
+						t.Fatalf("Failed to generate: %v", err)
+					}
+					if !re.MatchString(value.(string)) {
+						t.Errorf("Expected a valid %s, got %q", tt.format, value)
+					}
+				}
+			}
+
+			// The same seed yields the same pointer
+			a, _ := NewGenerator(9).GenerateFromSchema(schema)
+			b, _ := NewGenerator(9).GenerateFromSchema(schema)
+			if a != b {
+				t.Errorf("Expected seeded pointers to match, got %q and %q", a, b)
+			}
+		})
+	}
+}
+
+func TestJSONPointerEscaping(t *testing.T) {
+	if got := jsonPointerEscaper.Replace("a/b~c"); got != "a~1b~0c" {
+		t.Errorf("Expected a~1b~0c, got %s", got)
+	}
+}
+
+func TestIRIFormats(t *testing.T) {
+	tests := []struct {
+		format   string
+		absolute bool
+	}{
+		{format: "iri", absolute: true},
+		{format: "iri-reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			schema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: tt.format}
+			nonASCII := false
+			for seed := int64(0); seed < 50; seed++ {
+				value, err := NewGenerator(seed).GenerateFromSchema(schema)
+				if err != nil {
+					t.Fatalf("Failed to generate: %v", err)
+				}
+				iri := value.(string)
+				parsed, err := url.Parse(iri)
+				if err != nil {
+					t.Fatalf("Expected %q to parse as an IRI: %v", iri, err)
+				}
+				if tt.absolute && (parsed.Scheme != "https" || parsed.Host != "example.com") {
+					t.Errorf("Expected an absolute IRI, got %q", iri)
+				}
+				if !strings.HasPrefix(parsed.Path, "/") || !utf8.ValidString(iri) {
+					t.Errorf("Expected a valid IRI path, got %q", iri)
+				}
+				if strings.ContainsFunc(iri, func(r rune) bool { return r > unicode.MaxASCII }) {
+					nonASCII = true
+				}
+			}
+			if !nonASCII {
+				t.Errorf("Expected some %s values to contain non-ASCII characters", tt.format)
+			}
+
+			// The same seed yields the same IRI
+			a, _ := NewGenerator(9).GenerateFromSchema(schema)
+			b, _ := NewGenerator(9).GenerateFromSchema(schema)
+			if a != b {
+				t.Errorf("Expected seeded IRIs to match, got %q and %q", a, b)
+			}
+		})
+	}
+}
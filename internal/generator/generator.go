@@ -1,38 +1,167 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/Vooblin/mocktail/internal/parser"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// PayloadContext describes which side of an API exchange a payload is generated for
+type PayloadContext int
+
+const (
+	// ContextAny includes every property regardless of readOnly/writeOnly
+	ContextAny PayloadContext = iota
+	// ContextRequest omits readOnly properties, which clients must not send
+	ContextRequest
+	// ContextResponse omits writeOnly properties, which servers never return
+	ContextResponse
+)
+
+// GenerateOptions tunes how a Generator produces data
+type GenerateOptions struct {
+	Context PayloadContext
+	// Locale names a registered locale (see RegisterLocale); defaults to "en"
+	Locale string
+	// WordList, if set, replaces the locale's words for generic strings
+	WordList []string
+	// BinarySize is the length of generated file downloads; defaults to 1 KiB
+	BinarySize int
+	// UseDefaults generates a schema's declared default instead of random data, as a
+	// server applying defaults would return. When maxProperties forces optional
+	// properties out, those with a default are kept first; properties left out for
+	// readOnly/writeOnly stay out even when they have a default.
+	UseDefaults bool
+	// PreferExamples makes GenerateResponse return a response's media-type example,
+	// when the spec has one, instead of generated data
+	PreferExamples bool
+	// MergeAnyOf merges a seeded selection of one to all object branches of an anyOf
+	// instead of always picking a single branch
+	MergeAnyOf bool
+	// ProtoJSON follows the protobuf JSON mapping used by gRPC-gateway APIs: well-known
+	// types such as google.protobuf.Timestamp and Duration become their canonical
+	// strings, 64-bit integers are quoted, and integer enums with x-enum-varnames are
+	// written by name
+	ProtoJSON bool
+	// Consistent aligns reference fields with the objects they name within a generated
+	// document, so a userId matches the id of a sibling or nested user object
+	Consistent bool
+	// CoverEnums makes arrays of enum items include every enum member at least once
+	// when their length allows, growing up to maxItems to fit them, so fixtures
+	// exercise every category
+	CoverEnums bool
+	// EmailDomain is the domain of generated emails; defaults to the locale's domain,
+	// example.com for "en"
+	EmailDomain string
+	// NameEmails derives the local part of generated emails from a name field in the
+	// same object, so {"name": "John Doe"} gets an email like john.doe@example.com
+	NameEmails bool
+	// JSONSafeIntegers caps generated integers at ±(2^53-1), the range JavaScript
+	// numbers hold exactly, even where the schema allows larger values
+	JSONSafeIntegers bool
+	// Now is the time generated dates and timestamps count back from; zero uses the
+	// current time. A fixed value keeps seeded output identical from second to second.
+	Now time.Time
+}
+
+// now returns Now, or the current time when it is unset
+func (o GenerateOptions) now() time.Time {
+	if o.Now.IsZero() {
+		return time.Now()
+	}
+	return o.Now
+}
+
 // Generator creates mock data from OpenAPI schemas
 type Generator struct {
-	rng *rand.Rand
+	rng     *rand.Rand
+	opts    GenerateOptions
+	locale  Locale
+	formats map[string]FormatGenerator
 }
 
 // NewGenerator creates a new generator with a seed for reproducibility
 func NewGenerator(seed int64) *Generator {
+	return NewGeneratorWithOptions(seed, GenerateOptions{})
+}
+
+// ParseSeed converts a --seed value into a generator seed. Integers are used
+// as-is; any other string is hashed with FNV-1a so datasets can have memorable names.
+func ParseSeed(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	if seed, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seed
+	}
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	return int64(h.Sum64())
+}
+
+// NewGeneratorWithOptions creates a new seeded generator with custom options
+func NewGeneratorWithOptions(seed int64, opts GenerateOptions) *Generator {
+	locale := resolveLocale(opts)
 	return &Generator{
-		rng: rand.New(rand.NewSource(seed)),
+		rng:     rand.New(rand.NewSource(seed)),
+		opts:    opts,
+		locale:  locale,
+		formats: builtinFormats(locale, opts.EmailDomain, opts.now),
 	}
 }
 
+// WithContext returns a generator for the given payload context that shares this
+// generator's random source and formats, so interleaved calls stay reproducible
+func (g *Generator) WithContext(ctx PayloadContext) *Generator {
+	opts := g.opts
+	opts.Context = ctx
+	return &Generator{rng: g.rng, opts: opts, locale: g.locale, formats: g.formats}
+}
+
 // GenerateFromSchema generates mock data from an OpenAPI schema
 func (g *Generator) GenerateFromSchema(schema *openapi3.Schema) (interface{}, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
 
+	if len(schema.AllOf) > 0 {
+		return g.GenerateFromSchema(parser.MergeAllOf(schema))
+	}
+
+	if schema.Not != nil && schema.Not.Value != nil {
+		return g.generateNot(schema)
+	}
+
+	if g.opts.UseDefaults && schema.Default != nil {
+		return copyValue(schema.Default), nil
+	}
+
+	if g.opts.ProtoJSON {
+		if value, ok := g.generateProtoJSON(schema); ok {
+			return value, nil
+		}
+	}
+
+	if len(schema.AnyOf) > 0 {
+		return g.generateAnyOf(schema)
+	}
+
 	// Handle schema references
 	if schema.Type == nil || len(schema.Type.Slice()) == 0 {
 		// Default to object if no type specified
 		return g.generateObject(schema)
 	}
 
-	schemaType := schema.Type.Slice()[0]
+	schemaType := PrimaryType(schema.Type)
 
 	switch schemaType {
 	case "string":
@@ -47,11 +176,57 @@ func (g *Generator) GenerateFromSchema(schema *openapi3.Schema) (interface{}, er
 		return g.generateArray(schema)
 	case "object":
 		return g.generateObject(schema)
+	case "null":
+		return nil, nil
 	default:
 		return nil, fmt.Errorf("unsupported schema type: %s", schemaType)
 	}
 }
 
+// PrimaryType returns the type to generate for a schema's type list. OpenAPI 3.1 allows
+// several, such as ["string", "null"] for a nullable string; the first non-null one is
+// used, and "null" only when it is the sole type.
+func PrimaryType(types *openapi3.Types) string {
+	for _, t := range types.Slice() {
+		if t != openapi3.TypeNull {
+			return t
+		}
+	}
+	if types.Includes(openapi3.TypeNull) {
+		return openapi3.TypeNull
+	}
+	return ""
+}
+
+// GenerateJSON generates mock data from a schema and marshals it to compact JSON
+func (g *Generator) GenerateJSON(schema *openapi3.Schema) ([]byte, error) {
+	value, err := g.GenerateFromSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
+// GenerateJSONIndent generates mock data from a schema and marshals it to
+// JSON indented with two spaces
+func (g *Generator) GenerateJSONIndent(schema *openapi3.Schema) ([]byte, error) {
+	value, err := g.GenerateFromSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
 // generateString generates a string value based on format and constraints
 func (g *Generator) generateString(schema *openapi3.Schema) string {
 	// Check for enum values
@@ -62,40 +237,41 @@ func (g *Generator) generateString(schema *openapi3.Schema) string {
 		}
 	}
 
-	// Generate based on format
-	switch schema.Format {
-	case "date-time":
-		return time.Now().Add(-time.Duration(g.rng.Intn(365*24)) * time.Hour).Format(time.RFC3339)
-	case "date":
-		return time.Now().Add(-time.Duration(g.rng.Intn(365)) * 24 * time.Hour).Format("2006-01-02")
-	case "email":
-		return fmt.Sprintf("user%d@example.com", g.rng.Intn(1000))
-	case "uuid":
-		return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
-			g.rng.Uint32(),
-			uint16(g.rng.Uint32()),
-			uint16(g.rng.Uint32())|0x4000,
-			uint16(g.rng.Uint32())|0x8000,
-			uint64(g.rng.Uint32())<<16|uint64(g.rng.Uint32()>>16))
-	case "uri":
-		return fmt.Sprintf("https://example.com/resource/%d", g.rng.Intn(1000))
-	default:
-		// Generate a generic string
-		words := []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "theta"}
-		return words[g.rng.Intn(len(words))]
+	// Registered formats take precedence over generic words
+	if fn, ok := g.formats[schema.Format]; ok && schema.Format != "" {
+		return fn(g.rng, schema)
+	}
+
+	// 64-bit ids may also be flagged with the x-mocktail-int64 extension
+	if flag, _ := schema.Extensions["x-mocktail-int64"].(bool); flag {
+		return generateStringID(g.rng, schema)
 	}
+
+	// Generate a generic string
+	words := g.locale.Words
+	return words[g.rng.Intn(len(words))]
 }
 
-// generateInteger generates an integer value respecting min/max constraints
+// maxSafeInteger is the largest integer a JavaScript number represents exactly, 2^53-1
+const maxSafeInteger = 1<<53 - 1
+
+// generateInteger generates an integer value respecting min/max constraints,
+// including exclusive bounds, within ±maxSafeInteger with JSONSafeIntegers
 func (g *Generator) generateInteger(schema *openapi3.Schema) int64 {
 	min := int64(0)
 	max := int64(100)
 
 	if schema.Min != nil {
-		min = int64(*schema.Min)
+		min = int64(math.Ceil(g.safeBound(*schema.Min)))
+		if schema.ExclusiveMin && float64(min) == *schema.Min {
+			min++
+		}
 	}
 	if schema.Max != nil {
-		max = int64(*schema.Max)
+		max = int64(math.Floor(g.safeBound(*schema.Max)))
+		if schema.ExclusiveMax && float64(max) == *schema.Max {
+			max--
+		}
 	}
 
 	if max <= min {
@@ -105,7 +281,15 @@ func (g *Generator) generateInteger(schema *openapi3.Schema) int64 {
 	return min + int64(g.rng.Int63n(max-min+1))
 }
 
-// generateNumber generates a floating-point number
+// safeBound limits an integer bound to ±maxSafeInteger with JSONSafeIntegers
+func (g *Generator) safeBound(bound float64) float64 {
+	if !g.opts.JSONSafeIntegers {
+		return bound
+	}
+	return math.Max(-maxSafeInteger, math.Min(bound, maxSafeInteger))
+}
+
+// generateNumber generates a floating-point number, never returning an exclusive bound
 func (g *Generator) generateNumber(schema *openapi3.Schema) float64 {
 	min := 0.0
 	max := 100.0
@@ -121,7 +305,16 @@ func (g *Generator) generateNumber(schema *openapi3.Schema) float64 {
 		return min
 	}
 
-	return min + g.rng.Float64()*(max-min)
+	// Float64 is in [0, 1), so only the lower bound can be hit exactly
+	value := min + g.rng.Float64()*(max-min)
+	if schema.ExclusiveMin && value == min {
+		value = math.Nextafter(min, max)
+	}
+	if schema.ExclusiveMax && value >= max {
+		value = math.Nextafter(max, min)
+	}
+
+	return value
 }
 
 // generateBoolean generates a random boolean value
@@ -135,31 +328,81 @@ func (g *Generator) generateArray(schema *openapi3.Schema) ([]interface{}, error
 		return []interface{}{}, nil
 	}
 
-	// Determine array length (default 2-5 items)
+	if positions := tuplePositions(schema); positions > 0 {
+		return g.generateTuple(schema, positions)
+	}
+	if minContains, maxContains, ok := containsBounds(schema); ok {
+		return g.generateContains(schema, minContains, maxContains)
+	}
+
+	length := g.arrayLength(schema)
+
+	if enum := schema.Items.Value.Enum; g.opts.CoverEnums && len(enum) > 0 {
+		// Grow to fit every member unless maxItems forbids it; unique items can't repeat any
+		fit := len(enum)
+		if schema.MaxItems != nil {
+			fit = min(fit, int(*schema.MaxItems))
+		}
+		length = max(length, fit)
+		if schema.UniqueItems {
+			length = min(length, len(enum))
+		}
+		return g.coverEnum(enum, length)
+	}
+
+	result := make([]interface{}, length)
+	for i := 0; i < length; i++ {
+		item, err := g.GenerateFromSchema(schema.Items.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate array item: %w", err)
+		}
+		result[i] = item
+	}
+
+	return result, nil
+}
+
+// arrayLength picks an array's length within minItems and maxItems, 2-5 by default. A
+// declared maxItems, 0 included, is never exceeded.
+func (g *Generator) arrayLength(schema *openapi3.Schema) int {
 	minItems := 2
 	maxItems := 5
 
 	if schema.MinItems > 0 {
 		minItems = int(schema.MinItems)
 	}
-	if schema.MaxItems != nil && *schema.MaxItems > 0 {
+	if schema.MaxItems != nil {
 		maxItems = int(*schema.MaxItems)
+		minItems = min(minItems, maxItems)
+	} else if minItems > maxItems {
+		// minItems always wins; without a maxItems, keep the default spread above it
+		maxItems = minItems + 3
 	}
 
-	length := minItems
 	if maxItems > minItems {
-		length = minItems + g.rng.Intn(maxItems-minItems+1)
+		return minItems + g.rng.Intn(maxItems-minItems+1)
 	}
+	return minItems
+}
 
+// coverEnum generates an array of enum items holding every member at least once, as
+// far as length allows, with the remaining slots drawn at random and the order shuffled
+func (g *Generator) coverEnum(enum []interface{}, length int) ([]interface{}, error) {
 	result := make([]interface{}, length)
-	for i := 0; i < length; i++ {
-		item, err := g.GenerateFromSchema(schema.Items.Value)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate array item: %w", err)
+	for i := range result {
+		if i < len(enum) {
+			result[i] = enum[i]
+		} else {
+			result[i] = enum[g.rng.Intn(len(enum))]
 		}
-		result[i] = item
 	}
-
+	if length < len(enum) {
+		// Too short for every member: pick which ones appear at random
+		for i, j := range g.rng.Perm(len(enum))[:length] {
+			result[i] = enum[j]
+		}
+	}
+	g.rng.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
 	return result, nil
 }
 
@@ -167,32 +410,199 @@ func (g *Generator) generateArray(schema *openapi3.Schema) ([]interface{}, error
 func (g *Generator) generateObject(schema *openapi3.Schema) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
-	if schema.Properties == nil {
-		return result, nil
+	// Iterate in sorted order so the same seed always yields the same object
+	var names []string
+	for _, propName := range sortedPropertyNames(schema.Properties) {
+		propRef := schema.Properties[propName]
+		if propRef.Value != nil && !g.omitProperty(propRef.Value) {
+			names = append(names, propName)
+		}
 	}
 
-	for propName, propRef := range schema.Properties {
-		if propRef.Value == nil {
-			continue
+	if schema.MinProps > 0 || schema.MaxProps != nil {
+		var err error
+		if names, err = g.pickProperties(schema, names); err != nil {
+			return nil, err
 		}
+	}
 
-		value, err := g.GenerateFromSchema(propRef.Value)
+	for _, propName := range names {
+		var value interface{}
+		var err error
+		if propRef, declared := schema.Properties[propName]; declared {
+			value, err = g.GenerateFromSchema(propRef.Value)
+		} else {
+			// Keys beyond the declared properties come from additionalProperties
+			value, err = g.generateAdditionalProperty(schema)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate property %s: %w", propName, err)
 		}
 		result[propName] = value
 	}
 
+	if g.opts.NameEmails {
+		alignEmails(schema, result)
+	}
+	if g.opts.Consistent {
+		alignReferences(result)
+	}
+
 	return result, nil
 }
 
-// GenerateResponse generates a mock response for an OpenAPI operation
+// generateAnyOf generates a value matching at least one anyOf branch. Object branches
+// are merged, together with the schema's own properties: one branch by default, or a
+// seeded selection of one to all of them with MergeAnyOf. When any branch is not an
+// object, a single branch is picked.
+func (g *Generator) generateAnyOf(schema *openapi3.Schema) (interface{}, error) {
+	var branches []*openapi3.Schema
+	objects := true
+	for _, ref := range schema.AnyOf {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		branches = append(branches, ref.Value)
+		objects = objects && isObjectSchema(ref.Value)
+	}
+	if len(branches) == 0 {
+		return g.generateObject(schema)
+	}
+	if !objects {
+		return g.GenerateFromSchema(branches[g.rng.Intn(len(branches))])
+	}
+
+	count := 1
+	if g.opts.MergeAnyOf {
+		count += g.rng.Intn(len(branches))
+	}
+	// Merge in declaration order so later branches win on shared keys
+	picked := g.rng.Perm(len(branches))[:count]
+	sort.Ints(picked)
+
+	merged, err := g.generateObject(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range picked {
+		value, err := g.GenerateFromSchema(branches[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate anyOf branch: %w", err)
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, v := range object {
+				merged[key] = v
+			}
+		}
+	}
+	if g.opts.Consistent {
+		alignReferences(merged)
+	}
+	return merged, nil
+}
+
+// isObjectSchema reports whether a schema describes an object: typed as one, or
+// untyped with properties
+func isObjectSchema(schema *openapi3.Schema) bool {
+	if schema.Type == nil || len(schema.Type.Slice()) == 0 {
+		return len(schema.Properties) > 0 || len(schema.AnyOf) > 0
+	}
+	return schema.Type.Is("object")
+}
+
+// pickProperties chooses which keys an object with minProperties/maxProperties gets:
+// every required property, a seeded selection of optional ones, and generated extra
+// keys when the declared properties can't reach minProperties
+func (g *Generator) pickProperties(schema *openapi3.Schema, names []string) ([]string, error) {
+	var required, defaulted, optional []string
+	for _, name := range names {
+		switch {
+		case slices.Contains(schema.Required, name):
+			required = append(required, name)
+		case g.opts.UseDefaults && schema.Properties[name].Value.Default != nil:
+			defaulted = append(defaulted, name)
+		default:
+			optional = append(optional, name)
+		}
+	}
+
+	low := max(int(schema.MinProps), len(required))
+	count := max(low, len(names))
+	if schema.MaxProps != nil {
+		high := int(*schema.MaxProps)
+		if high < low {
+			return nil, fmt.Errorf("object needs at least %d properties but maxProperties is %d", low, high)
+		}
+		// Defaulted properties are kept as far as maxProperties allows
+		low = max(low, min(len(required)+len(defaulted), high))
+		count = low + g.rng.Intn(high-low+1)
+	}
+
+	g.rng.Shuffle(len(optional), func(i, j int) { optional[i], optional[j] = optional[j], optional[i] })
+	if len(defaulted) > 0 {
+		// A server applying defaults fills these in, so they are the last to be left out
+		g.rng.Shuffle(len(defaulted), func(i, j int) { defaulted[i], defaulted[j] = defaulted[j], defaulted[i] })
+		optional = append(defaulted, optional...)
+	}
+	picked := append(required, optional[:min(len(optional), count-len(required))]...)
+
+	if extra := count - len(picked); extra > 0 {
+		if schema.AdditionalProperties.Has != nil && !*schema.AdditionalProperties.Has {
+			return nil, fmt.Errorf("minProperties %d exceeds the %d declared properties and additionalProperties is false", schema.MinProps, len(names))
+		}
+		for i := 1; extra > 0; i++ {
+			key := fmt.Sprintf("key%d", i)
+			if _, declared := schema.Properties[key]; !declared {
+				picked = append(picked, key)
+				extra--
+			}
+		}
+	}
+
+	sort.Strings(picked)
+	return picked, nil
+}
+
+// generateAdditionalProperty generates a value for a key that isn't a declared property,
+// using the additionalProperties schema or a plain string for free-form objects
+func (g *Generator) generateAdditionalProperty(schema *openapi3.Schema) (interface{}, error) {
+	if ref := schema.AdditionalProperties.Schema; ref != nil && ref.Value != nil {
+		return g.GenerateFromSchema(ref.Value)
+	}
+	return g.generateString(&openapi3.Schema{}), nil
+}
+
+// omitProperty reports whether a property doesn't belong in the current payload context.
+// readOnly/writeOnly win over required: a required readOnly id is still left out of requests.
+func (g *Generator) omitProperty(schema *openapi3.Schema) bool {
+	switch g.opts.Context {
+	case ContextRequest:
+		return schema.ReadOnly
+	case ContextResponse:
+		return schema.WriteOnly
+	default:
+		return false
+	}
+}
+
+// sortedPropertyNames returns the property names of a schema map in sorted order
+func sortedPropertyNames(properties openapi3.Schemas) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenerateResponse generates a mock response for an OpenAPI operation from the response
+// declared for statusCode, or from the "default" response when that status has none
 func (g *Generator) GenerateResponse(operation *openapi3.Operation, statusCode string) (interface{}, error) {
 	if operation == nil || operation.Responses == nil {
 		return nil, fmt.Errorf("operation or responses is nil")
 	}
 
-	responseRef := operation.Responses.Value(statusCode)
+	responseRef := findResponse(operation.Responses, statusCode)
 	if responseRef == nil {
 		return nil, fmt.Errorf("no response defined for status code %s", statusCode)
 	}
@@ -202,11 +612,63 @@ func (g *Generator) GenerateResponse(operation *openapi3.Operation, statusCode s
 		return map[string]interface{}{}, nil
 	}
 
-	// Look for application/json content
-	jsonContent := response.Content.Get("application/json")
+	if g.opts.PreferExamples {
+		if example, ok := ResponseExample(operation, statusCode); ok {
+			return copyValue(example), nil
+		}
+	}
+
+	// Look for application/json content, or a +json vendor type
+	_, jsonContent := JSONMediaType(response.Content)
 	if jsonContent == nil || jsonContent.Schema == nil || jsonContent.Schema.Value == nil {
 		return map[string]interface{}{}, nil
 	}
 
-	return g.GenerateFromSchema(jsonContent.Schema.Value)
+	return g.WithContext(ContextResponse).GenerateFromSchema(jsonContent.Schema.Value)
+}
+
+// findResponse returns the response declared for statusCode, falling back to the
+// "default" response, or nil if neither exists
+func findResponse(responses *openapi3.Responses, statusCode string) *openapi3.ResponseRef {
+	if responseRef := responses.Value(statusCode); responseRef != nil {
+		return responseRef
+	}
+	return responses.Default()
+}
+
+// ResponseExample returns the example declared on a response's JSON media type, as
+// opposed to its named examples. The value belongs to the spec; copy it before changing it.
+func ResponseExample(operation *openapi3.Operation, statusCode string) (interface{}, bool) {
+	if operation == nil || operation.Responses == nil {
+		return nil, false
+	}
+	responseRef := findResponse(operation.Responses, statusCode)
+	if responseRef == nil || responseRef.Value == nil {
+		return nil, false
+	}
+	_, jsonContent := JSONMediaType(responseRef.Value.Content)
+	if jsonContent == nil || jsonContent.Example == nil {
+		return nil, false
+	}
+	return jsonContent.Example, true
+}
+
+// copyValue returns a deep copy of a decoded JSON value, so callers can modify a served
+// example or default without changing the spec it came from
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = copyValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = copyValue(item)
+		}
+		return result
+	}
+	return value
 }
@@ -1,7 +1,12 @@
 package generator
 
 import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -59,6 +64,47 @@ func TestGenerateString(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "int64 string id",
+			schema: &openapi3.Schema{
+				Type:   &openapi3.Types{"string"},
+				Format: "int64",
+			},
+			check: func(t *testing.T, result string) {
+				if _, err := strconv.ParseInt(result, 10, 64); err != nil || len(result) < 18 {
+					t.Errorf("Expected 64-bit numeric string id, got: %s", result)
+				}
+			},
+		},
+		{
+			name: "snowflake via extension",
+			schema: &openapi3.Schema{
+				Type:       &openapi3.Types{"string"},
+				Extensions: map[string]interface{}{"x-mocktail-int64": true},
+			},
+			check: func(t *testing.T, result string) {
+				if _, err := strconv.ParseInt(result, 10, 64); err != nil {
+					t.Errorf("Expected numeric string id, got: %s", result)
+				}
+			},
+		},
+		{
+			name: "decimal format",
+			schema: &openapi3.Schema{
+				Type:   &openapi3.Types{"string"},
+				Format: "decimal",
+				Min:    float64Ptr(-5),
+				Max:    float64Ptr(5),
+			},
+			check: func(t *testing.T, result string) {
+				if !regexp.MustCompile(`^-?\d+\.\d{2}$`).MatchString(result) {
+					t.Fatalf("Expected decimal with two fraction digits, got: %s", result)
+				}
+				if value, _ := strconv.ParseFloat(result, 64); value < -5 || value > 5 {
+					t.Errorf("Expected decimal in range [-5, 5], got: %s", result)
+				}
+			},
+		},
 		{
 			name: "date-time format",
 			schema: &openapi3.Schema{
@@ -127,6 +173,21 @@ func TestGenerateInteger(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "integer with exclusive min/max",
+			schema: &openapi3.Schema{
+				Type:         &openapi3.Types{"integer"},
+				Min:          float64Ptr(10),
+				Max:          float64Ptr(12),
+				ExclusiveMin: true,
+				ExclusiveMax: true,
+			},
+			check: func(t *testing.T, result int64) {
+				if result != 11 {
+					t.Errorf("Expected 11 for exclusive range (10, 12), got: %d", result)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -137,6 +198,67 @@ func TestGenerateInteger(t *testing.T) {
 	}
 }
 
+func TestGenerateDatesFromNow(t *testing.T) {
+	now := time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC)
+	schema := openapi3.NewDateTimeSchema()
+
+	for seed := int64(1); seed <= 20; seed++ {
+		value := NewGeneratorWithOptions(seed, GenerateOptions{Now: now}).generateString(schema)
+		generated, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("Seed %d: expected an RFC 3339 date-time, got %q", seed, value)
+		}
+		if generated.After(now) || generated.Before(now.AddDate(-1, 0, 0)) {
+			t.Errorf("Seed %d: expected a date-time in the year before %v, got %v", seed, now, generated)
+		}
+	}
+}
+
+func TestGenerateIntegerJSONSafe(t *testing.T) {
+	const limit = 1<<53 - 1
+	tests := []struct {
+		name     string
+		schema   *openapi3.Schema
+		safe     bool
+		expected func(n int64) bool
+	}{
+		{
+			name:     "above the safe range",
+			schema:   &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64", Min: float64Ptr(1 << 60), Max: float64Ptr(1 << 62)},
+			safe:     true,
+			expected: func(n int64) bool { return n == limit },
+		},
+		{
+			name:     "below the safe range",
+			schema:   &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: float64Ptr(-(1 << 62)), Max: float64Ptr(-(1 << 60))},
+			safe:     true,
+			expected: func(n int64) bool { return n == -limit },
+		},
+		{
+			name:     "spanning the safe range",
+			schema:   &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: float64Ptr(-(1 << 62)), Max: float64Ptr(1 << 62)},
+			safe:     true,
+			expected: func(n int64) bool { return n >= -limit && n <= limit },
+		},
+		{
+			name:     "schema honored by default",
+			schema:   &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: float64Ptr(1 << 60), Max: float64Ptr(1 << 62)},
+			expected: func(n int64) bool { return n >= 1<<60 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				gen := NewGeneratorWithOptions(seed, GenerateOptions{JSONSafeIntegers: tt.safe})
+				if n := gen.generateInteger(tt.schema); !tt.expected(n) {
+					t.Fatalf("Unexpected integer %d", n)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateNumber(t *testing.T) {
 	gen := NewGenerator(42)
 
@@ -169,6 +291,21 @@ func TestGenerateNumber(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "number with exclusive min/max",
+			schema: &openapi3.Schema{
+				Type:         &openapi3.Types{"number"},
+				Min:          float64Ptr(0),
+				Max:          float64Ptr(1),
+				ExclusiveMin: true,
+				ExclusiveMax: true,
+			},
+			check: func(t *testing.T, result float64) {
+				if result <= 0 || result >= 1 {
+					t.Errorf("Expected number in exclusive range (0, 1), got: %f", result)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,6 +377,66 @@ func TestGenerateArray(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "minItems above the default max",
+			schema: &openapi3.Schema{
+				Type:     &openapi3.Types{"array"},
+				MinItems: 10,
+				Items: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: &openapi3.Types{"integer"},
+					},
+				},
+			},
+			check: func(t *testing.T, result []interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(result) < 10 || len(result) > 13 {
+					t.Errorf("Expected array length 10-13, got: %d", len(result))
+				}
+			},
+		},
+		{
+			name: "maxItems below the default min",
+			schema: &openapi3.Schema{
+				Type:     &openapi3.Types{"array"},
+				MaxItems: uint64Ptr(1),
+				Items: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: &openapi3.Types{"integer"},
+					},
+				},
+			},
+			check: func(t *testing.T, result []interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(result) != 1 {
+					t.Errorf("Expected array length 1, got: %d", len(result))
+				}
+			},
+		},
+		{
+			name: "maxItems 0",
+			schema: &openapi3.Schema{
+				Type:     &openapi3.Types{"array"},
+				MaxItems: uint64Ptr(0),
+				Items: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: &openapi3.Types{"integer"},
+					},
+				},
+			},
+			check: func(t *testing.T, result []interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(result) != 0 {
+					t.Errorf("Expected empty array, got: %d items", len(result))
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -250,6 +447,62 @@ func TestGenerateArray(t *testing.T) {
 	}
 }
 
+func TestGenerateArrayCoverEnums(t *testing.T) {
+	colors := []interface{}{"red", "green", "blue", "yellow", "purple", "orange", "black"}
+	enumArray := func(minItems uint64, maxItems *uint64, unique bool) *openapi3.Schema {
+		return &openapi3.Schema{
+			Type:        &openapi3.Types{"array"},
+			MinItems:    minItems,
+			MaxItems:    maxItems,
+			UniqueItems: unique,
+			Items:       &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: colors}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		schema  *openapi3.Schema
+		covered int // distinct members expected
+		minLen  int
+		maxLen  int
+	}{
+		{name: "grows to fit every member", schema: enumArray(0, nil, false), covered: 7, minLen: 7, maxLen: 7},
+		{name: "longer arrays fill the rest", schema: enumArray(10, uint64Ptr(12), false), covered: 7, minLen: 10, maxLen: 12},
+		{name: "maxItems caps the coverage", schema: enumArray(0, uint64Ptr(4), false), covered: 4, minLen: 4, maxLen: 4},
+		{name: "unique items never repeat", schema: enumArray(10, uint64Ptr(12), true), covered: 7, minLen: 7, maxLen: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 10; seed++ {
+				result, err := NewGeneratorWithOptions(seed, GenerateOptions{CoverEnums: true}).generateArray(tt.schema)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(result) < tt.minLen || len(result) > tt.maxLen {
+					t.Fatalf("Expected %d to %d items, got %d", tt.minLen, tt.maxLen, len(result))
+				}
+				seen := make(map[interface{}]bool)
+				for _, item := range result {
+					if !slices.Contains(colors, item) {
+						t.Fatalf("Expected only enum members, got %v", item)
+					}
+					seen[item] = true
+				}
+				if len(seen) != tt.covered {
+					t.Errorf("Expected %d distinct members, got %v", tt.covered, result)
+				}
+			}
+		})
+	}
+
+	// Without the option the draw stays plain random
+	result, _ := NewGenerator(1).generateArray(enumArray(0, nil, false))
+	if len(result) > 5 {
+		t.Errorf("Expected the default length of 2-5 without CoverEnums, got %d", len(result))
+	}
+}
+
 func TestGenerateObject(t *testing.T) {
 	gen := NewGenerator(42)
 
@@ -307,6 +560,71 @@ func TestGenerateObject(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "minProperties map",
+			schema: &openapi3.Schema{
+				Type:     &openapi3.Types{"object"},
+				MinProps: 3,
+				AdditionalProperties: openapi3.AdditionalProperties{
+					Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+				},
+			},
+			check: func(t *testing.T, result map[string]interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(result) < 3 {
+					t.Errorf("Expected at least 3 keys, got %v", result)
+				}
+				for key, value := range result {
+					if _, ok := value.(int64); !ok {
+						t.Errorf("Expected %s to be int64, got: %T", key, value)
+					}
+				}
+			},
+		},
+		{
+			name: "maxProperties keeps required properties",
+			schema: &openapi3.Schema{
+				Type:     &openapi3.Types{"object"},
+				Required: []string{"id"},
+				MaxProps: openapi3.Uint64Ptr(2),
+				Properties: openapi3.Schemas{
+					"id":    &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+					"name":  &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+					"email": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+					"phone": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+				},
+			},
+			check: func(t *testing.T, result map[string]interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(result) < 1 || len(result) > 2 {
+					t.Errorf("Expected 1-2 keys, got %v", result)
+				}
+				if _, ok := result["id"]; !ok {
+					t.Error("Expected required 'id' property in object")
+				}
+			},
+		},
+		{
+			name: "unsatisfiable bounds",
+			schema: &openapi3.Schema{
+				Type:     &openapi3.Types{"object"},
+				Required: []string{"a", "b"},
+				MaxProps: openapi3.Uint64Ptr(1),
+				Properties: openapi3.Schemas{
+					"a": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+					"b": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+				},
+			},
+			check: func(t *testing.T, result map[string]interface{}, err error) {
+				if err == nil {
+					t.Error("Expected error for required properties exceeding maxProperties")
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -376,6 +694,66 @@ func TestGenerateFromSchema(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "3.1 nullable string",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"string", "null"},
+			},
+			check: func(t *testing.T, result interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if _, ok := result.(string); !ok {
+					t.Errorf("Expected string, got: %T", result)
+				}
+			},
+		},
+		{
+			name: "3.1 null listed first",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"null", "integer"},
+				Min:  openapi3.Float64Ptr(10),
+			},
+			check: func(t *testing.T, result interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if n, ok := result.(int64); !ok || n < 10 {
+					t.Errorf("Expected an int64 of at least 10, got: %v (%T)", result, result)
+				}
+			},
+		},
+		{
+			name: "3.1 nullable object",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"object", "null"},
+				Properties: openapi3.Schemas{
+					"name": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+				},
+			},
+			check: func(t *testing.T, result interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if obj, ok := result.(map[string]interface{}); !ok || obj["name"] == nil {
+					t.Errorf("Expected an object with a name, got: %v", result)
+				}
+			},
+		},
+		{
+			name: "3.1 null only",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"null"},
+			},
+			check: func(t *testing.T, result interface{}, err error) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if result != nil {
+					t.Errorf("Expected nil, got: %v", result)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -436,6 +814,283 @@ func TestDeterministicGeneration(t *testing.T) {
 
 // Helper functions
 
+func TestGenerateWithContext(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		Required: []string{"id", "password"},
+		Properties: openapi3.Schemas{
+			"id":       &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, ReadOnly: true}},
+			"password": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, WriteOnly: true}},
+			"name":     &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		ctx     PayloadContext
+		present []string
+		absent  []string
+	}{
+		{name: "any", ctx: ContextAny, present: []string{"id", "password", "name"}},
+		{name: "request", ctx: ContextRequest, present: []string{"password", "name"}, absent: []string{"id"}},
+		{name: "response", ctx: ContextResponse, present: []string{"id", "name"}, absent: []string{"password"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewGenerator(42).WithContext(tt.ctx).GenerateFromSchema(schema)
+			if err != nil {
+				t.Fatalf("Generation failed: %v", err)
+			}
+			obj := result.(map[string]interface{})
+			for _, key := range tt.present {
+				if _, ok := obj[key]; !ok {
+					t.Errorf("Expected '%s' in %v", key, obj)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := obj[key]; ok {
+					t.Errorf("Expected '%s' to be omitted from %v", key, obj)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		Required: []string{"name"},
+		Properties: openapi3.Schemas{
+			"name":   &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"status": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Default: "pending"}},
+			"limit":  &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Default: float64(25), Min: openapi3.Float64Ptr(100)}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		useDefaults bool
+		check       func(t *testing.T, obj map[string]interface{})
+	}{
+		{
+			name:        "defaults used",
+			useDefaults: true,
+			check: func(t *testing.T, obj map[string]interface{}) {
+				if obj["status"] != "pending" {
+					t.Errorf("Expected status default 'pending', got %v", obj["status"])
+				}
+				if obj["limit"] != float64(25) {
+					t.Errorf("Expected limit default 25, got %v", obj["limit"])
+				}
+				if _, ok := obj["name"].(string); !ok {
+					t.Errorf("Expected generated name, got %v", obj["name"])
+				}
+			},
+		},
+		{
+			name: "defaults ignored",
+			check: func(t *testing.T, obj map[string]interface{}) {
+				if limit, ok := obj["limit"].(int64); !ok || limit < 100 {
+					t.Errorf("Expected generated limit of at least 100, got %v", obj["limit"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGeneratorWithOptions(42, GenerateOptions{UseDefaults: tt.useDefaults})
+			result, err := gen.GenerateFromSchema(schema)
+			if err != nil {
+				t.Fatalf("Generation failed: %v", err)
+			}
+			tt.check(t, result.(map[string]interface{}))
+		})
+	}
+}
+
+func TestGenerateDefaultsFillOmittedProperties(t *testing.T) {
+	// maxProperties leaves room for one optional property; the defaulted one must win
+	schema := &openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		MaxProps: openapi3.Uint64Ptr(2),
+		Required: []string{"id"},
+		Properties: openapi3.Schemas{
+			"id":       &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"nickname": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"note":     &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"tags":     &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"array"}, Default: []interface{}{"new"}}},
+		},
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		result, err := NewGeneratorWithOptions(seed, GenerateOptions{UseDefaults: true}).GenerateFromSchema(schema)
+		if err != nil {
+			t.Fatalf("Generation failed: %v", err)
+		}
+		obj := result.(map[string]interface{})
+		tags, ok := obj["tags"].([]interface{})
+		if !ok || len(tags) != 1 || tags[0] != "new" {
+			t.Fatalf("Seed %d: expected the defaulted tags property, got %v", seed, obj)
+		}
+
+		// The default is served as a copy, so changing the result leaves the schema alone
+		tags[0] = "changed"
+		if schema.Properties["tags"].Value.Default.([]interface{})[0] != "new" {
+			t.Fatal("Expected the schema's default to stay unchanged")
+		}
+	}
+}
+
+func TestGenerateAnyOf(t *testing.T) {
+	str := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}
+	schema := &openapi3.Schema{
+		AnyOf: openapi3.SchemaRefs{
+			{Value: &openapi3.Schema{
+				Type:       &openapi3.Types{"object"},
+				Required:   []string{"email"},
+				Properties: openapi3.Schemas{"email": str, "verified": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"boolean"}}}},
+			}},
+			{Value: &openapi3.Schema{
+				Type:       &openapi3.Types{"object"},
+				Required:   []string{"phone"},
+				Properties: openapi3.Schemas{"phone": str},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		mergeAnyOf bool
+		check      func(t *testing.T, merged int)
+	}{
+		{
+			name: "single branch",
+			check: func(t *testing.T, merged int) {
+				if merged != 0 {
+					t.Errorf("Expected one branch per payload, got %d merged payloads", merged)
+				}
+			},
+		},
+		{
+			name:       "merged branches",
+			mergeAnyOf: true,
+			check: func(t *testing.T, merged int) {
+				if merged == 0 || merged == 50 {
+					t.Errorf("Expected a mix of single and merged payloads, got %d of 50 merged", merged)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := 0
+			for seed := int64(0); seed < 50; seed++ {
+				result, err := NewGeneratorWithOptions(seed, GenerateOptions{MergeAnyOf: tt.mergeAnyOf}).GenerateFromSchema(schema)
+				if err != nil {
+					t.Fatalf("Generation failed: %v", err)
+				}
+				obj := result.(map[string]interface{})
+				_, hasEmail := obj["email"]
+				_, hasVerified := obj["verified"]
+				_, hasPhone := obj["phone"]
+				if !hasEmail && !hasPhone {
+					t.Fatalf("Expected at least one branch, got %v", obj)
+				}
+				if hasVerified && !hasEmail {
+					t.Errorf("Expected the email branch's required field, got %v", obj)
+				}
+				if hasEmail && hasPhone {
+					merged++
+				}
+			}
+			tt.check(t, merged)
+		})
+	}
+
+	t.Run("non-object branches", func(t *testing.T) {
+		scalars := &openapi3.Schema{AnyOf: openapi3.SchemaRefs{str, {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}}}}
+		result, err := NewGeneratorWithOptions(1, GenerateOptions{MergeAnyOf: true}).GenerateFromSchema(scalars)
+		if err != nil {
+			t.Fatalf("Generation failed: %v", err)
+		}
+		switch result.(type) {
+		case string, int64:
+		default:
+			t.Errorf("Expected a string or integer, got %T", result)
+		}
+	})
+}
+
+func TestGenerateAllOf(t *testing.T) {
+	schema := &openapi3.Schema{
+		AllOf: openapi3.SchemaRefs{
+			openapi3.NewSchemaRef("#/components/schemas/Base", &openapi3.Schema{
+				Type:       &openapi3.Types{"object"},
+				Required:   []string{"id"},
+				Properties: openapi3.Schemas{"id": openapi3.NewIntegerSchema().NewRef()},
+			}),
+			openapi3.NewSchemaRef("", &openapi3.Schema{
+				Required:   []string{"createdAt"},
+				Properties: openapi3.Schemas{"createdAt": openapi3.NewDateTimeSchema().NewRef()},
+			}),
+		},
+	}
+
+	result, err := NewGenerator(42).GenerateFromSchema(schema)
+	if err != nil {
+		t.Fatalf("GenerateFromSchema failed: %v", err)
+	}
+	object, ok := result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected an object, got %T", result)
+	}
+	if _, ok := object["id"].(int64); !ok {
+		t.Errorf("Expected an integer id from the first branch, got %v", object["id"])
+	}
+	if _, ok := object["createdAt"].(string); !ok {
+		t.Errorf("Expected a createdAt string from the second branch, got %v", object["createdAt"])
+	}
+}
+
+func TestGenerateJSON(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"name": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+		},
+	}
+
+	compact, err := NewGenerator(42).GenerateJSON(schema)
+	if err != nil {
+		t.Fatalf("GenerateJSON failed: %v", err)
+	}
+	indented, err := NewGenerator(42).GenerateJSONIndent(schema)
+	if err != nil {
+		t.Fatalf("GenerateJSONIndent failed: %v", err)
+	}
+
+	if contains(string(compact), "\n") {
+		t.Errorf("Expected compact JSON on one line, got: %s", compact)
+	}
+	if !contains(string(indented), "\n  \"name\"") {
+		t.Errorf("Expected indented JSON, got: %s", indented)
+	}
+
+	var fromCompact, fromIndented map[string]interface{}
+	if err := json.Unmarshal(compact, &fromCompact); err != nil {
+		t.Fatalf("Compact output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(indented, &fromIndented); err != nil {
+		t.Fatalf("Indented output is not valid JSON: %v", err)
+	}
+	if fromCompact["name"] != fromIndented["name"] {
+		t.Errorf("Expected same payload for same seed, got %v and %v", fromCompact, fromIndented)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsHelper(s, substr))
 }
@@ -456,3 +1111,35 @@ func float64Ptr(f float64) *float64 {
 func uint64Ptr(u uint64) *uint64 {
 	return &u
 }
+
+func TestParseSeed(t *testing.T) {
+	if got := ParseSeed("42"); got != 42 {
+		t.Errorf("Expected numeric seed 42, got %d", got)
+	}
+	if got := ParseSeed(""); got != 0 {
+		t.Errorf("Expected empty seed to be 0, got %d", got)
+	}
+	if ParseSeed("release-candidate-3") == ParseSeed("release-candidate-4") {
+		t.Error("Expected different string seeds to hash differently")
+	}
+
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"id":   &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}},
+			"name": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+		},
+	}
+
+	first, err := NewGenerator(ParseSeed("release-candidate-3")).GenerateJSON(schema)
+	if err != nil {
+		t.Fatalf("First generation failed: %v", err)
+	}
+	second, err := NewGenerator(ParseSeed("release-candidate-3")).GenerateJSON(schema)
+	if err != nil {
+		t.Fatalf("Second generation failed: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("Expected identical output for the same string seed:\n%s\n%s", first, second)
+	}
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultLocale is the locale used when GenerateOptions names none
+const DefaultLocale = "en"
+
+// Locale is the corpus used for generic strings, emails, and URIs
+type Locale struct {
+	Words  []string
+	Domain string
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]Locale{
+		"en": {
+			Words:  []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "theta"},
+			Domain: "example.com",
+		},
+		"de": {
+			Words:  []string{"apfel", "baum", "haus", "wasser", "sonne", "berg", "fluss"},
+			Domain: "beispiel.example",
+		},
+		"es": {
+			Words:  []string{"manzana", "casa", "agua", "sol", "montaña", "río", "árbol"},
+			Domain: "ejemplo.example",
+		},
+	}
+)
+
+// RegisterLocale adds or replaces a named locale so GenerateOptions.Locale can select it
+func RegisterLocale(name string, locale Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[name] = locale
+}
+
+// LookupLocale returns the locale registered under name
+func LookupLocale(name string) (Locale, bool) {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	locale, ok := locales[name]
+	return locale, ok
+}
+
+// LocaleNames returns the registered locale names in sorted order
+func LocaleNames() []string {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveLocale picks the locale for a set of options, applying any WordList override.
+// Unknown names and empty fields fall back to the default locale.
+func resolveLocale(opts GenerateOptions) Locale {
+	fallback, _ := LookupLocale(DefaultLocale)
+	locale, ok := LookupLocale(opts.Locale)
+	if !ok {
+		locale = fallback
+	}
+	if len(opts.WordList) > 0 {
+		locale.Words = opts.WordList
+	}
+	if len(locale.Words) == 0 {
+		locale.Words = fallback.Words
+	}
+	if locale.Domain == "" {
+		locale.Domain = fallback.Domain
+	}
+	return locale
+}
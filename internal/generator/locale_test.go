@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestLocales(t *testing.T) {
+	RegisterLocale("pirate", Locale{Words: []string{"arr"}, Domain: "ship.test"})
+
+	tests := []struct {
+		name  string
+		opts  GenerateOptions
+		check func(t *testing.T, word, email string)
+	}{
+		{
+			name: "default locale",
+			check: func(t *testing.T, word, email string) {
+				if !strings.HasSuffix(email, "@example.com") {
+					t.Errorf("Expected example.com email, got %s", email)
+				}
+			},
+		},
+		{
+			name: "built-in locale",
+			opts: GenerateOptions{Locale: "de"},
+			check: func(t *testing.T, word, email string) {
+				if !strings.HasSuffix(email, "@beispiel.example") {
+					t.Errorf("Expected beispiel.example email, got %s", email)
+				}
+			},
+		},
+		{
+			name: "registered locale",
+			opts: GenerateOptions{Locale: "pirate"},
+			check: func(t *testing.T, word, email string) {
+				if word != "arr" || !strings.HasSuffix(email, "@ship.test") {
+					t.Errorf("Expected pirate corpus, got %s and %s", word, email)
+				}
+			},
+		},
+		{
+			name: "word list override",
+			opts: GenerateOptions{Locale: "de", WordList: []string{"widget"}},
+			check: func(t *testing.T, word, email string) {
+				if word != "widget" || !strings.HasSuffix(email, "@beispiel.example") {
+					t.Errorf("Expected custom words with the locale's domain, got %s and %s", word, email)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGeneratorWithOptions(1, tt.opts)
+			word, err := gen.GenerateFromSchema(&openapi3.Schema{Type: &openapi3.Types{"string"}})
+			if err != nil {
+				t.Fatalf("Failed to generate word: %v", err)
+			}
+			email, err := gen.GenerateFromSchema(&openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "email"})
+			if err != nil {
+				t.Fatalf("Failed to generate email: %v", err)
+			}
+			tt.check(t, word.(string), email.(string))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// JSONMediaType returns the JSON media type a content map declares and its entry:
+// application/json when present, otherwise the first, in sorted order, with a +json
+// suffix such as application/vnd.api+json or application/problem+json
+func JSONMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if media := content.Get("application/json"); media != nil {
+		return "application/json", media
+	}
+	for _, mediaType := range sortedMediaTypes(content) {
+		parsed, _, err := mime.ParseMediaType(mediaType)
+		if err == nil && strings.HasSuffix(parsed, "+json") {
+			return mediaType, content[mediaType]
+		}
+	}
+	return "", nil
+}
+
+// ResponseMediaType returns the JSON media type of the response declared for statusCode,
+// or of the "default" response, as GenerateResponse picks it; empty when there is none
+func ResponseMediaType(operation *openapi3.Operation, statusCode string) string {
+	if operation == nil || operation.Responses == nil {
+		return ""
+	}
+	responseRef := findResponse(operation.Responses, statusCode)
+	if responseRef == nil || responseRef.Value == nil {
+		return ""
+	}
+	mediaType, _ := JSONMediaType(responseRef.Value.Content)
+	return mediaType
+}
@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestJSONMediaType(t *testing.T) {
+	media := func() *openapi3.MediaType {
+		return openapi3.NewMediaType().WithSchema(openapi3.NewObjectSchema())
+	}
+
+	tests := []struct {
+		name     string
+		content  openapi3.Content
+		expected string
+	}{
+		{name: "plain JSON wins", content: openapi3.Content{"application/json": media(), "application/vnd.api+json": media()}, expected: "application/json"},
+		{name: "vendor JSON", content: openapi3.Content{"application/vnd.api+json": media(), "text/plain": media()}, expected: "application/vnd.api+json"},
+		{name: "parameters kept", content: openapi3.Content{"application/problem+json; charset=utf-8": media()}, expected: "application/problem+json; charset=utf-8"},
+		{name: "no JSON", content: openapi3.Content{"text/csv": media()}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mediaType, entry := JSONMediaType(tt.content)
+			if mediaType != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, mediaType)
+			}
+			if (entry != nil) != (tt.expected != "") {
+				t.Errorf("Expected an entry only for a JSON media type, got %v", entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxNotAttempts bounds how many values are generated looking for one that a schema's
+// not subschema rejects
+const maxNotAttempts = 20
+
+// generateNot generates values for a schema with a not keyword until one doesn't match
+// the not subschema, so the result validates against the whole schema
+func (g *Generator) generateNot(schema *openapi3.Schema) (interface{}, error) {
+	allowed := *schema
+	allowed.Not = nil
+
+	for attempt := 0; attempt < maxNotAttempts; attempt++ {
+		value, err := g.GenerateFromSchema(&allowed)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesSchema(schema.Not.Value, value) {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("no value avoiding the schema's not constraint after %d attempts", maxNotAttempts)
+}
+
+// matchesSchema reports whether a generated value validates against schema, comparing
+// it as decoded JSON so int64s and float64s are treated alike
+func matchesSchema(schema *openapi3.Schema, value interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return false
+	}
+	return schema.VisitJSON(decoded) == nil
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGenerateAvoidsNot(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+	}{
+		{
+			name: "enum value excluded",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"string"},
+				Enum: []interface{}{"draft", "published"},
+				Not:  openapi3.NewSchemaRef("", &openapi3.Schema{Enum: []interface{}{"draft"}}),
+			},
+		},
+		{
+			name: "number range excluded",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"integer"},
+				Min:  openapi3.Float64Ptr(0),
+				Max:  openapi3.Float64Ptr(10),
+				Not:  openapi3.NewSchemaRef("", &openapi3.Schema{Max: openapi3.Float64Ptr(4)}),
+			},
+		},
+		{
+			name: "object shape excluded",
+			schema: &openapi3.Schema{
+				Type:       &openapi3.Types{"object"},
+				Required:   []string{"kind"},
+				Properties: openapi3.Schemas{"kind": openapi3.NewStringSchema().WithEnum("a", "b").NewRef()},
+				Not:        openapi3.NewSchemaRef("", &openapi3.Schema{Properties: openapi3.Schemas{"kind": openapi3.NewStringSchema().WithEnum("a").NewRef()}}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				value, err := NewGenerator(seed).GenerateFromSchema(tt.schema)
+				if err != nil {
+					t.Fatalf("Seed %d: unexpected error: %v", seed, err)
+				}
+				if matchesSchema(tt.schema.Not.Value, value) {
+					t.Fatalf("Seed %d: generated %v, which matches the not subschema", seed, value)
+				}
+				if !matchesSchema(tt.schema, value) {
+					t.Fatalf("Seed %d: generated %v, which doesn't validate", seed, value)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateNotUnsatisfiable(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"boolean"},
+		Not:  openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"boolean"}}),
+	}
+
+	if value, err := NewGenerator(1).GenerateFromSchema(schema); err == nil {
+		t.Errorf("Expected an error for a schema nothing satisfies, got %v", value)
+	}
+}
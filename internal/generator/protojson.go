@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// protoTypeExtension names the protobuf well-known type a schema stands for, e.g.
+// x-protobuf-type: google.protobuf.Duration, for specs whose format alone is ambiguous
+const protoTypeExtension = "x-protobuf-type"
+
+// enumNamesExtension lists the names of an integer enum's values, in the same order
+const enumNamesExtension = "x-enum-varnames"
+
+// protoAnyTypeURL is the type packed into generated google.protobuf.Any values
+const protoAnyTypeURL = "type.googleapis.com/google.protobuf.Empty"
+
+// protoWellKnownType returns the protobuf well-known type a schema maps to, from the
+// x-protobuf-type extension or, failing that, from the hints gRPC-gateway's OpenAPI
+// output carries, or "" for an ordinary schema
+func protoWellKnownType(schema *openapi3.Schema) string {
+	if name, ok := schema.Extensions[protoTypeExtension].(string); ok && name != "" {
+		return strings.TrimPrefix(name, ".")
+	}
+
+	switch schema.Format {
+	case "date-time":
+		return "google.protobuf.Timestamp"
+	case "duration":
+		return "google.protobuf.Duration"
+	case "field-mask":
+		return "google.protobuf.FieldMask"
+	case "byte":
+		return "google.protobuf.BytesValue"
+	case "int64", "sint64", "sfixed64":
+		// protoc-gen-openapiv2 declares 64-bit fields as strings, others as integers
+		if schema.Type.Is("integer") || schema.Type.Is("string") {
+			return "google.protobuf.Int64Value"
+		}
+	case "uint64", "fixed64":
+		if schema.Type.Is("integer") || schema.Type.Is("string") {
+			return "google.protobuf.UInt64Value"
+		}
+	}
+
+	if _, ok := schema.Properties["@type"]; ok {
+		return "google.protobuf.Any"
+	}
+	return ""
+}
+
+// generateProtoJSON generates a value following the protobuf JSON mapping when the
+// schema is a well-known type or a named integer enum; ok is false for any other schema
+func (g *Generator) generateProtoJSON(schema *openapi3.Schema) (value interface{}, ok bool) {
+	if names := enumNames(schema); len(names) > 0 {
+		// Enums are written as their value names, not their numbers
+		return names[g.rng.Intn(len(names))], true
+	}
+
+	switch protoWellKnownType(schema) {
+	case "google.protobuf.Timestamp":
+		// RFC 3339 in UTC with a "Z" suffix and millisecond precision
+		offset := time.Duration(g.rng.Int63n(int64(365 * 24 * time.Hour)))
+		return g.opts.now().UTC().Add(-offset).Truncate(time.Millisecond).Format("2006-01-02T15:04:05.000Z"), true
+	case "google.protobuf.Duration":
+		return protoDuration(g.rng.Int63n(int64(time.Hour/time.Millisecond)) * int64(time.Millisecond)), true
+	case "google.protobuf.FieldMask":
+		paths := make([]string, 1+g.rng.Intn(3))
+		for i := range paths {
+			paths[i] = g.fieldMaskPath()
+		}
+		return strings.Join(paths, ","), true
+	case "google.protobuf.Int64Value":
+		// 64-bit integers are strings so JavaScript clients don't lose precision
+		return strconv.FormatInt(g.generateInteger(schema), 10), true
+	case "google.protobuf.UInt64Value":
+		n := g.generateInteger(schema)
+		if n < 0 {
+			n = -n
+		}
+		return strconv.FormatInt(n, 10), true
+	case "google.protobuf.BytesValue":
+		data := make([]byte, 8+g.rng.Intn(24))
+		g.rng.Read(data)
+		return base64.StdEncoding.EncodeToString(data), true
+	case "google.protobuf.Empty":
+		return map[string]interface{}{}, true
+	case "google.protobuf.Any":
+		return map[string]interface{}{"@type": protoAnyTypeURL, "value": map[string]interface{}{}}, true
+	}
+	return nil, false
+}
+
+// enumNames returns the names of an integer enum's values from x-enum-varnames, or nil
+// when the schema isn't one or the names don't line up with the values
+func enumNames(schema *openapi3.Schema) []string {
+	if len(schema.Enum) == 0 || !schema.Type.Is("integer") {
+		return nil
+	}
+	raw, ok := schema.Extensions[enumNamesExtension].([]interface{})
+	if !ok || len(raw) != len(schema.Enum) {
+		return nil
+	}
+	names := make([]string, len(raw))
+	for i, name := range raw {
+		if names[i], ok = name.(string); !ok {
+			return nil
+		}
+	}
+	return names
+}
+
+// protoDuration formats nanoseconds as a protobuf JSON duration: seconds with a
+// fractional part of 0, 3, 6, or 9 digits and an "s" suffix, e.g. "1.500s"
+func protoDuration(nanos int64) string {
+	seconds, frac := nanos/int64(time.Second), nanos%int64(time.Second)
+	switch {
+	case frac == 0:
+		return fmt.Sprintf("%ds", seconds)
+	case frac%int64(time.Millisecond) == 0:
+		return fmt.Sprintf("%d.%03ds", seconds, frac/int64(time.Millisecond))
+	case frac%int64(time.Microsecond) == 0:
+		return fmt.Sprintf("%d.%06ds", seconds, frac/int64(time.Microsecond))
+	default:
+		return fmt.Sprintf("%d.%09ds", seconds, frac)
+	}
+}
+
+// fieldMaskPath generates one lowerCamelCase field mask path from locale words
+func (g *Generator) fieldMaskPath() string {
+	words := g.locale.Words
+	first := strings.ToLower(words[g.rng.Intn(len(words))])
+	second := []rune(strings.ToLower(words[g.rng.Intn(len(words))]))
+	return first + strings.ToUpper(string(second[:1])) + string(second[1:])
+}
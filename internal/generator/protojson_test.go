@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestProtoJSONMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *openapi3.Schema
+		pattern string
+		check   func(t *testing.T, value interface{})
+	}{
+		{
+			name:    "timestamp from date-time",
+			schema:  &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "date-time"},
+			pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z$`,
+			check: func(t *testing.T, value interface{}) {
+				if _, err := time.Parse(time.RFC3339, value.(string)); err != nil {
+					t.Errorf("Expected an RFC 3339 timestamp, got %q: %v", value, err)
+				}
+			},
+		},
+		{
+			name: "timestamp from x-protobuf-type",
+			schema: &openapi3.Schema{
+				Type:       &openapi3.Types{"string"},
+				Extensions: map[string]interface{}{"x-protobuf-type": ".google.protobuf.Timestamp"},
+			},
+			pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z$`,
+		},
+		{
+			name: "enum by name",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"integer"},
+				Enum: []interface{}{0, 1, 2},
+				Extensions: map[string]interface{}{
+					"x-enum-varnames": []interface{}{"STATUS_UNSPECIFIED", "STATUS_ACTIVE", "STATUS_DELETED"},
+				},
+			},
+			pattern: `^STATUS_(UNSPECIFIED|ACTIVE|DELETED)$`,
+		},
+		{
+			name:    "string enum unchanged",
+			schema:  &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: []interface{}{"RED", "GREEN"}},
+			pattern: `^(RED|GREEN)$`,
+		},
+		{
+			name: "duration",
+			schema: &openapi3.Schema{
+				Type:       &openapi3.Types{"string"},
+				Extensions: map[string]interface{}{"x-protobuf-type": "google.protobuf.Duration"},
+			},
+			pattern: `^\d+(\.\d{3})?s$`,
+		},
+		{
+			name:    "int64 quoted",
+			schema:  &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64", Min: float64Ptr(5), Max: float64Ptr(9)},
+			pattern: `^[5-9]$`,
+		},
+		{
+			name:    "int64 declared as a string",
+			schema:  &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "int64"},
+			pattern: `^-?\d+$`,
+		},
+		{
+			name:    "bytes as base64",
+			schema:  &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "byte"},
+			pattern: `^[A-Za-z0-9+/]+=*$`,
+		},
+		{
+			name:    "field mask",
+			schema:  &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "field-mask"},
+			pattern: `^[a-z]+[A-Z][a-z]*(,[a-z]+[A-Z][a-z]*)*$`,
+		},
+		{
+			name: "any carries a type URL",
+			schema: &openapi3.Schema{
+				Type:       &openapi3.Types{"object"},
+				Properties: openapi3.Schemas{"@type": openapi3.NewStringSchema().NewRef()},
+			},
+			check: func(t *testing.T, value interface{}) {
+				obj := value.(map[string]interface{})
+				if obj["@type"] != "type.googleapis.com/google.protobuf.Empty" {
+					t.Errorf("Expected an @type URL, got %v", obj)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGeneratorWithOptions(7, GenerateOptions{ProtoJSON: true})
+			value, err := gen.GenerateFromSchema(tt.schema)
+			if err != nil {
+				t.Fatalf("Failed to generate: %v", err)
+			}
+			if tt.pattern != "" {
+				str, ok := value.(string)
+				if !ok || !regexp.MustCompile(tt.pattern).MatchString(str) {
+					t.Errorf("Expected a string matching %s, got %#v", tt.pattern, value)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, value)
+			}
+		})
+	}
+}
+
+func TestProtoJSONDisabled(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"integer"},
+		Enum: []interface{}{1, 2},
+		Extensions: map[string]interface{}{
+			"x-enum-varnames": []interface{}{"ONE", "TWO"},
+		},
+	}
+	value, err := NewGenerator(7).GenerateFromSchema(schema)
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if _, ok := value.(int64); !ok {
+		t.Errorf("Expected a number without ProtoJSON, got %#v", value)
+	}
+}
+
+func TestProtoDuration(t *testing.T) {
+	tests := []struct {
+		nanos    int64
+		expected string
+	}{
+		{nanos: 3 * int64(time.Second), expected: "3s"},
+		{nanos: 1500 * int64(time.Millisecond), expected: "1.500s"},
+		{nanos: 1000340 * int64(time.Microsecond), expected: "1.000340s"},
+		{nanos: 1000000012, expected: "1.000000012s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := protoDuration(tt.nanos); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
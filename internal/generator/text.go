@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// FindTextContent returns the media type and schema of a plain-text response declared
+// for statusCode: a text/* media type whose schema, if any, is a scalar. JSON responses
+// take precedence.
+func FindTextContent(operation *openapi3.Operation, statusCode string) (string, *openapi3.Schema, bool) {
+	if operation == nil || operation.Responses == nil {
+		return "", nil, false
+	}
+	responseRef := findResponse(operation.Responses, statusCode)
+	if responseRef == nil || responseRef.Value == nil || responseRef.Value.Content == nil {
+		return "", nil, false
+	}
+	content := responseRef.Value.Content
+	if mediaType, _ := JSONMediaType(content); mediaType != "" {
+		return "", nil, false
+	}
+
+	for _, mediaType := range sortedMediaTypes(content) {
+		if !strings.HasPrefix(strings.ToLower(mediaType), "text/") {
+			continue
+		}
+		var schema *openapi3.Schema
+		if media := content[mediaType]; media != nil && media.Schema != nil {
+			schema = media.Schema.Value
+		}
+		if schema == nil || isScalar(schema) {
+			return mediaType, schema, true
+		}
+	}
+	return "", nil, false
+}
+
+// TextExample returns the example declared on the plain-text media type FindTextContent
+// picks for statusCode, written as text
+func TextExample(operation *openapi3.Operation, statusCode string) (string, bool) {
+	mediaType, _, ok := FindTextContent(operation, statusCode)
+	if !ok {
+		return "", false
+	}
+	media := findResponse(operation.Responses, statusCode).Value.Content[mediaType]
+	if media == nil || media.Example == nil {
+		return "", false
+	}
+	return formatText(media.Example), true
+}
+
+// GenerateText produces a plain-text body: the scalar the schema describes, written
+// as is, or a random string when there is no schema
+func (g *Generator) GenerateText(schema *openapi3.Schema) (string, error) {
+	if schema == nil {
+		schema = openapi3.NewStringSchema()
+	}
+	value, err := g.WithContext(ContextResponse).GenerateFromSchema(schema)
+	if err != nil {
+		return "", err
+	}
+	return formatText(value), nil
+}
+
+// formatText writes a scalar as plain text
+func formatText(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		// Avoid exponent notation for large numbers
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// isScalar reports whether a schema describes a string, number, integer, or boolean
+func isScalar(schema *openapi3.Schema) bool {
+	if schema.Type == nil {
+		return false
+	}
+	for _, t := range []string{"string", "number", "integer", "boolean"} {
+		if schema.Type.Is(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFindTextContent(t *testing.T) {
+	response := func(content openapi3.Content) *openapi3.Operation {
+		responses := openapi3.NewResponses()
+		responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK").WithContent(content)})
+		return &openapi3.Operation{Responses: responses}
+	}
+
+	tests := []struct {
+		name      string
+		content   openapi3.Content
+		mediaType string
+		found     bool
+	}{
+		{name: "string schema", content: openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/plain"}), mediaType: "text/plain", found: true},
+		{name: "no schema", content: openapi3.Content{"text/csv": openapi3.NewMediaType()}, mediaType: "text/csv", found: true},
+		{name: "object schema", content: openapi3.NewContentWithSchema(openapi3.NewObjectSchema(), []string{"text/plain"})},
+		{name: "JSON wins", content: openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/plain", "application/json"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mediaType, _, found := FindTextContent(response(tt.content), "200")
+			if found != tt.found || mediaType != tt.mediaType {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.mediaType, tt.found, mediaType, found)
+			}
+		})
+	}
+}
+
+func TestGenerateText(t *testing.T) {
+	price := openapi3.NewFloat64Schema().WithMin(12000000).WithMax(12000000)
+	if text, err := NewGenerator(1).GenerateText(price); err != nil || text != "12000000" {
+		t.Errorf("Expected 12000000 without exponent, got %q (%v)", text, err)
+	}
+	if text, err := NewGenerator(1).GenerateText(nil); err != nil || text == "" {
+		t.Errorf("Expected a random string without a schema, got %q (%v)", text, err)
+	}
+}
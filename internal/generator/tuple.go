@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// tuplePositions returns the number of tuple positions an array schema declares, or 0.
+// The parser rewrites OpenAPI 3.1 prefixItems into items.anyOf, with this many leading
+// branches being the tuple's positions and an optional last branch the schema of any
+// further items.
+func tuplePositions(schema *openapi3.Schema) int {
+	positions, _ := extensionInt(schema, parser.PrefixItemsExtension)
+	if positions <= 0 || positions > len(schema.Items.Value.AnyOf) {
+		return 0
+	}
+	return positions
+}
+
+// generateTuple generates each tuple position from its own schema, then as many further
+// items from the additional items schema as minItems asks for, or a random few up to
+// maxItems. Without an additional items schema the tuple is generated as declared.
+func (g *Generator) generateTuple(schema *openapi3.Schema, positions int) ([]interface{}, error) {
+	branches := schema.Items.Value.AnyOf
+
+	var additional *openapi3.Schema
+	if len(branches) > positions && branches[positions] != nil {
+		additional = branches[positions].Value
+	}
+
+	length := positions
+	if additional != nil {
+		length = max(length, int(schema.MinItems))
+		if schema.MaxItems == nil || int(*schema.MaxItems) > length {
+			maxLength := length + 2
+			if schema.MaxItems != nil {
+				maxLength = min(maxLength, int(*schema.MaxItems))
+			}
+			length += g.rng.Intn(maxLength - length + 1)
+		}
+	}
+	if schema.MaxItems != nil {
+		length = min(length, int(*schema.MaxItems))
+	}
+
+	result := make([]interface{}, length)
+	for i := range result {
+		itemSchema := additional
+		if i < positions {
+			if branches[i] == nil || branches[i].Value == nil {
+				return nil, fmt.Errorf("tuple position %d has no schema", i)
+			}
+			itemSchema = branches[i].Value
+		}
+		item, err := g.GenerateFromSchema(itemSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate tuple item %d: %w", i, err)
+		}
+		result[i] = item
+	}
+
+	return result, nil
+}
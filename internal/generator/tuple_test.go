@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// tupleSchema builds an array whose items.anyOf holds the positions followed by the
+// additional items schema, as the parser rewrites prefixItems
+func tupleSchema(positions []*openapi3.Schema, additional *openapi3.Schema) *openapi3.Schema {
+	branches := make(openapi3.SchemaRefs, 0, len(positions)+1)
+	for _, position := range positions {
+		branches = append(branches, position.NewRef())
+	}
+	if additional != nil {
+		branches = append(branches, additional.NewRef())
+	}
+	schema := openapi3.NewArraySchema().WithItems(&openapi3.Schema{AnyOf: branches})
+	schema.Extensions = map[string]interface{}{parser.PrefixItemsExtension: float64(len(positions))}
+	return schema
+}
+
+func TestGenerateTuple(t *testing.T) {
+	pair := tupleSchema([]*openapi3.Schema{openapi3.NewStringSchema(), openapi3.NewIntegerSchema()}, nil)
+
+	for seed := int64(1); seed <= 10; seed++ {
+		value, err := NewGenerator(seed).GenerateFromSchema(pair)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		tuple := value.([]interface{})
+		if len(tuple) != 2 {
+			t.Fatalf("Expected exactly 2 items, got %v", tuple)
+		}
+		if _, ok := tuple[0].(string); !ok {
+			t.Errorf("Expected a string first, got %T", tuple[0])
+		}
+		if _, ok := tuple[1].(int64); !ok {
+			t.Errorf("Expected an integer second, got %T", tuple[1])
+		}
+	}
+}
+
+func TestGenerateTupleAdditionalItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		minItems uint64
+		maxItems *uint64
+		minLen   int
+		maxLen   int
+	}{
+		{name: "unbounded", minLen: 1, maxLen: 3},
+		{name: "minItems", minItems: 4, minLen: 4, maxLen: 6},
+		{name: "maxItems", maxItems: openapi3.Uint64Ptr(2), minLen: 1, maxLen: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := tupleSchema([]*openapi3.Schema{openapi3.NewStringSchema()}, openapi3.NewBoolSchema())
+			schema.MinItems = tt.minItems
+			schema.MaxItems = tt.maxItems
+
+			for seed := int64(1); seed <= 20; seed++ {
+				value, err := NewGenerator(seed).GenerateFromSchema(schema)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				tuple := value.([]interface{})
+				if len(tuple) < tt.minLen || len(tuple) > tt.maxLen {
+					t.Fatalf("Expected %d to %d items, got %v", tt.minLen, tt.maxLen, tuple)
+				}
+				if _, ok := tuple[0].(string); !ok {
+					t.Errorf("Expected the first position to be a string, got %T", tuple[0])
+				}
+				for _, item := range tuple[1:] {
+					if _, ok := item.(bool); !ok {
+						t.Errorf("Expected additional items to be booleans, got %T", item)
+					}
+				}
+			}
+		})
+	}
+}
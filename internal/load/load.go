@@ -0,0 +1,172 @@
+package load
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config describes a constant-rate load run against a single endpoint
+type Config struct {
+	BaseURL  string
+	Path     string
+	Method   string
+	RPS      int
+	Duration time.Duration
+
+	// Body returns the request body for each request; nil sends no body
+	Body func() ([]byte, error)
+
+	Client *http.Client
+}
+
+// maxRPS is the highest rate Run accepts: one request per nanosecond, the ticker's
+// finest interval
+const maxRPS = int(time.Second)
+
+// Result summarizes a completed load run
+type Result struct {
+	Requests  int
+	Errors    int
+	Latencies []time.Duration // sorted ascending
+	Elapsed   time.Duration
+}
+
+// ErrorRate returns the fraction of requests that failed
+func (r *Result) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Percentile returns the latency at percentile p (0-100) using nearest-rank: the
+// smallest latency with at least p percent of the samples at or below it
+func (r *Result) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(r.Latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(r.Latencies) {
+		rank = len(r.Latencies)
+	}
+	return r.Latencies[rank-1]
+}
+
+// Run fires requests at a constant rate until the duration elapses or ctx is cancelled.
+// Requests that fail to connect or return a 4xx/5xx status count as errors; requests
+// cut off by cancelling ctx aren't counted at all.
+func Run(ctx context.Context, cfg Config) (*Result, error) {
+	if cfg.RPS <= 0 {
+		return nil, fmt.Errorf("rps must be positive")
+	}
+	if cfg.RPS > maxRPS {
+		return nil, fmt.Errorf("rps must be at most %d", maxRPS)
+	}
+	if cfg.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	url := strings.TrimSuffix(cfg.BaseURL, "/") + cfg.Path
+	method := strings.ToUpper(cfg.Method)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result Result
+	)
+
+	record := func(latency time.Duration, failed bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Requests++
+		result.Latencies = append(result.Latencies, latency)
+		if failed {
+			result.Errors++
+		}
+	}
+	// recordUnsent counts a request that failed before it was sent; it has no latency
+	recordUnsent := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Requests++
+		result.Errors++
+	}
+
+	fire := func() {
+		defer wg.Done()
+
+		var body io.Reader
+		if cfg.Body != nil {
+			data, err := cfg.Body()
+			if err != nil {
+				recordUnsent()
+				return
+			}
+			body = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			recordUnsent()
+			return
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Stopped by the caller, not a failure of the target
+				return
+			}
+			record(time.Since(start), true)
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		record(time.Since(start), resp.StatusCode >= 400)
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.RPS))
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+	start := time.Now()
+loop:
+	for {
+		wg.Add(1)
+		go fire()
+
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline.C:
+			break loop
+		case <-ticker.C:
+		}
+	}
+	wg.Wait()
+
+	result.Elapsed = time.Since(start)
+	sort.Slice(result.Latencies, func(i, j int) bool { return result.Latencies[i] < result.Latencies[j] })
+
+	return &result, nil
+}
@@ -0,0 +1,139 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var bodies atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			bodies.Add(1)
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedError float64
+	}{
+		{name: "healthy endpoint", path: "/items", expectedError: 0},
+		{name: "failing endpoint", path: "/fail", expectedError: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(context.Background(), Config{
+				BaseURL:  backend.URL,
+				Path:     tt.path,
+				Method:   "POST",
+				RPS:      50,
+				Duration: 200 * time.Millisecond,
+				Body:     func() ([]byte, error) { return []byte(`{"name":"alpha"}`), nil },
+			})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if result.Requests < 5 {
+				t.Errorf("Expected at least 5 requests at 50 rps for 200ms, got %d", result.Requests)
+			}
+			if result.ErrorRate() != tt.expectedError {
+				t.Errorf("Expected error rate %v, got %v", tt.expectedError, result.ErrorRate())
+			}
+			if result.Percentile(50) > result.Percentile(99) {
+				t.Errorf("Expected p50 <= p99, got %v > %v", result.Percentile(50), result.Percentile(99))
+			}
+		})
+	}
+
+	if bodies.Load() == 0 {
+		t.Error("Expected request bodies to be sent")
+	}
+}
+
+func TestRunRejectsInvalidConfig(t *testing.T) {
+	if _, err := Run(context.Background(), Config{BaseURL: "http://localhost", RPS: 0, Duration: time.Second}); err == nil {
+		t.Error("Expected error for zero rps")
+	}
+	if _, err := Run(context.Background(), Config{BaseURL: "http://localhost", RPS: 2e9, Duration: time.Second}); err == nil {
+		t.Error("Expected error for an rps beyond one request per nanosecond")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	result := &Result{}
+	for i := 1; i <= 10; i++ {
+		result.Latencies = append(result.Latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{p: 0, expected: 1 * time.Millisecond},
+		{p: 10, expected: 1 * time.Millisecond},
+		{p: 11, expected: 2 * time.Millisecond},
+		{p: 50, expected: 5 * time.Millisecond},
+		{p: 95, expected: 10 * time.Millisecond},
+		{p: 99, expected: 10 * time.Millisecond},
+		{p: 100, expected: 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := result.Percentile(tt.p); got != tt.expected {
+			t.Errorf("p%v: expected %v, got %v", tt.p, tt.expected, got)
+		}
+	}
+}
+
+func TestRunIgnoresCancelledRequests(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold every request until the client gives up
+		<-r.Context().Done()
+	}))
+	defer backend.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := Run(ctx, Config{BaseURL: backend.URL, Path: "/slow", Method: "GET", RPS: 50, Duration: time.Minute})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Errors != 0 {
+		t.Errorf("Expected cancelled requests not to count as errors, got %d", result.Errors)
+	}
+}
+
+func TestRunUnsentRequestsHaveNoLatency(t *testing.T) {
+	result, err := Run(context.Background(), Config{
+		BaseURL:  "http://localhost",
+		Path:     "/items",
+		Method:   "POST",
+		RPS:      50,
+		Duration: 100 * time.Millisecond,
+		Body:     func() ([]byte, error) { return nil, errors.New("boom") },
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Requests == 0 || result.ErrorRate() != 1 {
+		t.Errorf("Expected every request to fail, got %d errors of %d", result.Errors, result.Requests)
+	}
+	if len(result.Latencies) != 0 {
+		t.Errorf("Expected no latency samples for unsent requests, got %v", result.Latencies)
+	}
+}
@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+// writeBinary answers with a generated file download instead of a JSON body
+func (s *Server) writeBinary(rnd *requestRandom, w http.ResponseWriter, r *http.Request, endpoint parser.Endpoint, status int, mediaType string, data []byte) {
+	if !s.sleep(r, s.delayFor(r, s.latencyFor(endpoint), len(data))) {
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", downloadName(endpoint.Path, mediaType)))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("X-Mocktail-Server", "true")
+	s.setCookies(rnd, w.Header(), endpoint)
+	w.WriteHeader(status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(data); err != nil {
+		s.logger.Errorf("Error writing response: %v", err)
+	}
+}
+
+// downloadName derives a file name from the last static path segment, e.g.
+// /reports/{id}/pdf served as application/pdf becomes "pdf.pdf"
+func downloadName(path, mediaType string) string {
+	name := "download"
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !isWildcard(segments[i]) {
+			name = segments[i]
+			break
+		}
+	}
+
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return name + extensions[0]
+	}
+	return name + ".bin"
+}
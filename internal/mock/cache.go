@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// setCacheHeaders adds an ETag derived from the encoded body and a Cache-Control that
+// makes clients revalidate, turning the response into a 304 when the request's
+// If-None-Match already holds that ETag
+func setCacheHeaders(r *http.Request, response *MockResponse, body []byte) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return
+	}
+	if response.Status < 200 || response.Status >= 300 {
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	response.Headers.Set("ETag", etag)
+	response.Headers.Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		response.Status = http.StatusNotModified
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly
+// as RFC 9110 requires for GET and HEAD
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
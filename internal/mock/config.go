@@ -0,0 +1,124 @@
+package mock
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the optional mock configuration file, letting users tune behavior per endpoint
+type Config struct {
+	Endpoints []EndpointConfig `yaml:"endpoints"`
+}
+
+// EndpointConfig holds overrides for the endpoints matching Method and Path.
+// Path is a schema path template or a glob such as /reports/*; an empty Method matches any method.
+type EndpointConfig struct {
+	Method  string         `yaml:"method"`
+	Path    string         `yaml:"path"`
+	Latency time.Duration  `yaml:"latency"` // e.g. 2s; overrides the global latency
+	Cookies []CookieConfig `yaml:"cookies"`
+
+	// Template is a Go text/template producing the JSON response body; see templateData
+	Template string `yaml:"template"`
+
+	// Rules script responses by request body; the first matching rule applies
+	Rules []RuleConfig `yaml:"rules"`
+}
+
+// RuleConfig answers requests whose JSON body matches every condition with a fixed
+// status and, optionally, a fixed body
+type RuleConfig struct {
+	Match  map[string]interface{} `yaml:"match"`  // field path, e.g. user.name or $.items[0].id -> expected value
+	Status int                    `yaml:"status"` // 0 keeps the endpoint's status
+	Body   interface{}            `yaml:"body"`   // nil generates from the status's response
+}
+
+// CookieConfig describes a Set-Cookie header to add to an endpoint's responses
+type CookieConfig struct {
+	Name     string `yaml:"name"`
+	Value    string `yaml:"value"`  // fixed value; generated when empty
+	Format   string `yaml:"format"` // string format for generated values (default: uuid)
+	Path     string `yaml:"path"`   // default: /
+	Domain   string `yaml:"domain"`
+	MaxAge   int    `yaml:"maxAge"`
+	HttpOnly bool   `yaml:"httpOnly"`
+	Secure   bool   `yaml:"secure"`
+	SameSite string `yaml:"sameSite"` // lax, strict, or none
+}
+
+// LoadConfig reads a YAML (or JSON) mock configuration file
+func LoadConfig(filepath string) (*Config, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	for i, endpoint := range config.Endpoints {
+		if endpoint.Path == "" {
+			return nil, fmt.Errorf("endpoint config #%d is missing a path", i+1)
+		}
+		if _, err := path.Match(endpoint.Path, "/"); err != nil {
+			return nil, fmt.Errorf("endpoint config #%d has an invalid path pattern %q: %w", i+1, endpoint.Path, err)
+		}
+		if endpoint.Template != "" {
+			if _, err := parseTemplate(endpoint.Template); err != nil {
+				return nil, fmt.Errorf("endpoint config for %s has an invalid template: %w", endpoint.Path, err)
+			}
+		}
+		for j, rule := range endpoint.Rules {
+			if len(rule.Match) == 0 {
+				return nil, fmt.Errorf("endpoint config for %s has rule #%d without match conditions", endpoint.Path, j+1)
+			}
+			if rule.Status == 0 && rule.Body == nil {
+				return nil, fmt.Errorf("endpoint config for %s has rule #%d without a status or body", endpoint.Path, j+1)
+			}
+			if rule.Status != 0 && (rule.Status < 100 || rule.Status > 599) {
+				return nil, fmt.Errorf("endpoint config for %s has rule #%d with invalid status %d", endpoint.Path, j+1, rule.Status)
+			}
+		}
+		for _, cookie := range endpoint.Cookies {
+			if cookie.Name == "" {
+				return nil, fmt.Errorf("endpoint config for %s has a cookie without a name", endpoint.Path)
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// matches reports whether the config entry applies to the given method and schema path
+func (e EndpointConfig) matches(method, schemaPath string) bool {
+	if e.Method != "" && !strings.EqualFold(e.Method, method) {
+		return false
+	}
+	if e.Path == schemaPath {
+		return true
+	}
+	matched, _ := path.Match(e.Path, schemaPath)
+	return matched
+}
+
+// endpointConfigs returns the config entries applying to an endpoint, in file order
+func (c *Config) endpointConfigs(method, schemaPath string) []EndpointConfig {
+	if c == nil {
+		return nil
+	}
+
+	var matched []EndpointConfig
+	for _, endpoint := range c.Endpoints {
+		if endpoint.matches(method, schemaPath) {
+			matched = append(matched, endpoint)
+		}
+	}
+	return matched
+}
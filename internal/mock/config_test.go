@@ -0,0 +1,138 @@
+package mock
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "mocktail.yaml")
+	content := `endpoints:
+  - method: POST
+    path: /login
+    cookies:
+      - name: session
+        httpOnly: true
+        maxAge: 3600
+  - path: /reports/*
+    latency: 2s
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if len(config.Endpoints) != 2 {
+		t.Fatalf("Expected 2 endpoint configs, got %d", len(config.Endpoints))
+	}
+	if config.Endpoints[1].Latency != 2*time.Second {
+		t.Errorf("Expected latency 2s, got %v", config.Endpoints[1].Latency)
+	}
+	if cookie := config.Endpoints[0].Cookies[0]; cookie.Name != "session" || !cookie.HttpOnly || cookie.MaxAge != 3600 {
+		t.Errorf("Unexpected cookie config: %+v", cookie)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		count  int
+	}{
+		{method: "POST", path: "/login", count: 1},
+		{method: "GET", path: "/login", count: 0},
+		{method: "GET", path: "/reports/{id}", count: 1},
+		{method: "GET", path: "/reports/{id}/pages", count: 0},
+	}
+	for _, tt := range tests {
+		if got := len(config.endpointConfigs(tt.method, tt.path)); got != tt.count {
+			t.Errorf("Expected %d configs for %s %s, got %d", tt.count, tt.method, tt.path, got)
+		}
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "mocktail.yaml")
+	if err := os.WriteFile(configFile, []byte("endpoints:\n  - method: GET\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if _, err := LoadConfig(configFile); err == nil {
+		t.Error("Expected error for endpoint config without a path")
+	}
+}
+
+func TestLoadConfigRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid rule",
+			content: `endpoints:
+  - method: POST
+    path: /login
+    rules:
+      - match: {username: bad}
+        status: 401
+`,
+		},
+		{
+			name: "no conditions",
+			content: `endpoints:
+  - path: /login
+    rules:
+      - status: 401
+`,
+			wantErr: "without match conditions",
+		},
+		{
+			name: "no outcome",
+			content: `endpoints:
+  - path: /login
+    rules:
+      - match: {username: bad}
+`,
+			wantErr: "without a status or body",
+		},
+		{
+			name: "invalid status",
+			content: `endpoints:
+  - path: /login
+    rules:
+      - match: {username: bad}
+        status: 42
+`,
+			wantErr: "invalid status 42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "mocktail.yaml")
+			if err := os.WriteFile(configFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			config, err := LoadConfig(configFile)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig failed: %v", err)
+				}
+				if rule := config.Endpoints[0].Rules[0]; rule.Status != 401 || rule.Match["username"] != "bad" {
+					t.Errorf("Unexpected rule: %+v", rule)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
+)
+
+// dryRunPreviewLength caps the response preview printed per route
+const dryRunPreviewLength = 60
+
+// DryRun generates one response per endpoint without starting the listener and prints
+// a line per route, returning an error when any endpoint's response can't be generated
+func (s *Server) DryRun(w io.Writer) error {
+	total, failed := 0, 0
+	for _, m := range s.mounts {
+		if _, ok := m.Schema.Raw.(*parser.GraphQLSchema); ok {
+			total++
+			fmt.Fprintf(w, "✓ %-7s %s → GraphQL, resolved per query\n", "POST", m.Prefix+parser.GraphQLEndpointPath)
+			continue
+		}
+
+		paths := make([]string, 0, len(m.Schema.Paths))
+		for path := range m.Schema.Paths {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			endpoints := append([]parser.Endpoint(nil), m.Schema.Paths[path]...)
+			sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Method < endpoints[j].Method })
+
+			for _, endpoint := range endpoints {
+				total++
+				route := m.Prefix + path
+				operation := findOperation(m.Schema, endpoint)
+				statusKey, status := s.chooseStatus(endpoint, operation)
+				rnd := s.randomForKey(endpoint.Method + " " + route)
+
+				if mediaType, _, ok := generator.FindBinaryContent(operation, statusKey); ok && endpoint.Example == nil {
+					fmt.Fprintf(w, "✓ %-7s %s → %d %s download\n", endpoint.Method, route, status, mediaType)
+					continue
+				}
+				if mediaType, textSchema, ok := generator.FindTextContent(operation, statusKey); ok && endpoint.Example == nil {
+					text, err := rnd.gen.GenerateText(textSchema)
+					if err != nil {
+						failed++
+						fmt.Fprintf(w, "❌ %-7s %s → %v\n", endpoint.Method, route, err)
+						continue
+					}
+					if runes := []rune(text); len(runes) > dryRunPreviewLength {
+						text = string(runes[:dryRunPreviewLength]) + "…"
+					}
+					fmt.Fprintf(w, "✓ %-7s %s → %d %s: %s\n", endpoint.Method, route, status, mediaType, text)
+					continue
+				}
+
+				// The server falls back to a placeholder body when generation fails,
+				// so check the schema directly to surface the error
+				if endpoint.Example == nil && validator.ResponseSchema(operation, statusKey) != nil {
+					if _, err := rnd.gen.GenerateResponse(operation, statusKey); err != nil {
+						failed++
+						fmt.Fprintf(w, "❌ %-7s %s → %v\n", endpoint.Method, route, err)
+						continue
+					}
+				}
+
+				response, err := s.generateMockResponse(rnd, endpoint, operation, statusKey)
+				if err != nil {
+					failed++
+					fmt.Fprintf(w, "❌ %-7s %s → %v\n", endpoint.Method, route, err)
+					continue
+				}
+				body, err := json.Marshal(response)
+				if err != nil {
+					failed++
+					fmt.Fprintf(w, "❌ %-7s %s → %v\n", endpoint.Method, route, err)
+					continue
+				}
+				preview := string(body)
+				if runes := []rune(preview); len(runes) > dryRunPreviewLength {
+					preview = string(runes[:dryRunPreviewLength]) + "…"
+				}
+				fmt.Fprintf(w, "✓ %-7s %s → %d, %d bytes: %s\n", endpoint.Method, route, status, len(body), preview)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d endpoints, %d failed\n", total, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d endpoints failed to generate", failed, total)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// graphQLRequest is the standard GraphQL-over-HTTP request body
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLError is an entry of a GraphQL response's errors list
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// graphQLScalars maps built-in GraphQL scalars to the schemas their values are generated from
+var graphQLScalars = map[string]*openapi3.Schema{
+	"String":  {Type: &openapi3.Types{"string"}},
+	"ID":      {Type: &openapi3.Types{"string"}, Format: "uuid"},
+	"Int":     {Type: &openapi3.Types{"integer"}, Min: float64Ptr(0), Max: float64Ptr(1000)},
+	"Float":   {Type: &openapi3.Types{"number"}, Min: float64Ptr(0), Max: float64Ptr(1000)},
+	"Boolean": {Type: &openapi3.Types{"boolean"}},
+}
+
+// handleGraphQL answers a GraphQL query with mock data shaped by the selection set
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request, schema *parser.GraphQLSchema) {
+	s.setResponseHeaders(w)
+
+	if !s.limitBody(w, r) {
+		return
+	}
+
+	var req graphQLRequest
+	switch r.Method {
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeGraphQL(w, r, http.StatusBadRequest, nil, []graphQLError{{Message: "invalid request body: " + err.Error()}})
+			return
+		}
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	op, err := parser.ParseGraphQLQuery(req.Query, req.OperationName)
+	if err != nil {
+		s.writeGraphQL(w, r, http.StatusBadRequest, nil, []graphQLError{{Message: "invalid query: " + err.Error()}})
+		return
+	}
+
+	rootType := schema.QueryType
+	if op.Type == "mutation" {
+		rootType = schema.MutationType
+	}
+	root, ok := schema.Types[rootType]
+	if rootType == "" || !ok {
+		s.writeGraphQL(w, r, http.StatusBadRequest, nil, []graphQLError{{Message: fmt.Sprintf("schema does not support %s operations", op.Type)}})
+		return
+	}
+
+	// Introspection fields are answered from the SDL; the rest get mock data
+	var fields []parser.GraphQLSelection
+	introspected := make(map[string]interface{})
+	for _, selection := range op.Selections {
+		if op.Type == "query" && isIntrospection(selection) {
+			introspected[selection.Alias] = introspect(schema, selection, req.Variables)
+			continue
+		}
+		fields = append(fields, selection)
+	}
+
+	var errs []graphQLError
+	data := s.resolveGraphQLObject(s.randomFor(r), schema, root, fields, &errs)
+	maps.Copy(data, introspected)
+	s.writeGraphQL(w, r, http.StatusOK, data, errs)
+}
+
+// writeGraphQL sends a GraphQL response with data and any errors; successful
+// responses are delayed like any other mock response
+func (s *Server) writeGraphQL(w http.ResponseWriter, r *http.Request, status int, data interface{}, errs []graphQLError) {
+	response := map[string]interface{}{"data": data}
+	if len(errs) > 0 {
+		response["errors"] = errs
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		s.logger.Errorf("Error encoding GraphQL response: %v", err)
+		http.Error(w, "failed to encode mock response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+
+	if status == http.StatusOK && !s.sleep(r, s.responseDelay(s.options.Latency, len(body))) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Mocktail-Server", "true")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		s.logger.Errorf("Error writing GraphQL response: %v", err)
+	}
+}
+
+// resolveGraphQLObject generates a value for each selected field of an object type,
+// skipping fields of fragments whose type condition t doesn't satisfy
+func (s *Server) resolveGraphQLObject(rnd *requestRandom, schema *parser.GraphQLSchema, t *parser.GraphQLType, selections []parser.GraphQLSelection, errs *[]graphQLError) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, selection := range selections {
+		if !satisfies(schema, t, selection.On) {
+			continue
+		}
+		if selection.Name == "__typename" {
+			result[selection.Alias] = t.Name
+			continue
+		}
+
+		field, ok := t.Field(selection.Name)
+		if !ok {
+			*errs = append(*errs, graphQLError{Message: fmt.Sprintf("Cannot query field %q on type %q", selection.Name, t.Name)})
+			result[selection.Alias] = nil
+			continue
+		}
+		result[selection.Alias] = s.resolveGraphQLValue(rnd, schema, field.Type, selection.Selections, errs)
+	}
+	return result
+}
+
+// resolveGraphQLValue generates a value for a field type: lists, scalars, enums, and objects
+func (s *Server) resolveGraphQLValue(rnd *requestRandom, schema *parser.GraphQLSchema, ref parser.GraphQLTypeRef, selections []parser.GraphQLSelection, errs *[]graphQLError) interface{} {
+	if ref.OfType != nil {
+		items := make([]interface{}, s.listSize(rnd))
+		for i := range items {
+			items[i] = s.resolveGraphQLValue(rnd, schema, *ref.OfType, selections, errs)
+		}
+		return items
+	}
+
+	if scalar, ok := graphQLScalars[ref.Name]; ok {
+		value, err := rnd.gen.GenerateFromSchema(scalar)
+		if err != nil {
+			return nil
+		}
+		return value
+	}
+
+	t, ok := schema.Types[ref.Name]
+	if !ok {
+		*errs = append(*errs, graphQLError{Message: fmt.Sprintf("Unknown type %q", ref.Name)})
+		return nil
+	}
+
+	switch t.Kind {
+	case "enum":
+		if len(t.Values) == 0 {
+			return nil
+		}
+		value := t.Values[rnd.rng.Intn(len(t.Values))]
+		return value
+	case "scalar":
+		// Custom scalars have no known shape, so they mock as strings
+		value, _ := rnd.gen.GenerateFromSchema(graphQLScalars["String"])
+		return value
+	case "union":
+		if len(t.Values) == 0 {
+			return nil
+		}
+		member, ok := schema.Types[t.Values[rnd.rng.Intn(len(t.Values))]]
+		if !ok {
+			return nil
+		}
+		return s.resolveGraphQLObject(rnd, schema, member, selections, errs)
+	default:
+		return s.resolveGraphQLObject(rnd, schema, t, selections, errs)
+	}
+}
+
+// satisfies reports whether an object of type t matches a fragment's type condition:
+// the type itself, an interface it implements, or a union it belongs to. An empty
+// condition matches every type.
+func satisfies(schema *parser.GraphQLSchema, t *parser.GraphQLType, condition string) bool {
+	if condition == "" || condition == t.Name || slices.Contains(t.Interfaces, condition) {
+		return true
+	}
+	union, ok := schema.Types[condition]
+	return ok && union.Kind == "union" && slices.Contains(union.Values, t.Name)
+}
+
+// float64Ptr returns a pointer to f, for schema bounds
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,15 @@
+package mock
+
+import "net/http"
+
+// MockResponse is a generated response about to be sent, as seen by a ResponseHook
+type MockResponse struct {
+	Status  int
+	Headers http.Header
+	Body    interface{}
+}
+
+// ResponseHook mutates a generated response before it is sent, e.g. to sign it, encrypt
+// fields, or add headers. Returning an error answers the request with a 500 instead.
+// Hooks run once per request, possibly concurrently, so they must be goroutine-safe.
+type ResponseHook func(r *http.Request, resp *MockResponse) error
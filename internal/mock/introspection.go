@@ -0,0 +1,185 @@
+package mock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+// graphQLKinds maps SDL definition kinds to introspection __TypeKind values
+var graphQLKinds = map[string]string{
+	"type":      "OBJECT",
+	"interface": "INTERFACE",
+	"input":     "INPUT_OBJECT",
+	"enum":      "ENUM",
+	"union":     "UNION",
+	"scalar":    "SCALAR",
+}
+
+// isIntrospection reports whether a root query field asks for the schema itself
+func isIntrospection(selection parser.GraphQLSelection) bool {
+	return selection.Name == "__schema" || selection.Name == "__type"
+}
+
+// introspect answers a __schema or __type(name:) root field from the SDL, projected
+// onto the requested selection set; variables resolve $name arguments
+func introspect(schema *parser.GraphQLSchema, selection parser.GraphQLSelection, variables map[string]interface{}) interface{} {
+	var value interface{}
+	if selection.Name == "__schema" {
+		value = introspectionSchema(schema)
+	} else {
+		name := selection.Arguments["name"]
+		if variable, ok := strings.CutPrefix(name, "$"); ok {
+			name = fmt.Sprint(variables[variable])
+		}
+		if t := introspectionType(schema, name); t != nil {
+			value = t
+		}
+	}
+	return projectIntrospection(value, selection.Selections)
+}
+
+// projectIntrospection keeps the selected fields of an introspection value, under
+// their aliases, descending through objects and lists
+func projectIntrospection(value interface{}, selections []parser.GraphQLSelection) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(selections))
+		for _, selection := range selections {
+			result[selection.Alias] = projectIntrospection(v[selection.Name], selection.Selections)
+		}
+		return result
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = projectIntrospection(item, selections)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// introspectionSchema describes the whole schema as a __Schema object
+func introspectionSchema(schema *parser.GraphQLSchema) map[string]interface{} {
+	names := schema.TypeNames()
+	for name := range graphQLScalars {
+		if _, ok := schema.Types[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	types := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		types = append(types, introspectionType(schema, name))
+	}
+
+	var mutationType interface{}
+	if schema.MutationType != "" {
+		mutationType = namedTypeRef(schema, schema.MutationType)
+	}
+	return map[string]interface{}{
+		"__typename":       "__Schema",
+		"description":      nil,
+		"queryType":        namedTypeRef(schema, schema.QueryType),
+		"mutationType":     mutationType,
+		"subscriptionType": nil,
+		"types":            types,
+		"directives":       []map[string]interface{}{},
+	}
+}
+
+// introspectionType describes a named type as a __Type object, or nil if the schema
+// doesn't define it and it isn't a built-in scalar
+func introspectionType(schema *parser.GraphQLSchema, name string) map[string]interface{} {
+	result := namedTypeRef(schema, name)
+	for _, key := range []string{"description", "specifiedByURL", "fields", "interfaces", "possibleTypes", "enumValues", "inputFields"} {
+		result[key] = nil
+	}
+
+	t, ok := schema.Types[name]
+	if !ok {
+		if _, builtin := graphQLScalars[name]; builtin {
+			return result
+		}
+		return nil
+	}
+
+	switch t.Kind {
+	case "type", "interface":
+		fields := make([]map[string]interface{}, len(t.Fields))
+		for i, field := range t.Fields {
+			fields[i] = map[string]interface{}{
+				"__typename":        "__Field",
+				"name":              field.Name,
+				"description":       nil,
+				"args":              []map[string]interface{}{},
+				"type":              typeRef(schema, field.Type),
+				"isDeprecated":      false,
+				"deprecationReason": nil,
+			}
+		}
+		result["fields"] = fields
+		if t.Kind == "type" {
+			result["interfaces"] = []map[string]interface{}{}
+		} else {
+			result["possibleTypes"] = []map[string]interface{}{}
+		}
+	case "input":
+		inputFields := make([]map[string]interface{}, len(t.Fields))
+		for i, field := range t.Fields {
+			inputFields[i] = map[string]interface{}{
+				"__typename":   "__InputValue",
+				"name":         field.Name,
+				"description":  nil,
+				"type":         typeRef(schema, field.Type),
+				"defaultValue": nil,
+			}
+		}
+		result["inputFields"] = inputFields
+	case "enum":
+		values := make([]map[string]interface{}, len(t.Values))
+		for i, value := range t.Values {
+			values[i] = map[string]interface{}{
+				"__typename":        "__EnumValue",
+				"name":              value,
+				"description":       nil,
+				"isDeprecated":      false,
+				"deprecationReason": nil,
+			}
+		}
+		result["enumValues"] = values
+	case "union":
+		members := make([]map[string]interface{}, len(t.Values))
+		for i, member := range t.Values {
+			members[i] = namedTypeRef(schema, member)
+		}
+		result["possibleTypes"] = members
+	}
+	return result
+}
+
+// typeRef describes a field type, wrapping NON_NULL and LIST around the named type
+func typeRef(schema *parser.GraphQLSchema, ref parser.GraphQLTypeRef) map[string]interface{} {
+	if ref.NonNull {
+		inner := ref
+		inner.NonNull = false
+		return map[string]interface{}{"__typename": "__Type", "kind": "NON_NULL", "name": nil, "ofType": typeRef(schema, inner)}
+	}
+	if ref.OfType != nil {
+		return map[string]interface{}{"__typename": "__Type", "kind": "LIST", "name": nil, "ofType": typeRef(schema, *ref.OfType)}
+	}
+	return namedTypeRef(schema, ref.Name)
+}
+
+// namedTypeRef describes a reference to a named type by its kind and name
+func namedTypeRef(schema *parser.GraphQLSchema, name string) map[string]interface{} {
+	kind := "SCALAR"
+	if t, ok := schema.Types[name]; ok {
+		kind = graphQLKinds[t.Kind]
+	}
+	return map[string]interface{}{"__typename": "__Type", "kind": kind, "name": name, "ofType": nil}
+}
@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultThroughput is the simulated bandwidth for the size latency model (1 MiB/s)
+const defaultThroughput = 1 << 20
+
+// LatencyModel selects what makes up a response's simulated latency. The fixed
+// base latency always applies; Random and Size add to it and can be combined.
+type LatencyModel struct {
+	// Random adds a uniform jitter in [0, Options.LatencyJitter)
+	Random bool
+
+	// Size adds the encoded body size divided by Options.Throughput
+	Size bool
+}
+
+// ParseLatencyModel parses a comma-separated list of models: fixed, random, size
+func ParseLatencyModel(value string) (LatencyModel, error) {
+	var model LatencyModel
+	for _, part := range strings.Split(value, ",") {
+		switch strings.TrimSpace(part) {
+		case "", "fixed":
+		case "random":
+			model.Random = true
+		case "size":
+			model.Size = true
+		default:
+			return LatencyModel{}, fmt.Errorf("unknown latency model %q (expected fixed, random, or size)", part)
+		}
+	}
+	return model, nil
+}
+
+// responseDelay computes the simulated latency for a response body of the given size
+func (s *Server) responseDelay(base time.Duration, size int) time.Duration {
+	delay := base
+
+	if s.options.LatencyModel.Random && s.options.LatencyJitter > 0 {
+		s.mu.Lock()
+		delay += time.Duration(s.vary.Int63n(int64(s.options.LatencyJitter)))
+		s.mu.Unlock()
+	}
+
+	if s.options.LatencyModel.Size {
+		throughput := s.options.Throughput
+		if throughput <= 0 {
+			throughput = defaultThroughput
+		}
+		delay += time.Duration(float64(size) / float64(throughput) * float64(time.Second))
+	}
+
+	return delay
+}
@@ -0,0 +1,91 @@
+package mock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+func TestParseLatencyModel(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  LatencyModel
+		expectErr bool
+	}{
+		{value: "fixed", expected: LatencyModel{}},
+		{value: "", expected: LatencyModel{}},
+		{value: "size", expected: LatencyModel{Size: true}},
+		{value: "size,random", expected: LatencyModel{Size: true, Random: true}},
+		{value: "fixed, random", expected: LatencyModel{Random: true}},
+		{value: "exponential", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			model, err := ParseLatencyModel(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if model != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, model)
+			}
+		})
+	}
+}
+
+func TestResponseDelay(t *testing.T) {
+	schema := &parser.Schema{Paths: map[string][]parser.Endpoint{}}
+	base := 100 * time.Millisecond
+
+	tests := []struct {
+		name  string
+		opts  Options
+		size  int
+		check func(t *testing.T, delay time.Duration)
+	}{
+		{
+			name: "fixed ignores size",
+			size: 1 << 20,
+			check: func(t *testing.T, delay time.Duration) {
+				if delay != base {
+					t.Errorf("Expected %v, got %v", base, delay)
+				}
+			},
+		},
+		{
+			name: "size scales with body",
+			opts: Options{LatencyModel: LatencyModel{Size: true}, Throughput: 1000},
+			size: 500,
+			check: func(t *testing.T, delay time.Duration) {
+				if delay != base+500*time.Millisecond {
+					t.Errorf("Expected %v, got %v", base+500*time.Millisecond, delay)
+				}
+			},
+		},
+		{
+			name: "random stays within jitter",
+			opts: Options{LatencyModel: LatencyModel{Random: true}, LatencyJitter: 50 * time.Millisecond},
+			check: func(t *testing.T, delay time.Duration) {
+				if delay < base || delay >= base+50*time.Millisecond {
+					t.Errorf("Expected delay in [%v, %v), got %v", base, base+50*time.Millisecond, delay)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServerWithOptions(schema, 0, tt.opts)
+			for i := 0; i < 10; i++ {
+				tt.check(t, server.responseDelay(base, tt.size))
+			}
+		})
+	}
+}
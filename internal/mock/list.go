@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/validator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ListSize bounds the number of items in list responses; each response picks a
+// count in [Min, Max]
+type ListSize struct {
+	Min, Max int
+}
+
+// ParseListSize parses a fixed count such as "5" or an inclusive range such as "0-10"
+func ParseListSize(value string) (ListSize, error) {
+	low, high, isRange := strings.Cut(value, "-")
+	minSize, err := strconv.Atoi(strings.TrimSpace(low))
+	if err != nil {
+		return ListSize{}, fmt.Errorf("invalid list size %q: expected N or MIN-MAX", value)
+	}
+	maxSize := minSize
+	if isRange {
+		if maxSize, err = strconv.Atoi(strings.TrimSpace(high)); err != nil {
+			return ListSize{}, fmt.Errorf("invalid list size %q: expected N or MIN-MAX", value)
+		}
+	}
+	if minSize < 0 || maxSize < minSize {
+		return ListSize{}, fmt.Errorf("invalid list size %q: bounds must be non-negative and ordered", value)
+	}
+	return ListSize{Min: minSize, Max: maxSize}, nil
+}
+
+// listSize picks how many items the next list response holds
+func (s *Server) listSize(rnd *requestRandom) int {
+	if s.options.ListSize == nil {
+		return defaultListSize
+	}
+	size := *s.options.ListSize
+	if size.Max <= size.Min {
+		return size.Min
+	}
+	return size.Min + rnd.rng.Intn(size.Max-size.Min+1)
+}
+
+// resizeList regenerates a collection's top-level array response with the configured
+// number of items, overriding the schema's own array length
+func (s *Server) resizeList(rnd *requestRandom, operation *openapi3.Operation, statusCode string, response []interface{}) []interface{} {
+	schema := validator.ResponseSchema(operation, statusCode)
+	if schema == nil || schema.Items == nil || schema.Items.Value == nil {
+		return response
+	}
+
+	gen := rnd.gen.WithContext(generator.ContextResponse)
+	items := make([]interface{}, 0, s.listSize(rnd))
+	for len(items) < cap(items) {
+		item, err := gen.GenerateFromSchema(schema.Items.Value)
+		if err != nil {
+			return response
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,36 @@
+package mock
+
+import "testing"
+
+func TestParseListSize(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  ListSize
+		expectErr bool
+	}{
+		{value: "0", expected: ListSize{Min: 0, Max: 0}},
+		{value: "5", expected: ListSize{Min: 5, Max: 5}},
+		{value: "1-10", expected: ListSize{Min: 1, Max: 10}},
+		{value: "10-1", expectErr: true},
+		{value: "-3", expectErr: true},
+		{value: "many", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			size, err := ParseListSize(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if size != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, size)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// LogLevel is the least severe kind of message a Logger writes
+type LogLevel int
+
+const (
+	// LogInfo writes everything: startup details, every request, warnings, and errors
+	LogInfo LogLevel = iota
+	// LogWarn writes warnings and errors
+	LogWarn
+	// LogError writes only errors
+	LogError
+	// LogSilent writes nothing
+	LogSilent
+)
+
+// ParseLogLevel parses a log level name: info, warn, error, or silent
+func ParseLogLevel(value string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "info", "":
+		return LogInfo, nil
+	case "warn", "warning":
+		return LogWarn, nil
+	case "error":
+		return LogError, nil
+	case "silent", "none":
+		return LogSilent, nil
+	}
+	return LogInfo, fmt.Errorf("unknown log level %q (expected info, warn, error, or silent)", value)
+}
+
+// Logger writes the server's log lines at or above a minimum level
+type Logger struct {
+	out   *log.Logger // nil writes through the standard logger
+	level LogLevel
+}
+
+// NewLogger creates a logger writing timestamped lines at or above level to w
+func NewLogger(w io.Writer, level LogLevel) *Logger {
+	return &Logger{out: log.New(w, "", log.LstdFlags), level: level}
+}
+
+// defaultLogger writes everything through the standard logger
+var defaultLogger = &Logger{level: LogInfo}
+
+// Enabled reports whether messages of the given level are written
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level >= l.level && l.level != LogSilent
+}
+
+// Infof logs startup details and served requests
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(LogInfo, format, args...)
+}
+
+// Warnf logs conditions worth a look that don't stop a request from being served
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(LogWarn, format, args...)
+}
+
+// Errorf logs failures
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(LogError, format, args...)
+}
+
+func (l *Logger) logf(level LogLevel, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	if l.out == nil {
+		log.Printf(format, args...)
+		return
+	}
+	l.out.Printf(format, args...)
+}
+
+// standard returns a *log.Logger whose lines are logged at level, for standard library
+// components such as http.Server and httputil.ReverseProxy
+func (l *Logger) standard(level LogLevel) *log.Logger {
+	return log.New(levelWriter{logger: l, level: level}, "", 0)
+}
+
+// levelWriter logs each write as one line at a fixed level
+type levelWriter struct {
+	logger *Logger
+	level  LogLevel
+}
+
+func (w levelWriter) Write(p []byte) (int, error) {
+	w.logger.logf(w.level, "%s", strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,109 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  LogLevel
+		expectErr bool
+	}{
+		{value: "info", expected: LogInfo},
+		{value: "", expected: LogInfo},
+		{value: "WARN", expected: LogWarn},
+		{value: "error", expected: LogError},
+		{value: "silent", expected: LogSilent},
+		{value: "debug", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			level, err := ParseLogLevel(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if level != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestServerLogLevels(t *testing.T) {
+	schema, err := parser.NewOpenAPIParser().Parse("../../examples/petstore.yaml")
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		level LogLevel
+		check func(t *testing.T, output string)
+	}{
+		{
+			name:  "info logs startup and requests",
+			level: LogInfo,
+			check: func(t *testing.T, output string) {
+				for _, want := range []string{"Mocktail server starting", "GET /pets 200", "Shutting down"} {
+					if !strings.Contains(output, want) {
+						t.Errorf("Expected %q in the log, got:\n%s", want, output)
+					}
+				}
+			},
+		},
+		{
+			name:  "quiet logs nothing",
+			level: LogError,
+			check: func(t *testing.T, output string) {
+				if output != "" {
+					t.Errorf("Expected no output, got:\n%s", output)
+				}
+			},
+		},
+		{
+			name:  "silent logs nothing",
+			level: LogSilent,
+			check: func(t *testing.T, output string) {
+				if output != "" {
+					t.Errorf("Expected no output, got:\n%s", output)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output syncBuffer
+			server := NewServerWithOptions(schema, 0, Options{Logger: NewLogger(&output, tt.level)})
+			go server.Start()
+			time.Sleep(100 * time.Millisecond)
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/pets", server.Port()))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			server.Stop(ctx)
+
+			tt.check(t, output.String())
+		})
+	}
+}
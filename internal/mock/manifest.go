@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+// Route is one operation the server exposes, as listed in the route manifest
+type Route struct {
+	Method    string   `json:"method"`
+	Path      string   `json:"path"`               // full path template, including any mount prefix
+	Status    int      `json:"status"`             // status answered by default
+	Statuses  []string `json:"statuses,omitempty"` // response codes the schema declares
+	Validated bool     `json:"validated"`          // whether request bodies are checked against the schema
+}
+
+// Manifest lists the routes a running server exposes, for scripts and CI to read
+type Manifest struct {
+	URL    string  `json:"url"`
+	Routes []Route `json:"routes"` // sorted by path, then method
+}
+
+// manifestRoutes describes the operations of one registered schema path
+func (s *Server) manifestRoutes(prefix string, schema *parser.Schema, endpoints []parser.Endpoint) []Route {
+	_, graphQL := schema.Raw.(*parser.GraphQLSchema)
+
+	routes := make([]Route, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		route := Route{
+			Method:    endpoint.Method,
+			Path:      prefix + endpoint.Path,
+			Status:    endpoint.Status,
+			Validated: s.options.ValidateRequests && !graphQL,
+		}
+		if route.Status == 0 {
+			route.Status = s.getStatusCode(endpoint.Method)
+		}
+		if operation := findOperation(schema, endpoint); operation != nil && operation.Responses != nil {
+			for code := range operation.Responses.Map() {
+				route.Statuses = append(route.Statuses, code)
+			}
+			sort.Strings(route.Statuses)
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// writeManifest writes the route manifest to Options.Manifest as indented JSON
+func (s *Server) writeManifest(routes []Route) error {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	manifest := Manifest{
+		URL:    "http://localhost:" + strconv.Itoa(s.Port()),
+		Routes: routes,
+	}
+	if manifest.Routes == nil {
+		manifest.Routes = []Route{}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode route manifest: %w", err)
+	}
+	if err := os.WriteFile(s.options.Manifest, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write route manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+func TestRouteManifest(t *testing.T) {
+	schema, err := parser.NewOpenAPIParser().Parse("../../examples/petstore.yaml")
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "routes.json")
+	server := NewServerWithMounts([]Mount{{Schema: schema}, {Prefix: "/v2", Schema: signupSchema()}}, 0,
+		Options{Manifest: file, ValidateRequests: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v\n%s", err, data)
+	}
+
+	if want := fmt.Sprintf("http://localhost:%d", server.Port()); manifest.URL != want {
+		t.Errorf("Expected URL %s, got %s", want, manifest.URL)
+	}
+
+	routes := make(map[string]Route)
+	for _, route := range manifest.Routes {
+		routes[route.Method+" "+route.Path] = route
+	}
+	for _, key := range []string{"GET /pets", "POST /pets", "GET /pets/{petId}", "POST /v2/users"} {
+		route, ok := routes[key]
+		if !ok {
+			t.Errorf("Expected %s in the manifest, got %v", key, manifest.Routes)
+			continue
+		}
+		if !route.Validated {
+			t.Errorf("Expected %s to be validated", key)
+		}
+	}
+
+	if list := routes["GET /pets"]; list.Status != 200 || !slices.Contains(list.Statuses, "200") {
+		t.Errorf("Expected GET /pets to answer 200 and declare it, got %+v", list)
+	}
+	if create := routes["POST /v2/users"]; create.Status != 201 {
+		t.Errorf("Expected the mounted POST /v2/users to answer 201, got %+v", create)
+	}
+}
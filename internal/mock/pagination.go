@@ -0,0 +1,154 @@
+package mock
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// cursorFields and hasMoreFields are the normalized (lowercase, no underscores) names
+// of fields recognized as cursor pagination metadata
+var (
+	cursorFields  = []string{"nextcursor", "endcursor", "nextpagetoken"}
+	hasMoreFields = []string{"hasmore", "hasnextpage"}
+)
+
+// cursorParams are the query parameters a client may send a cursor in
+var cursorParams = []string{"cursor", "after", "pageToken", "page_token"}
+
+// normalizeField lowercases a field name and drops underscores, so next_cursor,
+// nextCursor, and NextCursor compare equal
+func normalizeField(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// encodeCursor builds an opaque cursor for the item offset a page starts at
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", offset)))
+}
+
+// decodeCursor reads the offset from a cursor; anything unreadable starts at zero
+func decodeCursor(cursor string) int {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0
+	}
+	var offset int
+	if _, err := fmt.Sscanf(string(data), "offset:%d", &offset); err != nil || offset < 0 {
+		return 0
+	}
+	return offset
+}
+
+// requestCursor returns the cursor a client sent, if any
+func requestCursor(r *http.Request) string {
+	query := r.URL.Query()
+	for _, param := range cursorParams {
+		if cursor := query.Get(param); cursor != "" {
+			return cursor
+		}
+	}
+	return ""
+}
+
+// cursorMetadata finds the cursor and has-more fields of a page, either at the top
+// level or in a nested object such as pageInfo. Keys are visited in sorted order, so
+// a page declaring several candidates always uses the same ones.
+func cursorMetadata(page map[string]interface{}) (holder map[string]interface{}, cursorKey, hasMoreKey string) {
+	keys := sortedKeys(page)
+	for _, key := range keys {
+		name := normalizeField(key)
+		switch {
+		case slices.Contains(cursorFields, name) && cursorKey == "":
+			holder, cursorKey = page, key
+		case slices.Contains(hasMoreFields, name) && hasMoreKey == "":
+			hasMoreKey = key
+		}
+	}
+	if cursorKey != "" {
+		return holder, cursorKey, hasMoreKey
+	}
+
+	for _, key := range keys {
+		if nested, ok := page[key].(map[string]interface{}); ok {
+			if holder, cursorKey, hasMoreKey = cursorMetadata(nested); cursorKey != "" {
+				return holder, cursorKey, hasMoreKey
+			}
+		}
+	}
+	return nil, "", ""
+}
+
+// isPage reports whether a response object carries cursor pagination fields
+func isPage(response interface{}) bool {
+	page, ok := response.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, cursorKey, _ := cursorMetadata(page)
+	return cursorKey != ""
+}
+
+// pageItemsKey returns the page's first array field, which holds its items
+func pageItemsKey(page map[string]interface{}) string {
+	for _, key := range sortedKeys(page) {
+		if _, ok := page[key].([]interface{}); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns an object's keys in sorted order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// paginate fills a page's cursor fields so clients can walk the collection. The
+// request's cursor gives the page's starting offset; stored items are sliced to one
+// page and there is more to come until the page reaches the last item, while generated
+// pages go on as long as they hold items. An empty page always ends the collection,
+// so clients never ask for the same offset again.
+func (s *Server) paginate(rnd *requestRandom, r *http.Request, response interface{}, stored bool) interface{} {
+	page, ok := response.(map[string]interface{})
+	if !ok {
+		return response
+	}
+	holder, cursorKey, hasMoreKey := cursorMetadata(page)
+	if cursorKey == "" {
+		return response
+	}
+
+	itemsKey := pageItemsKey(page)
+	if itemsKey == "" {
+		return response
+	}
+	items := page[itemsKey].([]interface{})
+
+	offset := decodeCursor(requestCursor(r))
+	next := offset + len(items)
+	hasMore := next > offset
+	if stored {
+		start := min(offset, len(items))
+		end := min(start+s.listSize(rnd), len(items))
+		page[itemsKey] = items[start:end]
+		next, hasMore = end, end > start && end < len(items)
+	}
+
+	holder[cursorKey] = encodeCursor(next)
+	if !hasMore {
+		holder[cursorKey] = nil
+	}
+	if hasMoreKey != "" {
+		holder[hasMoreKey] = hasMore
+	}
+	return page
+}
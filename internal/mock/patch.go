@@ -0,0 +1,297 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/validator"
+)
+
+// errPatchTestFailed marks a JSON Patch whose test operation didn't match, answered
+// with 409 Conflict rather than 422
+var errPatchTestFailed = errors.New("test operation failed")
+
+// patchItem applies a PATCH request body to a copy of a stored item according to its
+// Content-Type: RFC 7386 merge patch, RFC 6902 JSON Patch, or a shallow merge of a
+// plain JSON object. The body is restored for later readers.
+func patchItem(item map[string]interface{}, r *http.Request) (map[string]interface{}, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case validator.MergePatchMediaType:
+		var patch interface{}
+		if err := json.Unmarshal(body, &patch); err != nil {
+			return nil, fmt.Errorf("invalid merge patch: %w", err)
+		}
+		patched, ok := applyMergePatch(deepCopy(item), patch).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("merge patch must be a JSON object")
+		}
+		return patched, nil
+	case validator.JSONPatchMediaType:
+		var ops []map[string]interface{}
+		if err := json.Unmarshal(body, &ops); err != nil {
+			return nil, fmt.Errorf("invalid JSON Patch: %w", err)
+		}
+		patched, err := applyJSONPatch(deepCopy(item), ops)
+		if err != nil {
+			return nil, err
+		}
+		object, ok := patched.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("patched resource must stay a JSON object")
+		}
+		return object, nil
+	default:
+		patched := copyItem(item)
+		mergeRequestBody(patched, r)
+		return patched, nil
+	}
+}
+
+// applyMergePatch applies an RFC 7386 merge patch: objects merge recursively, null
+// removes a field, and any other value replaces the target
+func applyMergePatch(target, patch interface{}) interface{} {
+	fields, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+	}
+	for key, value := range fields {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = applyMergePatch(object[key], value)
+	}
+	return object
+}
+
+// applyJSONPatch applies RFC 6902 operations in order, stopping at the first failure
+func applyJSONPatch(doc interface{}, ops []map[string]interface{}) (interface{}, error) {
+	for i, op := range ops {
+		name, _ := op["op"].(string)
+		path, _ := op["path"].(string)
+		from, _ := op["from"].(string)
+		value := op["value"]
+
+		var err error
+		switch name {
+		case "add":
+			doc, err = pointerAdd(doc, path, value)
+		case "remove":
+			doc, _, err = pointerRemove(doc, path)
+		case "replace":
+			if doc, _, err = pointerRemove(doc, path); err == nil {
+				doc, err = pointerAdd(doc, path, value)
+			}
+		case "move":
+			var moved interface{}
+			if doc, moved, err = pointerRemove(doc, from); err == nil {
+				doc, err = pointerAdd(doc, path, moved)
+			}
+		case "copy":
+			var copied interface{}
+			if copied, err = pointerGet(doc, from); err == nil {
+				doc, err = pointerAdd(doc, path, deepCopy(copied))
+			}
+		case "test":
+			var actual interface{}
+			if actual, err = pointerGet(doc, path); err == nil && !jsonEqual(actual, value) {
+				err = fmt.Errorf("%w: %s does not match", errPatchTestFailed, path)
+			}
+		default:
+			err = fmt.Errorf("unknown operation %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s): %w", i, name, err)
+		}
+	}
+	return doc, nil
+}
+
+// pointerTokens splits a JSON Pointer into unescaped reference tokens
+func pointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerGet returns the value a JSON Pointer refers to
+func pointerGet(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := pointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %s not found", pointer)
+			}
+			doc = value
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("path %s not found", pointer)
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("path %s not found", pointer)
+		}
+	}
+	return doc, nil
+}
+
+// pointerAdd returns doc with value added at the pointer: set on an object, inserted
+// into an array ("-" appends), or replacing the whole document at ""
+func pointerAdd(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := pointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, pointer, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[key] = value
+			return node, nil
+		case []interface{}:
+			i := len(node)
+			if key != "-" {
+				if i, err = strconv.Atoi(key); err != nil || i < 0 || i > len(node) {
+					return nil, fmt.Errorf("array index %q out of range at %s", key, pointer)
+				}
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("path %s not found", pointer)
+	})
+}
+
+// pointerRemove returns doc without the value at the pointer, and that value
+func pointerRemove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := pointerTokens(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	var removed interface{}
+	doc, err = pointerUpdate(doc, pointer, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("path %s not found", pointer)
+			}
+			removed = value
+			delete(node, key)
+			return node, nil
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("path %s not found", pointer)
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %s not found", pointer)
+	})
+	return doc, removed, err
+}
+
+// pointerUpdate walks to the parent of the last token and replaces it with what
+// update returns, so arrays can grow or shrink
+func pointerUpdate(doc interface{}, pointer string, tokens []string, update func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return update(doc, tokens[0])
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path %s not found", pointer)
+		}
+		updated, err := pointerUpdate(child, pointer, tokens[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = updated
+		return node, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i >= len(node) {
+			return nil, fmt.Errorf("path %s not found", pointer)
+		}
+		updated, err := pointerUpdate(node[i], pointer, tokens[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = updated
+		return node, nil
+	}
+	return nil, fmt.Errorf("path %s not found", pointer)
+}
+
+// jsonEqual compares two values as JSON, so an int64 from generated data equals the
+// float64 a request body decodes to
+func jsonEqual(a, b interface{}) bool {
+	var left, right interface{}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	if errA != nil || errB != nil || json.Unmarshal(encodedA, &left) != nil || json.Unmarshal(encodedB, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// deepCopy copies nested objects and arrays so patching never touches a stored item
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = deepCopy(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = deepCopy(item)
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		patch     string
+		expected  string
+		expectErr error
+	}{
+		{
+			name:     "add replace and remove",
+			doc:      `{"a": 1, "b": {"c": 2}}`,
+			patch:    `[{"op": "add", "path": "/d", "value": 4}, {"op": "replace", "path": "/b/c", "value": 3}, {"op": "remove", "path": "/a"}]`,
+			expected: `{"b": {"c": 3}, "d": 4}`,
+		},
+		{
+			name:     "array insert append and remove",
+			doc:      `{"list": [1, 2, 3]}`,
+			patch:    `[{"op": "add", "path": "/list/1", "value": 9}, {"op": "add", "path": "/list/-", "value": 7}, {"op": "remove", "path": "/list/0"}]`,
+			expected: `{"list": [9, 2, 3, 7]}`,
+		},
+		{
+			name:     "move and copy",
+			doc:      `{"from": {"x": 1}, "keep": "k"}`,
+			patch:    `[{"op": "copy", "from": "/keep", "path": "/copied"}, {"op": "move", "from": "/from/x", "path": "/moved"}]`,
+			expected: `{"from": {}, "keep": "k", "copied": "k", "moved": 1}`,
+		},
+		{
+			name:     "escaped tokens",
+			doc:      `{"a/b": 1, "m~n": 2}`,
+			patch:    `[{"op": "remove", "path": "/a~1b"}, {"op": "replace", "path": "/m~0n", "value": 3}]`,
+			expected: `{"m~n": 3}`,
+		},
+		{
+			name:      "failed test",
+			doc:       `{"a": 1}`,
+			patch:     `[{"op": "test", "path": "/a", "value": 2}]`,
+			expectErr: errPatchTestFailed,
+		},
+		{
+			name:  "missing path",
+			doc:   `{"a": 1}`,
+			patch: `[{"op": "replace", "path": "/b", "value": 2}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc interface{}
+			var ops []map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatalf("Invalid doc: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.patch), &ops); err != nil {
+				t.Fatalf("Invalid patch: %v", err)
+			}
+
+			result, err := applyJSONPatch(doc, ops)
+			if tt.expected == "" {
+				if err == nil {
+					t.Fatalf("Expected an error, got %v", result)
+				}
+				if tt.expectErr != nil && !errors.Is(err, tt.expectErr) {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var expected interface{}
+			json.Unmarshal([]byte(tt.expected), &expected)
+			if !jsonEqual(result, expected) {
+				t.Errorf("Expected %s, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	var target, patch, expected interface{}
+	json.Unmarshal([]byte(`{"a": "b", "c": {"d": "e", "f": "g"}, "list": [1]}`), &target)
+	json.Unmarshal([]byte(`{"a": "z", "c": {"f": null}, "list": [2, 3], "new": {"x": 1}}`), &patch)
+	json.Unmarshal([]byte(`{"a": "z", "c": {"d": "e"}, "list": [2, 3], "new": {"x": 1}}`), &expected)
+
+	if result := applyMergePatch(target, patch); !jsonEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
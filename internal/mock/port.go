@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of ports the server may listen on; Start binds
+// the first free one
+type PortRange struct {
+	Min, Max int
+}
+
+// String formats the range as MIN-MAX
+func (r PortRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// ParsePortRange parses an inclusive port range such as "8080-8090"
+func ParsePortRange(value string) (PortRange, error) {
+	low, high, ok := strings.Cut(value, "-")
+	if !ok {
+		return PortRange{}, fmt.Errorf("invalid port range %q: expected MIN-MAX", value)
+	}
+	minPort, errLow := strconv.Atoi(strings.TrimSpace(low))
+	maxPort, errHigh := strconv.Atoi(strings.TrimSpace(high))
+	if errLow != nil || errHigh != nil {
+		return PortRange{}, fmt.Errorf("invalid port range %q: expected MIN-MAX", value)
+	}
+	if minPort < 1 || maxPort > 65535 || maxPort < minPort {
+		return PortRange{}, fmt.Errorf("invalid port range %q: ports must be ordered and within 1-65535", value)
+	}
+	return PortRange{Min: minPort, Max: maxPort}, nil
+}
+
+// Port returns the port the server listens on. Once Start has bound it, this is the
+// resolved port, such as the one picked from Options.PortRange.
+func (s *Server) Port() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.port
+}
+
+// listen binds the configured port, or with Options.PortRange the first free port in
+// the range, and records the port it got
+func (s *Server) listen() (net.Listener, error) {
+	candidates := []int{s.Port()}
+	if r := s.options.PortRange; r != nil {
+		candidates = candidates[:0]
+		for port := r.Min; port <= r.Max; port++ {
+			candidates = append(candidates, port)
+		}
+	}
+
+	var lastErr error
+	for _, port := range candidates {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		s.mu.Lock()
+		s.port = listener.Addr().(*net.TCPAddr).Port
+		s.mu.Unlock()
+		if s.options.PortRange != nil {
+			s.logger.Infof("🔌 Picked port %d from range %s", s.Port(), s.options.PortRange)
+		}
+		return listener, nil
+	}
+
+	if s.options.PortRange != nil {
+		return nil, fmt.Errorf("no free port in range %s: %w", s.options.PortRange, lastErr)
+	}
+	return nil, fmt.Errorf("server failed: %w", lastErr)
+}
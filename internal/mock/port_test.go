@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  PortRange
+		expectErr bool
+	}{
+		{value: "8080-8090", expected: PortRange{Min: 8080, Max: 8090}},
+		{value: "9000-9000", expected: PortRange{Min: 9000, Max: 9000}},
+		{value: "8090-8080", expectErr: true},
+		{value: "8080", expectErr: true},
+		{value: "0-10", expectErr: true},
+		{value: "65000-70000", expectErr: true},
+		{value: "a-b", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			ports, err := ParsePortRange(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ports != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, ports)
+			}
+		})
+	}
+}
+
+func TestPortRangeSkipsOccupiedPort(t *testing.T) {
+	occupied, err := net.Listen("tcp", ":8138")
+	if err != nil {
+		t.Fatalf("Failed to occupy port: %v", err)
+	}
+	defer occupied.Close()
+
+	schema := &parser.Schema{Type: "openapi", Paths: map[string][]parser.Endpoint{}}
+	server := NewServerWithOptions(schema, 0, Options{PortRange: &PortRange{Min: 8138, Max: 8139}})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	if port := server.Port(); port != 8139 {
+		t.Fatalf("Expected the server to skip occupied port 8138 and pick 8139, got %d", port)
+	}
+	resp, err := http.Get("http://localhost:8139/health")
+	if err != nil {
+		t.Fatalf("Failed to reach the server on its picked port: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestPortRangeExhausted(t *testing.T) {
+	occupied, err := net.Listen("tcp", ":8140")
+	if err != nil {
+		t.Fatalf("Failed to occupy port: %v", err)
+	}
+	defer occupied.Close()
+
+	schema := &parser.Schema{Type: "openapi", Paths: map[string][]parser.Endpoint{}}
+	server := NewServerWithOptions(schema, 0, Options{PortRange: &PortRange{Min: 8140, Max: 8140}})
+	if err := server.Start(); err == nil {
+		t.Error("Expected an error when every port in the range is taken")
+	}
+}
@@ -0,0 +1,60 @@
+package mock
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+// redacted replaces the values of configured sensitive headers in logs and recordings
+const redacted = "[redacted]"
+
+// ParseHeaders parses repeatable "Name: value" flags into a header set
+func ParseHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, value := range values {
+		name, content, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: expected 'Name: value'", value)
+		}
+		header.Add(name, strings.TrimSpace(content))
+	}
+	return header, nil
+}
+
+// newProxy forwards requests to the upstream in Options.Proxy, adding the configured
+// headers so protected backends accept them and, with ProxyInsecure, trusting any
+// certificate the upstream presents
+func (s *Server) newProxy() http.Handler {
+	target := s.options.Proxy
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorLog = s.logger.standard(LogError)
+	if s.options.ProxyInsecure {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		proxy.Transport = transport
+	}
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		r.Host = target.Host
+		for name, values := range s.options.ProxyHeaders {
+			r.Header[name] = values
+		}
+	}
+	return proxy
+}
+
+// redactHeaders flattens headers for a recording, hiding the values of injected ones
+func (s *Server) redactHeaders(header http.Header) map[string]string {
+	flat := flattenHeaders(header)
+	for name := range s.options.ProxyHeaders {
+		if _, ok := flat[name]; ok {
+			flat[name] = redacted
+		}
+	}
+	return flat
+}
@@ -0,0 +1,199 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RateLimit is the request rate each client may sustain: Requests per Per, with
+// bursts of up to Requests
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// String formats the limit as N/unit, e.g. 10/s
+func (l RateLimit) String() string {
+	switch l.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", l.Requests)
+	case time.Minute:
+		return fmt.Sprintf("%d/m", l.Requests)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", l.Requests)
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// ParseRateLimit parses "N/s", "N/m", "N/h", or "N/<duration>" such as "5/10s"
+func ParseRateLimit(value string) (RateLimit, error) {
+	count, unit, ok := strings.Cut(value, "/")
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected N/s, N/m, N/h, or N/<duration>", value)
+	}
+
+	var per time.Duration
+	switch unit = strings.TrimSpace(unit); unit {
+	case "s", "sec":
+		per = time.Second
+	case "m", "min":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		if per, err = time.ParseDuration(unit); err != nil || per <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected N/s, N/m, N/h, or N/<duration>", value)
+		}
+	}
+	return RateLimit{Requests: requests, Per: per}, nil
+}
+
+// bucket holds one client's tokens as of its last refill
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket per client IP
+type rateLimiter struct {
+	limit RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time        // when idle buckets were last dropped
+	now     func() time.Time // replaced in tests
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	return &rateLimiter{limit: limit, buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// allow takes a token from the client's bucket, or reports how long until one is free
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	capacity := float64(l.limit.Requests)
+	rate := capacity / l.limit.Per.Seconds() // tokens per second
+
+	// A bucket idle for a whole period has refilled, so dropping it changes nothing
+	// and keeps the map from growing with every client ever seen
+	if now.Sub(l.swept) >= l.limit.Per {
+		for client, b := range l.buckets {
+			if now.Sub(b.last) >= l.limit.Per {
+				delete(l.buckets, client)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// rateLimitMiddleware answers requests for mocked endpoints with 429 Too Many Requests
+// once their client exceeds Options.RateLimit. Other routes, such as /health and the
+// admin endpoints, are never limited.
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema, endpoint, ok := s.findEndpoint(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		allowed, wait := s.limiter.allow(clientIP(r))
+		if allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Retry-After is in whole seconds, so round up to avoid retrying too early
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		w.Header().Set("X-Mocktail-Server", "true")
+
+		// Serve the spec's own 429 response when the operation declares one
+		operation := findOperation(schema, endpoint)
+		if contentType, body, ok := s.rateLimitedBody(r, endpoint, operation); ok {
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			if r.Method != http.MethodHead {
+				w.Write(body)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "rate limit exceeded",
+			"limit": s.limiter.limit.String(),
+		})
+	})
+}
+
+// rateLimitedBody generates the operation's declared 429 body in its media type: raw
+// bytes for file downloads, raw text for plain-text scalars, and JSON otherwise. ok is
+// false when no 429 is declared or its body can't be generated.
+func (s *Server) rateLimitedBody(r *http.Request, endpoint parser.Endpoint, operation *openapi3.Operation) (contentType string, body []byte, ok bool) {
+	const statusKey = "429"
+	if operation == nil || operation.Responses == nil || operation.Responses.Value(statusKey) == nil {
+		return "", nil, false
+	}
+	rnd := s.randomFor(r)
+
+	if mediaType, fileSchema, ok := generator.FindBinaryContent(operation, statusKey); ok {
+		return mediaType, rnd.gen.GenerateBinary(mediaType, fileSchema), true
+	}
+	if mediaType, textSchema, ok := generator.FindTextContent(operation, statusKey); ok {
+		text, err := s.generateText(rnd, endpoint, operation, statusKey, textSchema)
+		if err != nil {
+			return "", nil, false
+		}
+		return textContentType(mediaType), []byte(text), true
+	}
+
+	generated, err := rnd.gen.GenerateResponse(operation, statusKey)
+	if err != nil {
+		return "", nil, false
+	}
+	if body, err = json.Marshal(generated); err != nil {
+		return "", nil, false
+	}
+	if contentType = generator.ResponseMediaType(operation, statusKey); contentType == "" {
+		contentType = "application/json"
+	}
+	return contentType, append(body, '\n'), true
+}
+
+// clientIP returns the host part of the request's remote address
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,208 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  RateLimit
+		expectErr bool
+	}{
+		{value: "10/s", expected: RateLimit{Requests: 10, Per: time.Second}},
+		{value: "100/m", expected: RateLimit{Requests: 100, Per: time.Minute}},
+		{value: "5/h", expected: RateLimit{Requests: 5, Per: time.Hour}},
+		{value: "5/10s", expected: RateLimit{Requests: 5, Per: 10 * time.Second}},
+		{value: "10", expectErr: true},
+		{value: "0/s", expectErr: true},
+		{value: "10/fortnight", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			limit, err := ParseRateLimit(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if limit != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, limit)
+			}
+		})
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(RateLimit{Requests: 2, Per: time.Second})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("a"); !ok {
+			t.Fatalf("Expected request %d within the burst to pass", i+1)
+		}
+	}
+	ok, wait := limiter.allow("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("Expected the third request to wait 500ms, got allowed=%v wait=%v", ok, wait)
+	}
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Error("Expected other clients to have their own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Error("Expected a token to be refilled after 500ms")
+	}
+}
+
+func TestRateLimiterEvictsIdleBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(RateLimit{Requests: 2, Per: time.Second})
+	limiter.now = func() time.Time { return now }
+
+	limiter.allow("a")
+	limiter.allow("b")
+	now = now.Add(500 * time.Millisecond)
+	limiter.allow("b")
+
+	// a has been idle a whole period and is dropped; b refilled for only 500ms and stays
+	now = now.Add(700 * time.Millisecond)
+	limiter.allow("c")
+	if _, ok := limiter.buckets["a"]; ok {
+		t.Error("Expected the idle bucket to be evicted")
+	}
+	if len(limiter.buckets) != 2 {
+		t.Errorf("Expected buckets for b and c, got %d", len(limiter.buckets))
+	}
+}
+
+func TestRateLimitedServer(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Limited API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+        '429':
+          description: Slow down
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [code]
+                properties:
+                  code:
+                    type: string
+                    enum: [TOO_MANY_REQUESTS]
+  /users:
+    get:
+      responses:
+        '200':
+          description: OK
+  /notes:
+    get:
+      responses:
+        '200':
+          description: OK
+        '429':
+          description: Slow down
+          content:
+            text/plain:
+              schema:
+                type: string
+                example: try again later
+`)
+
+	server := NewServerWithOptions(schema, 8156, Options{RateLimit: &RateLimit{Requests: 3, Per: time.Minute}})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	get := func(path string) *http.Response {
+		t.Helper()
+		resp, err := http.Get("http://localhost:8156" + path)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		return resp
+	}
+
+	// A burst of 5 against a limit of 3 per minute: the last 2 are limited
+	var statuses []int
+	for i := 0; i < 5; i++ {
+		resp := get("/items")
+		statuses = append(statuses, resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			// One token comes back every 20s
+			if got := resp.Header.Get("Retry-After"); got != "20" {
+				t.Errorf("Expected Retry-After 20, got %q", got)
+			}
+			var body map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if body["code"] != "TOO_MANY_REQUESTS" {
+				t.Errorf("Expected the spec's 429 body, got %v", body)
+			}
+		}
+		resp.Body.Close()
+	}
+	expected := []int{200, 200, 200, 429, 429}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Fatalf("Expected statuses %v, got %v", expected, statuses)
+		}
+	}
+
+	// The bucket is per client, not per endpoint; without a declared 429 a default body is sent
+	resp := get("/users")
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusTooManyRequests || body["error"] != "rate limit exceeded" {
+		t.Errorf("Expected a default 429 body, got %d %v", resp.StatusCode, body)
+	}
+
+	// A plain-text 429 is written as raw text rather than a JSON string
+	notes := get("/notes")
+	defer notes.Body.Close()
+	text, _ := io.ReadAll(notes.Body)
+	if got := notes.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Expected a text/plain 429, got %q", got)
+	}
+	if strings.HasPrefix(string(text), `"`) {
+		t.Errorf("Expected a raw text body, got %q", text)
+	}
+
+	// Health checks are never limited
+	health := get("/health")
+	health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("Expected /health to bypass the limit, got %d", health.StatusCode)
+	}
+}
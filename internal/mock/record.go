@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxRecordedBodySize caps how much of each request/response body is recorded
+const maxRecordedBodySize = 1 << 20
+
+// RecordedExchange is one line of a recording file: a served request and its response
+type RecordedExchange struct {
+	Time            time.Time         `json:"time"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query,omitempty"`
+	Route           string            `json:"route,omitempty"` // matched schema path template
+	RequestHeaders  map[string]string `json:"requestHeaders,omitempty"`
+	RequestBody     string            `json:"requestBody,omitempty"`
+	Status          int               `json:"status"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	ResponseBody    string            `json:"responseBody,omitempty"`
+	DurationMs      float64           `json:"durationMs"`
+}
+
+// recorder appends exchanges as JSON lines to a writer, safe for concurrent use
+type recorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// record writes one exchange; errors are returned so the caller can log them
+func (rec *recorder) record(exchange RecordedExchange) error {
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %w", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if _, err := rec.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// ReadRecording parses a JSONL recording produced by the mock server
+func ReadRecording(r io.Reader) ([]RecordedExchange, error) {
+	var exchanges []RecordedExchange
+
+	decoder := json.NewDecoder(r)
+	for {
+		var exchange RecordedExchange
+		if err := decoder.Decode(&exchange); err == io.EOF {
+			return exchanges, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse recording entry %d: %w", len(exchanges)+1, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+}
+
+// flattenHeaders keeps the first value of each header for a compact recording
+func flattenHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		if len(values) > 0 {
+			flat[name] = values[0]
+		}
+	}
+	return flat
+}
+
+// cappedBuffer keeps at most limit bytes, silently dropping the rest
+type cappedBuffer struct {
+	data  []byte
+	limit int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - len(b.data); room > 0 {
+		if len(p) > room {
+			b.data = append(b.data, p[:room]...)
+		} else {
+			b.data = append(b.data, p...)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,111 @@
+package mock
+
+import (
+	"encoding/json"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// redactedValue replaces writeOnly and password fields in recorded request bodies
+const redactedValue = "***"
+
+// redactRequestBody hides the values of fields the request schema marks writeOnly or
+// format: password, so recordings don't leak secrets. Bodies that aren't JSON, or that
+// hit no endpoint or no sensitive field, are returned unchanged.
+func (s *Server) redactRequestBody(method, path string, body []byte) []byte {
+	if s.options.NoRedact || len(body) == 0 {
+		return body
+	}
+
+	schema := s.requestSchema(method, path)
+	if schema == nil {
+		return body
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	if !redactValue(value, schema, make(map[*openapi3.Schema]bool)) {
+		return body
+	}
+	redactedBody, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+// requestSchema returns the request body schema of the endpoint a request hits
+func (s *Server) requestSchema(method, path string) *openapi3.Schema {
+	if _, endpoint, ok := s.findEndpoint(method, path); ok {
+		return endpoint.RequestSchema
+	}
+	return nil
+}
+
+// redactValue replaces sensitive fields of value in place, following the schema into
+// nested objects, arrays, and composition branches; seen stops recursive schemas.
+// It reports whether anything was replaced.
+func redactValue(value interface{}, schema *openapi3.Schema, seen map[*openapi3.Schema]bool) bool {
+	if schema == nil || seen[schema] {
+		return false
+	}
+	seen[schema] = true
+	defer delete(seen, schema)
+
+	changed := false
+	for _, branches := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, branch := range branches {
+			if branch != nil && redactValue(value, branch.Value, seen) {
+				changed = true
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			fieldSchema := propertySchema(schema, name)
+			if fieldSchema == nil {
+				continue
+			}
+			if isSensitive(fieldSchema) {
+				if field != redactedValue {
+					v[name] = redactedValue
+					changed = true
+				}
+				continue
+			}
+			if redactValue(field, fieldSchema, seen) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil {
+			for _, item := range v {
+				if redactValue(item, schema.Items.Value, seen) {
+					changed = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// propertySchema returns the schema of a named property, falling back to the schema
+// of additional properties
+func propertySchema(schema *openapi3.Schema, name string) *openapi3.Schema {
+	if ref, ok := schema.Properties[name]; ok && ref != nil {
+		return ref.Value
+	}
+	if ref := schema.AdditionalProperties.Schema; ref != nil {
+		return ref.Value
+	}
+	return nil
+}
+
+// isSensitive reports whether a field holds a secret clients send but servers never return
+func isSensitive(schema *openapi3.Schema) bool {
+	return schema.WriteOnly || schema.Format == "password"
+}
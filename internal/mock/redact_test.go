@@ -0,0 +1,131 @@
+package mock
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// signupSchema declares POST /users with a password and a nested writeOnly token
+func signupSchema() *parser.Schema {
+	credentials := openapi3.NewObjectSchema().
+		WithProperty("token", &openapi3.Schema{Type: &openapi3.Types{"string"}, WriteOnly: true}).
+		WithProperty("label", openapi3.NewStringSchema())
+	user := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("password", openapi3.NewStringSchema().WithFormat("password")).
+		WithProperty("keys", openapi3.NewArraySchema().WithItems(credentials))
+
+	return &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Test API",
+		Paths: map[string][]parser.Endpoint{
+			"/users": {{Method: "POST", Path: "/users", Status: http.StatusCreated, RequestSchema: user}},
+		},
+	}
+}
+
+func TestRedactRequestBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		method   string
+		path     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "password and nested writeOnly",
+			method:   "POST",
+			path:     "/users",
+			body:     `{"name":"ann","password":"hunter2","keys":[{"token":"t0k","label":"ci"}]}`,
+			expected: `{"keys":[{"label":"ci","token":"***"}],"name":"ann","password":"***"}`,
+		},
+		{
+			name:     "nothing sensitive",
+			method:   "POST",
+			path:     "/users",
+			body:     `{"name": "ann"}`,
+			expected: `{"name": "ann"}`,
+		},
+		{
+			name:     "not JSON",
+			method:   "POST",
+			path:     "/users",
+			body:     `password=hunter2`,
+			expected: `password=hunter2`,
+		},
+		{
+			name:     "unknown endpoint",
+			method:   "PUT",
+			path:     "/users",
+			body:     `{"password":"hunter2"}`,
+			expected: `{"password":"hunter2"}`,
+		},
+		{
+			name:     "disabled",
+			options:  Options{NoRedact: true},
+			method:   "POST",
+			path:     "/users",
+			body:     `{"password":"hunter2"}`,
+			expected: `{"password":"hunter2"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServerWithOptions(signupSchema(), 0, tt.options)
+			if got := string(server.redactRequestBody(tt.method, tt.path, []byte(tt.body))); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRedactRequestBodyUnderMount(t *testing.T) {
+	server := NewServerWithMounts([]Mount{{Prefix: "/auth", Schema: signupSchema()}}, 0, Options{})
+
+	if got := string(server.redactRequestBody("POST", "/auth/users", []byte(`{"password":"x"}`))); got != `{"password":"***"}` {
+		t.Errorf("Expected the mounted endpoint's schema to apply, got %s", got)
+	}
+	if got := string(server.redactRequestBody("POST", "/users", []byte(`{"password":"x"}`))); got != `{"password":"x"}` {
+		t.Errorf("Expected paths outside the mount to be left alone, got %s", got)
+	}
+}
+
+func TestRecordingRedactsPasswords(t *testing.T) {
+	var record syncBuffer
+	server := NewServerWithOptions(signupSchema(), 8142, Options{Record: &record})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Post("http://localhost:8142/users", "application/json", strings.NewReader(`{"name":"ann","password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	// The entry is written after the response is sent, so give it a moment
+	for i := 0; i < 20 && record.String() == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	exchanges, err := ReadRecording(strings.NewReader(record.String()))
+	if err != nil || len(exchanges) != 1 {
+		t.Fatalf("Expected 1 recorded exchange, got %d (%v)", len(exchanges), err)
+	}
+	if body := exchanges[0].RequestBody; strings.Contains(body, "hunter2") || !strings.Contains(body, `"password":"***"`) {
+		t.Errorf("Expected the password to be redacted, got %s", body)
+	}
+}
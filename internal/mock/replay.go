@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// replayer hands out the durations of recorded responses, route by route in recorded
+// order, so mock responses take as long as the real ones did
+type replayer struct {
+	exchanges []RecordedExchange
+	speed     float64
+
+	mu        sync.Mutex
+	mux       *http.ServeMux             // routes the index was built against
+	durations map[string][]time.Duration // "METHOD /route" -> recorded durations
+	next      map[string]int             // "METHOD /route" -> index of the next duration
+}
+
+// newReplayer indexes a recording for replay; speed scales every duration, and zero
+// or less means 1
+func newReplayer(exchanges []RecordedExchange, speed float64) *replayer {
+	if speed <= 0 {
+		speed = 1
+	}
+	return &replayer{exchanges: exchanges, speed: speed}
+}
+
+// delay returns the next recorded duration of the route a request matched on mux,
+// or false when the recording holds none for it
+func (rp *replayer) delay(mux *http.ServeMux, r *http.Request) (time.Duration, bool) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	// Recorded paths are routed like live requests, so a reload re-indexes them
+	if rp.mux != mux {
+		rp.index(mux)
+	}
+
+	key := r.Method + " " + routeFromPattern(r.Pattern)
+	durations := rp.durations[key]
+	if len(durations) == 0 {
+		return 0, false
+	}
+	d := durations[rp.next[key]%len(durations)]
+	rp.next[key]++
+	return time.Duration(float64(d) * rp.speed), true
+}
+
+// index groups the recorded durations by the route each exchange's path matches on mux
+func (rp *replayer) index(mux *http.ServeMux) {
+	rp.mux = mux
+	rp.durations = make(map[string][]time.Duration)
+	rp.next = make(map[string]int)
+	for _, exchange := range rp.exchanges {
+		req := &http.Request{Method: exchange.Method, URL: &url.URL{Path: exchange.Path}, Host: "localhost"}
+		_, pattern := mux.Handler(req)
+		if pattern == "" {
+			continue
+		}
+		key := exchange.Method + " " + routeFromPattern(pattern)
+		duration := time.Duration(exchange.DurationMs * float64(time.Millisecond))
+		rp.durations[key] = append(rp.durations[key], duration)
+	}
+}
+
+// delayFor returns how long to hold a response: the recorded duration of its route when
+// replaying latency, otherwise the simulated latency for a body of the given size
+func (s *Server) delayFor(r *http.Request, base time.Duration, size int) time.Duration {
+	if s.replayer != nil {
+		if d, ok := s.replayer.delay(s.routes.Load(), r); ok {
+			return d
+		}
+	}
+	return s.responseDelay(base, size)
+}
@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+func TestReplayLatency(t *testing.T) {
+	schema, err := parser.NewOpenAPIParser().Parse("../../examples/petstore.yaml")
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	// Recorded against a real backend: GET /pets/{petId} took 300ms, then 500ms
+	recording := []RecordedExchange{
+		{Method: "GET", Path: "/pets/1", Status: 200, DurationMs: 300},
+		{Method: "GET", Path: "/pets/2", Status: 200, DurationMs: 500},
+	}
+
+	tests := []struct {
+		name     string
+		port     int
+		speed    float64
+		path     string
+		expected []time.Duration
+	}{
+		{name: "recorded durations in order", port: 8151, path: "/pets/7", expected: []time.Duration{300 * time.Millisecond, 500 * time.Millisecond, 300 * time.Millisecond}},
+		{name: "speed multiplier", port: 8152, speed: 0.5, path: "/pets/7", expected: []time.Duration{150 * time.Millisecond, 250 * time.Millisecond}},
+		{name: "route without recordings", port: 8153, path: "/pets", expected: []time.Duration{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServerWithOptions(schema, tt.port, Options{ReplayLatency: recording, ReplaySpeed: tt.speed})
+			go server.Start()
+			time.Sleep(100 * time.Millisecond)
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+				server.Stop(ctx)
+			}()
+
+			for i, expected := range tt.expected {
+				start := time.Now()
+				resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", tt.port, tt.path))
+				if err != nil {
+					t.Fatalf("Failed to make request: %v", err)
+				}
+				resp.Body.Close()
+				elapsed := time.Since(start)
+
+				if elapsed < expected || elapsed > expected+150*time.Millisecond {
+					t.Errorf("Request %d: expected a delay of about %v, took %v", i+1, expected, elapsed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package mock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// routeOverlap describes two schema paths that can match the same request URL
+type routeOverlap struct {
+	Winner string // the path that serves overlapping URLs
+	Loser  string // the path shadowed for those URLs
+	// Ambiguous is set when neither path is more specific; the loser is not registered
+	Ambiguous bool
+}
+
+// isWildcard reports whether a path segment is a template parameter such as {id}
+func isWildcard(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// isCatchAll reports whether a path segment captures the rest of the URL, written
+// {name+} (API Gateway style) or {name...} (Go style)
+func isCatchAll(segment string) bool {
+	return isWildcard(segment) && (strings.HasSuffix(segment, "+}") || strings.HasSuffix(segment, "...}"))
+}
+
+// paramName strips catch-all markers from a template parameter name
+func paramName(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, "..."), "+")
+}
+
+// muxPattern converts a schema path into a ServeMux pattern; a trailing catch-all
+// segment becomes {name...} so it matches the remainder of the URL
+func muxPattern(path string) string {
+	i := strings.LastIndex(path, "/")
+	if last := path[i+1:]; isCatchAll(last) {
+		return path[:i+1] + "{" + paramName(last[1:len(last)-1]) + "...}"
+	}
+	return path
+}
+
+// analyzeRoutes finds schema paths that overlap and decides precedence: a static
+// segment beats a wildcard at the same position. Results are sorted for stable logs.
+func analyzeRoutes(paths []string) []routeOverlap {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	var overlaps []routeOverlap
+	shadowed := make(map[string]bool)
+
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if shadowed[a] || shadowed[b] {
+				continue
+			}
+
+			aMoreSpecific, bMoreSpecific, ok := compareRoutes(a, b)
+			if !ok {
+				continue
+			}
+
+			switch {
+			case aMoreSpecific && !bMoreSpecific:
+				overlaps = append(overlaps, routeOverlap{Winner: a, Loser: b})
+			case bMoreSpecific && !aMoreSpecific:
+				overlaps = append(overlaps, routeOverlap{Winner: b, Loser: a})
+			default:
+				// Neither wins everywhere; keep the first path in sort order
+				overlaps = append(overlaps, routeOverlap{Winner: a, Loser: b, Ambiguous: true})
+				shadowed[b] = true
+			}
+		}
+	}
+
+	return overlaps
+}
+
+// compareRoutes reports whether paths a and b can match the same URL and, if so,
+// whether each has a static segment where the other has a wildcard
+func compareRoutes(a, b string) (aStatic, bStatic, overlap bool) {
+	aSegments := strings.Split(strings.Trim(a, "/"), "/")
+	bSegments := strings.Split(strings.Trim(b, "/"), "/")
+	aCatchAll := isCatchAll(aSegments[len(aSegments)-1])
+	bCatchAll := isCatchAll(bSegments[len(bSegments)-1])
+
+	// Only the segments before a catch-all are compared; the catch-all covers the rest,
+	// so a path that spells those segments out is the more specific one
+	n := len(aSegments)
+	switch {
+	case aCatchAll && bCatchAll:
+		n = min(len(aSegments), len(bSegments)) - 1
+		aStatic = len(aSegments) > len(bSegments)
+		bStatic = len(bSegments) > len(aSegments)
+	case aCatchAll:
+		if len(bSegments) < len(aSegments) {
+			return false, false, false
+		}
+		n = len(aSegments) - 1
+		bStatic = true
+	case bCatchAll:
+		if len(aSegments) < len(bSegments) {
+			return false, false, false
+		}
+		n = len(bSegments) - 1
+		aStatic = true
+	case len(aSegments) != len(bSegments):
+		return false, false, false
+	}
+
+	for i := range n {
+		aWild, bWild := isWildcard(aSegments[i]), isWildcard(bSegments[i])
+		switch {
+		case !aWild && !bWild:
+			if aSegments[i] != bSegments[i] {
+				return false, false, false
+			}
+		case !aWild && bWild:
+			aStatic = true
+		case aWild && !bWild:
+			bStatic = true
+		}
+	}
+
+	// Two templates differing only in parameter names are the same route
+	return aStatic, bStatic, aStatic || bStatic || a != b
+}
+
+// String renders the overlap as a human-readable log line
+func (o routeOverlap) String() string {
+	if o.Ambiguous {
+		return fmt.Sprintf("%s and %s match the same URLs with no clear precedence; %s is not served", o.Winner, o.Loser, o.Loser)
+	}
+	return fmt.Sprintf("%s takes precedence over %s for matching URLs (static beats wildcard)", o.Winner, o.Loser)
+}
@@ -0,0 +1,76 @@
+package mock
+
+import "testing"
+
+func TestAnalyzeRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		expected []routeOverlap
+	}{
+		{
+			name:     "static beats wildcard",
+			paths:    []string{"/items/{id}", "/items/search"},
+			expected: []routeOverlap{{Winner: "/items/search", Loser: "/items/{id}"}},
+		},
+		{
+			name:     "no overlap across different lengths or literals",
+			paths:    []string{"/items", "/items/{id}", "/users/{id}"},
+			expected: nil,
+		},
+		{
+			name:     "ambiguous wildcards",
+			paths:    []string{"/{kind}/latest", "/items/{id}"},
+			expected: []routeOverlap{{Winner: "/items/{id}", Loser: "/{kind}/latest", Ambiguous: true}},
+		},
+		{
+			name:     "specific paths beat a catch-all",
+			paths:    []string{"/files/{path+}", "/files/{id}/meta", "/files"},
+			expected: []routeOverlap{{Winner: "/files/{id}/meta", Loser: "/files/{path+}"}},
+		},
+		{
+			name:     "deeper catch-all wins",
+			paths:    []string{"/{rest...}", "/files/{path...}"},
+			expected: []routeOverlap{{Winner: "/files/{path...}", Loser: "/{rest...}"}},
+		},
+		{
+			name:     "ambiguous catch-all",
+			paths:    []string{"/files/{path+}", "/{kind}/latest"},
+			expected: []routeOverlap{{Winner: "/files/{path+}", Loser: "/{kind}/latest", Ambiguous: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlaps := analyzeRoutes(tt.paths)
+			if len(overlaps) != len(tt.expected) {
+				t.Fatalf("Expected %d overlaps, got %v", len(tt.expected), overlaps)
+			}
+			for i := range overlaps {
+				if overlaps[i] != tt.expected[i] {
+					t.Errorf("Expected %+v, got %+v", tt.expected[i], overlaps[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMuxPattern(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/files/{path+}", expected: "/files/{path...}"},
+		{path: "/files/{path...}", expected: "/files/{path...}"},
+		{path: "/items/{id}", expected: "/items/{id}"},
+		{path: "/", expected: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := muxPattern(tt.path); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+)
+
+// matchRule returns the first config rule for an endpoint whose conditions the
+// request's JSON body meets. The body is restored so later handlers can read it.
+func (s *Server) matchRule(endpoint parser.Endpoint, r *http.Request) (RuleConfig, bool) {
+	var rules []RuleConfig
+	for _, config := range s.options.Config.endpointConfigs(endpoint.Method, endpoint.Path) {
+		rules = append(rules, config.Rules...)
+	}
+	if len(rules) == 0 || r.Body == nil {
+		return RuleConfig{}, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return RuleConfig{}, false
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return RuleConfig{}, false
+	}
+
+	for _, rule := range rules {
+		if rule.matches(value) {
+			return rule, true
+		}
+	}
+	return RuleConfig{}, false
+}
+
+// matches reports whether every field the rule names holds its expected value
+func (rule RuleConfig) matches(body interface{}) bool {
+	for path, expected := range rule.Match {
+		actual, ok := lookupField(body, path)
+		if !ok || !jsonEqual(actual, expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupField follows a dotted field path such as user.name or items.0.id into a
+// decoded JSON value; the JSONPath forms $.user.name and items[0].id work too
+func lookupField(value interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	if path == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			field, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = field
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
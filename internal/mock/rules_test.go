@@ -0,0 +1,202 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupField(t *testing.T) {
+	var body interface{}
+	json.Unmarshal([]byte(`{"user": {"name": "ann"}, "items": [{"id": 7}]}`), &body)
+
+	tests := []struct {
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{path: "user.name", expected: "ann", found: true},
+		{path: "$.user.name", expected: "ann", found: true},
+		{path: "items.0.id", expected: float64(7), found: true},
+		{path: "$.items[0].id", expected: float64(7), found: true},
+		{path: "items.1.id"},
+		{path: "user.email"},
+		{path: "user.name.first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found := lookupField(body, tt.path)
+			if found != tt.found || value != tt.expected {
+				t.Errorf("Expected (%v, %v), got (%v, %v)", tt.expected, tt.found, value, found)
+			}
+		})
+	}
+}
+
+func TestRuleResponses(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Auth API
+  version: 1.0.0
+paths:
+  /login:
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [token]
+                properties:
+                  token:
+                    type: string
+        '401':
+          description: Unauthorized
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [reason]
+                properties:
+                  reason:
+                    type: string
+                    enum: [bad credentials]
+`)
+
+	config := &Config{Endpoints: []EndpointConfig{{
+		Method: "POST",
+		Path:   "/login",
+		Rules: []RuleConfig{
+			{Match: map[string]interface{}{"username": "bad"}, Status: http.StatusUnauthorized},
+			{Match: map[string]interface{}{"$.username": "locked", "attempts": 3}, Status: http.StatusForbidden, Body: map[string]interface{}{"error": "locked"}},
+		},
+	}}}
+
+	server := NewServerWithOptions(schema, 8158, Options{Config: config})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		check          func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name:           "status rule generates from the spec",
+			body:           `{"username": "bad", "password": "x"}`,
+			expectedStatus: http.StatusUnauthorized,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["reason"] != "bad credentials" {
+					t.Errorf("Expected the spec's 401 body, got %v", body)
+				}
+			},
+		},
+		{
+			name:           "body rule",
+			body:           `{"username": "locked", "attempts": 3}`,
+			expectedStatus: http.StatusForbidden,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["error"] != "locked" {
+					t.Errorf("Expected the scripted body, got %v", body)
+				}
+			},
+		},
+		{
+			name:           "partial match falls back",
+			body:           `{"username": "locked", "attempts": 2}`,
+			expectedStatus: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if _, ok := body["token"]; !ok {
+					t.Errorf("Expected a generated token, got %v", body)
+				}
+			},
+		},
+		{
+			name:           "not JSON falls back",
+			body:           `username=bad`,
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post("http://localhost:8158/login", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.check == nil {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestRuleBodyIsCopiedPerRequest(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Auth API
+  version: 1.0.0
+paths:
+  /login:
+    post:
+      responses:
+        '201':
+          description: Created
+`)
+
+	body := map[string]interface{}{"error": "locked"}
+	config := &Config{Endpoints: []EndpointConfig{{
+		Method: "POST",
+		Path:   "/login",
+		Rules:  []RuleConfig{{Match: map[string]interface{}{"username": "locked"}, Status: http.StatusForbidden, Body: body}},
+	}}}
+	hook := func(r *http.Request, resp *MockResponse) error {
+		resp.Body.(map[string]interface{})["signed"] = true
+		return nil
+	}
+
+	server := NewServerWithOptions(schema, 8161, Options{Config: config, ResponseHook: hook})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Post("http://localhost:8161/login", "application/json", strings.NewReader(`{"username": "locked"}`))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	}
+	if _, modified := body["signed"]; modified || len(body) != 1 {
+		t.Errorf("Expected the configured rule body to stay unchanged, got %v", body)
+	}
+}
@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+)
+
+// requestRandom is the randomness behind one response: a generator and an rng
+// seeded alike, so the same request always yields the same data for a base seed
+type requestRandom struct {
+	gen *generator.Generator
+	rng *rand.Rand
+}
+
+// randomFor derives a request's seed from the base seed and a hash of its method and
+// path, so repeated requests are stable while different endpoints differ. In stateful
+// mode a per-request counter is mixed in, so successive calls advance like a session.
+// With Options.SeedHeader, a request carrying that header is seeded from its value
+// instead of the counter, so retries with the same idempotency key get the same response;
+// in stateful mode a retried POST also replays the item it created rather than adding one.
+// With Options.SeedParam, a request passing that query parameter is generated as if the
+// server ran with it as --seed, so a payload can be reproduced by sharing the seed.
+func (s *Server) randomFor(r *http.Request) *requestRandom {
+	// HEAD answers with GET's headers, ETag included, so it shares GET's data
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	key := method + " " + r.URL.Path
+	if s.options.SeedParam != "" {
+		if query := r.URL.Query(); query.Has(s.options.SeedParam) {
+			return s.randomFromSeed(generator.ParseSeed(query.Get(s.options.SeedParam)), key)
+		}
+	}
+	if s.options.SeedHeader != "" {
+		if value := r.Header.Get(s.options.SeedHeader); value != "" {
+			return s.seededRandom(key + " " + s.options.SeedHeader + ": " + value)
+		}
+	}
+	return s.randomForKey(key)
+}
+
+// maxRequestCounts bounds how many paths a per-path counter, such as stateful mode's
+// request counts or the unknown paths, holds; past it, an arbitrary path is forgotten
+// and its next request starts over as its first
+const maxRequestCounts = 10000
+
+// countRequest adds a request to key's count in counts, keeping at most maxRequestCounts
+// keys, and returns the new count; callers hold s.mu
+func countRequest(counts map[string]int, key string) int {
+	if _, counted := counts[key]; !counted && len(counts) >= maxRequestCounts {
+		for evicted := range counts {
+			delete(counts, evicted)
+			break
+		}
+	}
+	counts[key]++
+	return counts[key]
+}
+
+// randomForKey derives the randomness for a "METHOD /path" key
+func (s *Server) randomForKey(key string) *requestRandom {
+	if s.store != nil {
+		s.mu.Lock()
+		key = fmt.Sprintf("%s #%d", key, countRequest(s.requestCounts, key))
+		s.mu.Unlock()
+	}
+	return s.seededRandom(key)
+}
+
+// seededRandom returns a generator and rng seeded from the base seed and a hash of key
+func (s *Server) seededRandom(key string) *requestRandom {
+	return s.randomFromSeed(s.seed, key)
+}
+
+// randomFromSeed returns a generator and rng seeded from base and a hash of key
+func (s *Server) randomFromSeed(base int64, key string) *requestRandom {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	seed := base ^ int64(h.Sum64())
+	return &requestRandom{
+		gen: generator.NewGeneratorWithOptions(seed, s.generateOptions()),
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
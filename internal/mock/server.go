@@ -1,47 +1,451 @@
 package mock
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"math/rand"
+	"mime"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/Vooblin/mocktail/internal/generator"
 	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// adminPrefix is the path prefix for mocktail's own introspection endpoints
+const adminPrefix = "/__mocktail"
+
+// defaultMaxBodySize caps request bodies when Options.MaxBodySize is zero
+const defaultMaxBodySize = 1 << 20
+
+// defaultListSize is the number of items in a synthesized list response unless
+// Options.ListSize is set
+const defaultListSize = 2
+
+// Options configures optional mock server behavior
+type Options struct {
+	// FailOnUnknownPath answers requests for paths missing from the schema with a
+	// structured JSON 404 and records them for the admin endpoint
+	FailOnUnknownPath bool
+
+	// VaryResponses randomly picks among the operation's declared 2xx responses
+	// instead of always using the method's default status code
+	VaryResponses bool
+
+	// ValidateRequests rejects request bodies that don't match the operation's
+	// request schema with a 400
+	ValidateRequests bool
+
+	// ValidateParams rejects path parameters that don't match their schema,
+	// such as values outside an enum, with a 400
+	ValidateParams bool
+
+	// StrictContentType rejects request bodies whose Content-Type isn't one of the
+	// operation's declared request media types with a 415
+	StrictContentType bool
+
+	// Strict answers with a 500 detailing the error when a response can't be generated
+	// from the schema, instead of logging it and serving a placeholder body
+	Strict bool
+
+	// Stateful remembers resources created or updated through the mock, so reads
+	// return what was written and unknown ids read back the same data every time
+	Stateful bool
+
+	// CacheHeaders adds an ETag and Cache-Control to successful GET responses and
+	// answers 304 Not Modified when If-None-Match matches the ETag
+	CacheHeaders bool
+
+	// DeprecatedGone answers deprecated operations with 410 Gone instead of
+	// mocking them with a Deprecation header
+	DeprecatedGone bool
+
+	// Latency is the default delay added before every mock response
+	Latency time.Duration
+
+	// LatencyModel adds random jitter and/or size-proportional delay on top of Latency
+	LatencyModel LatencyModel
+
+	// LatencyJitter is the upper bound of the random latency model's extra delay
+	LatencyJitter time.Duration
+
+	// Throughput is the size latency model's simulated bandwidth in bytes per
+	// second; zero means 1 MiB/s
+	Throughput int
+
+	// BinarySize is the length of generated file downloads; zero means 1 KiB
+	BinarySize int
+
+	// UseDefaults returns a schema's declared default for any value that has one
+	UseDefaults bool
+
+	// PreferExamples returns a response's media-type example, when the spec has one,
+	// instead of generated data
+	PreferExamples bool
+
+	// MergeAnyOf merges a seeded selection of anyOf object branches into each
+	// generated value instead of always picking one branch
+	MergeAnyOf bool
+
+	// CoverEnums makes generated arrays of enum items include every enum member
+	CoverEnums bool
+
+	// JSONSafeIntegers caps generated integers at ±(2^53-1) so JavaScript clients
+	// read them exactly
+	JSONSafeIntegers bool
+
+	// ConsistentRefs aligns <name>Id fields with the id of a sibling or nested <name>
+	// object in each generated response
+	ConsistentRefs bool
+
+	// ProtoJSON shapes generated data by the protobuf JSON mapping, as gRPC-gateway
+	// APIs return it: RFC 3339 timestamps, quoted 64-bit integers, named enums
+	ProtoJSON bool
+
+	// PortRange, when set, makes Start listen on the first free port in the range
+	// instead of the server's port; Port reports the one picked
+	PortRange *PortRange
+
+	// ListSize sets how many items collection GET responses hold, overriding the
+	// schema's array bounds; nil keeps the default of 2
+	ListSize *ListSize
+
+	// Seed is the base seed each request's data is derived from, together with its
+	// method and path; nil picks one from the clock
+	Seed *int64
+
+	// SeedHeader names a request header, such as Idempotency-Key, whose value seeds the
+	// response data of requests that carry it, so the same key gets the same response
+	SeedHeader string
+
+	// SeedParam names a query parameter, such as __seed, that overrides the base seed for
+	// the request passing it; empty ignores seeds sent by clients
+	SeedParam string
+
+	// StreamLists, when positive, answers collection GETs whose response is a top-level
+	// array with this many items, generated and encoded one at a time as they are
+	// written; stateful, templated, and example responses are never streamed, nor is
+	// anything when ResponseHook or CacheHeaders is set, as both need the whole body.
+	// MinBodySize only pads objects, so it has nothing to add to a streamed array.
+	StreamLists int
+
+	// MinBodySize pads JSON object responses with a filler field until the
+	// encoded body is at least this many bytes; other responses are left as-is
+	MinBodySize int
+
+	// MaxBodySize caps request bodies; larger ones are rejected with 413. Zero means
+	// 1 MiB and a negative value disables the limit.
+	MaxBodySize int64
+
+	// Proxy, when set, forwards every request to this upstream instead of mocking it,
+	// e.g. to capture real traffic with Record
+	Proxy *url.URL
+
+	// ProxyHeaders are added to every proxied request, such as credentials for a
+	// protected staging API; their values are redacted from logs and recordings
+	ProxyHeaders http.Header
+
+	// ResponseHeaders are added to every mock response, e.g. to mark responses as
+	// mocked or to set security headers
+	ResponseHeaders http.Header
+
+	// ProxyInsecure skips TLS certificate verification of the proxy upstream, for
+	// staging backends with self-signed certificates
+	ProxyInsecure bool
+
+	// Record, when set, receives one JSON line per served request and response
+	Record io.Writer
+
+	// ReplayLatency, when set, holds recorded exchanges whose timings are reproduced: a
+	// response waits as long as the next recorded response of its route took, in place
+	// of the simulated latency. Routes the recording lacks keep the simulated latency.
+	ReplayLatency []RecordedExchange
+
+	// ReplaySpeed multiplies replayed durations, e.g. 0.5 to replay twice as fast;
+	// zero means 1
+	ReplaySpeed float64
+
+	// Logger receives the server's log lines; nil logs everything through the
+	// standard logger
+	Logger *Logger
+
+	// Manifest, when set, is a file the server writes a JSON list of its routes to on
+	// start and on every reload
+	Manifest string
+
+	// NoRedact records request bodies as sent; by default fields the schema marks
+	// writeOnly or format: password are recorded as "***"
+	NoRedact bool
+
+	// Config holds per-endpoint overrides loaded from a config file
+	Config *Config
+
+	// DisableSpecEndpoint stops serving the loaded spec at /openapi.json and /openapi.yaml
+	DisableSpecEndpoint bool
+
+	// Docs serves an interactive Swagger UI page at /docs for each OpenAPI schema;
+	// it needs the spec endpoint
+	Docs bool
+
+	// ResponseHook, when set, can rewrite each generated response before it is sent
+	ResponseHook ResponseHook
+
+	// RateLimit, when set, answers a client's requests beyond this rate with 429 Too
+	// Many Requests and a Retry-After header; clients are told apart by IP
+	RateLimit *RateLimit
+}
+
+// generateOptions returns the generator settings derived from the server options, with
+// dates counting back from the server's clock
+func (s *Server) generateOptions() generator.GenerateOptions {
+	o := s.options
+	return generator.GenerateOptions{BinarySize: o.BinarySize, UseDefaults: o.UseDefaults, PreferExamples: o.PreferExamples, MergeAnyOf: o.MergeAnyOf, CoverEnums: o.CoverEnums, JSONSafeIntegers: o.JSONSafeIntegers, ProtoJSON: o.ProtoJSON, Consistent: o.ConsistentRefs, Now: s.clock}
+}
+
+// Mount serves a parsed schema under a route prefix, so one server can front
+// several APIs, each from its own schema file and format
+type Mount struct {
+	Prefix string // e.g. "/gateway"; empty mounts at the root
+	Schema *parser.Schema
+}
+
 // Server represents a mock API server
 type Server struct {
-	schema    *parser.Schema
-	server    *http.Server
-	port      int
-	generator *generator.Generator
+	mounts   []Mount // guarded by mu; replaced by Reload
+	routes   atomic.Pointer[http.ServeMux]
+	server   *http.Server
+	port     int // guarded by mu; resolved by Start
+	seed     int64
+	options  Options
+	logger   *Logger
+	recorder *recorder
+	replayer *replayer    // nil unless Options.ReplayLatency
+	limiter  *rateLimiter // nil unless Options.RateLimit
+	store    *store       // nil unless Options.Stateful
+	// clock is the time generated dates count back from, fixed when the server is
+	// created so a request's data, and its ETag, don't change every second
+	clock time.Time
+
+	mu            sync.Mutex
+	vary          *rand.Rand     // latency jitter and --vary-responses picks, which differ between identical requests; guarded by mu
+	unknownPaths  map[string]int // "METHOD /path" -> hit count; at most maxRequestCounts
+	requestCounts map[string]int // "METHOD /path" -> requests served, in stateful mode; at most maxRequestCounts
 }
 
 // NewServer creates a new mock server from a parsed schema
 func NewServer(schema *parser.Schema, port int) *Server {
-	return &Server{
-		schema:    schema,
-		port:      port,
-		generator: generator.NewGenerator(time.Now().UnixNano()),
+	return NewServerWithOptions(schema, port, Options{})
+}
+
+// NewServerWithOptions creates a new mock server with optional behavior enabled
+func NewServerWithOptions(schema *parser.Schema, port int, options Options) *Server {
+	return NewServerWithMounts([]Mount{{Schema: schema}}, port, options)
+}
+
+// NewServerWithMounts creates a mock server serving each schema under its mount prefix
+func NewServerWithMounts(mounts []Mount, port int, options Options) *Server {
+	seed := time.Now().UnixNano()
+	if options.Seed != nil {
+		seed = *options.Seed
+	}
+	server := &Server{
+		mounts:        mounts,
+		port:          port,
+		seed:          seed,
+		vary:          rand.New(rand.NewSource(seed)),
+		options:       options,
+		clock:         time.Now(),
+		logger:        options.Logger,
+		unknownPaths:  make(map[string]int),
+		requestCounts: make(map[string]int),
 	}
+	if server.logger == nil {
+		server.logger = defaultLogger
+	}
+	if options.Record != nil {
+		server.recorder = &recorder{w: options.Record}
+	}
+	if options.Stateful {
+		server.store = newStore()
+	}
+	if options.ReplayLatency != nil {
+		server.replayer = newReplayer(options.ReplayLatency, options.ReplaySpeed)
+	}
+	if options.RateLimit != nil {
+		server.limiter = newRateLimiter(*options.RateLimit)
+	}
+	return server
 }
 
 // Start begins serving mock responses
 func (s *Server) Start() error {
+	s.mu.Lock()
+	mounts := s.mounts
+	s.mu.Unlock()
+
+	mux, count, manifest := s.buildMux(mounts)
+	s.routes.Store(mux)
+
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	if s.options.Manifest != "" {
+		if err := s.writeManifest(manifest); err != nil {
+			listener.Close()
+			return err
+		}
+	}
+
+	var handler http.Handler = http.HandlerFunc(s.serveRoutes)
+	if s.limiter != nil {
+		handler = s.rateLimitMiddleware(handler)
+	}
+	s.server = &http.Server{
+		Handler:  s.loggingMiddleware(handler),
+		ErrorLog: s.logger.standard(LogError),
+	}
+
+	s.logger.Infof("🍹 Mocktail server starting on http://localhost:%d", s.Port())
+	s.logSchemas(mounts)
+	if s.options.Proxy != nil {
+		s.logger.Infof("🔀 Proxying all requests to %s", s.options.Proxy)
+		for name := range s.options.ProxyHeaders {
+			s.logger.Infof("   adding header %s: %s", name, redacted)
+		}
+		if s.options.ProxyInsecure {
+			s.logger.Warnf("⚠️  TLS certificate verification of the upstream is disabled")
+		}
+	} else {
+		s.logger.Infof("🎯 Registered %d paths", count)
+		s.logger.Infof("🎲 Seed: %d (pass --seed to reproduce this session's data)", s.seed)
+	}
+	if count == 0 && s.options.Proxy == nil {
+		s.logger.Warnf("⚠️  No paths registered: the schema declares no paths, so every request except /health will 404")
+	}
+
+	if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("server failed: %w", err)
+	}
+
+	return nil
+}
+
+// Reload swaps in newly parsed schemas without restarting the server. Requests
+// already in flight finish against the routes they started with; stored resources,
+// the seed, and the options carry over.
+func (s *Server) Reload(mounts []Mount) {
+	mux, count, manifest := s.buildMux(mounts)
+
+	s.mu.Lock()
+	s.mounts = mounts
+	s.mu.Unlock()
+	s.routes.Store(mux)
+
+	s.logSchemas(mounts)
+	s.logger.Infof("🔄 Reloaded schema: %d paths registered", count)
+	if s.options.Manifest != "" {
+		if err := s.writeManifest(manifest); err != nil {
+			s.logger.Warnf("⚠️  %v", err)
+		}
+	}
+}
+
+// serveRoutes dispatches a request to the current routes
+func (s *Server) serveRoutes(w http.ResponseWriter, r *http.Request) {
+	s.routes.Load().ServeHTTP(w, r)
+}
+
+// logSchemas logs each mounted schema and its parse warnings
+func (s *Server) logSchemas(mounts []Mount) {
+	for _, m := range mounts {
+		if m.Prefix == "" {
+			s.logger.Infof("📋 Schema: %s (version %s)", m.Schema.Title, m.Schema.Version)
+		} else {
+			s.logger.Infof("📋 Schema: %s (version %s) mounted at %s", m.Schema.Title, m.Schema.Version, m.Prefix)
+		}
+		for _, warning := range m.Schema.Warnings {
+			s.logger.Warnf("⚠️  %s", warning)
+		}
+	}
+}
+
+// buildMux registers the routes of every mount along with the spec, docs, health,
+// and admin endpoints, returning the mux, the number of schema paths, and the
+// operations registered for the route manifest
+func (s *Server) buildMux(mounts []Mount) (*http.ServeMux, int, []Route) {
 	mux := http.NewServeMux()
 
-	// Register all endpoints from the schema - group by path
-	for path, endpoints := range s.schema.Paths {
-		// Create a closure to capture the endpoints for this path
-		pathEndpoints := endpoints
-		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
-			s.handlePath(w, r, pathEndpoints)
-		})
+	// Routes from every mount, keyed by their full (prefixed) path
+	routes := make(map[string]http.HandlerFunc)
+	operations := make(map[string][]Route)
+	for _, m := range mounts {
+		for path, endpoints := range m.Schema.Paths {
+			routes[m.Prefix+path] = s.routeHandler(m.Schema, endpoints)
+			operations[m.Prefix+path] = s.manifestRoutes(m.Prefix, m.Schema, endpoints)
+		}
+	}
+
+	// Detect overlapping paths; ambiguous ones would make the mux panic, so skip them
+	paths := make([]string, 0, len(routes))
+	for path := range routes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	skipped := make(map[string]bool)
+	for _, overlap := range analyzeRoutes(paths) {
+		s.logger.Warnf("⚠️  Route overlap: %s", overlap)
+		if overlap.Ambiguous {
+			skipped[overlap.Loser] = true
+		}
+	}
+
+	// Register all endpoints from the schema - group by path. A proxy serves
+	// every path from upstream instead.
+	var manifest []Route
+	for _, path := range paths {
+		if skipped[path] || s.options.Proxy != nil {
+			continue
+		}
+		mux.HandleFunc(muxPattern(path), routes[path])
+		manifest = append(manifest, operations[path]...)
+	}
+
+	// Serve each OpenAPI document back to clients unless it declares those paths itself
+	if s.options.Proxy != nil {
+		// Spec and docs would shadow the upstream's own paths
+	} else if !s.options.DisableSpecEndpoint {
+		for _, m := range mounts {
+			doc, ok := m.Schema.Raw.(*openapi3.T)
+			if !ok {
+				continue
+			}
+			for _, format := range []string{"json", "yaml"} {
+				path := m.Prefix + "/openapi." + format
+				if _, exists := routes[path]; !exists {
+					mux.HandleFunc(path, s.handleSpec(doc, format))
+				}
+			}
+			if _, exists := routes[m.Prefix+"/docs"]; s.options.Docs && !exists {
+				mux.HandleFunc("GET "+m.Prefix+"/docs", handleDocs)
+			}
+		}
+	} else if s.options.Docs {
+		s.logger.Warnf("⚠️  Docs disabled: /docs needs the spec endpoint")
 	}
 
 	// Health check endpoint
@@ -53,20 +457,17 @@ func (s *Server) Start() error {
 		})
 	})
 
-	s.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.port),
-		Handler: s.loggingMiddleware(mux),
-	}
-
-	log.Printf("🍹 Mocktail server starting on http://localhost:%d", s.port)
-	log.Printf("📋 Schema: %s (version %s)", s.schema.Title, s.schema.Version)
-	log.Printf("🎯 Registered %d paths", len(s.schema.Paths))
+	// Admin endpoint listing requests that didn't match any schema path
+	mux.HandleFunc(adminPrefix+"/unknown-paths", s.handleUnknownPaths)
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return fmt.Errorf("server failed: %w", err)
+	// Catch-all for paths not declared in the schema
+	if s.options.Proxy != nil {
+		mux.Handle("/", s.newProxy())
+	} else if _, exists := routes["/"]; !exists && s.options.FailOnUnknownPath {
+		mux.HandleFunc("/", s.handleUnknownPath)
 	}
 
-	return nil
+	return mux, len(routes), manifest
 }
 
 // Stop gracefully shuts down the server
@@ -75,12 +476,29 @@ func (s *Server) Stop(ctx context.Context) error {
 		return nil
 	}
 
-	log.Println("🛑 Shutting down mock server...")
+	s.logger.Infof("🛑 Shutting down mock server...")
 	return s.server.Shutdown(ctx)
 }
 
-// handlePath handles all methods for a given path
-func (s *Server) handlePath(w http.ResponseWriter, r *http.Request, endpoints []parser.Endpoint) {
+// routeHandler returns the handler serving one schema path, with its response
+// templates parsed up front rather than on every request
+func (s *Server) routeHandler(schema *parser.Schema, endpoints []parser.Endpoint) http.HandlerFunc {
+	if gql, ok := schema.Raw.(*parser.GraphQLSchema); ok {
+		return func(w http.ResponseWriter, r *http.Request) {
+			s.handleGraphQL(w, r, gql)
+		}
+	}
+	templates := s.parseTemplates(endpoints)
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.handlePath(w, r, schema, endpoints, templates)
+	}
+}
+
+// handlePath handles all methods for a given path; templates holds the parsed response
+// templates by method
+func (s *Server) handlePath(w http.ResponseWriter, r *http.Request, schema *parser.Schema, endpoints []parser.Endpoint, templates map[string]*template.Template) {
+	s.setResponseHeaders(w)
+
 	// Find the endpoint that matches the request method
 	var matchedEndpoint *parser.Endpoint
 	for i, endpoint := range endpoints {
@@ -90,53 +508,725 @@ func (s *Server) handlePath(w http.ResponseWriter, r *http.Request, endpoints []
 		}
 	}
 
+	// HEAD mirrors GET without a body unless the schema defines it explicitly
+	if matchedEndpoint == nil && r.Method == http.MethodHead {
+		for i, endpoint := range endpoints {
+			if endpoint.Method == http.MethodGet {
+				matchedEndpoint = &endpoints[i]
+				break
+			}
+		}
+	}
+
+	// Answer OPTIONS (e.g. CORS preflight) unless the schema defines it explicitly
+	if matchedEndpoint == nil && r.Method == http.MethodOptions {
+		w.Header().Set("Allow", allowedMethods(endpoints))
+		w.Header().Set("X-Mocktail-Server", "true")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	// If no matching method found, return 405
 	if matchedEndpoint == nil {
+		w.Header().Set("Allow", allowedMethods(endpoints))
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Generate mock response based on the endpoint
-	response := s.generateMockResponse(*matchedEndpoint, r)
+	if matchedEndpoint.Deprecated {
+		w.Header().Set("Deprecation", "true")
+		if s.options.DeprecatedGone {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Mocktail-Server", "true")
+			w.WriteHeader(http.StatusGone)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":  "endpoint is deprecated",
+				"method": matchedEndpoint.Method,
+				"path":   matchedEndpoint.Path,
+			})
+			return
+		}
+	}
+
+	if !s.limitBody(w, r) {
+		return
+	}
+
+	operation := findOperation(schema, *matchedEndpoint)
+
+	if s.options.StrictContentType {
+		if supported, ok := checkContentType(operation, r); !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Mocktail-Server", "true")
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":     "unsupported media type",
+				"details":   fmt.Sprintf("Content-Type %q is not accepted", r.Header.Get("Content-Type")),
+				"supported": supported,
+			})
+			return
+		}
+	}
+
+	if s.options.ValidateParams {
+		if err := validatePathParams(schema, *matchedEndpoint, r); err != nil {
+			writeValidationError(w, "path parameter validation failed", err)
+			return
+		}
+	}
+
+	if s.options.ValidateRequests {
+		if err := s.validateRequest(operation, r); err != nil {
+			writeValidationError(w, "request validation failed", err)
+			return
+		}
+	}
+
+	// A config rule matching the request body scripts the status and, optionally, the body
+	rule, ruled := s.matchRule(*matchedEndpoint, r)
+	if ruled {
+		scripted := *matchedEndpoint
+		if rule.Status != 0 {
+			scripted.Status = rule.Status
+		}
+		if rule.Body != nil {
+			// generateMockResponse serves each request its own copy of Example, so hooks
+			// and padding never edit the configured body
+			scripted.Example = rule.Body
+		}
+		matchedEndpoint = &scripted
+	}
+
+	// Pick the status code first so the body is generated from the matching response
+	statusKey, statusCode := s.chooseStatus(*matchedEndpoint, operation)
+	rnd := s.randomFor(r)
+
+	// File downloads are served as raw bytes rather than JSON
+	if mediaType, fileSchema, ok := generator.FindBinaryContent(operation, statusKey); ok && matchedEndpoint.Example == nil {
+		s.writeBinary(rnd, w, r, *matchedEndpoint, statusCode, mediaType, rnd.gen.GenerateBinary(mediaType, fileSchema))
+		return
+	}
+
+	// Plain-text scalars are written as is rather than JSON-encoded
+	if mediaType, textSchema, ok := generator.FindTextContent(operation, statusKey); ok && matchedEndpoint.Example == nil {
+		text, err := s.generateText(rnd, *matchedEndpoint, operation, statusKey, textSchema)
+		if err != nil {
+			s.logger.Errorf("❌ %v", err)
+			http.Error(w, "response generation failed", http.StatusInternalServerError)
+			return
+		}
+		s.writeText(rnd, w, r, *matchedEndpoint, statusCode, mediaType, text)
+		return
+	}
+
+	// Huge collections are streamed instead of built in memory
+	if itemSchema := s.streamItemSchema(r, *matchedEndpoint, operation, statusKey); itemSchema != nil {
+		s.writeStreamedList(rnd, w, r, *matchedEndpoint, operation, statusKey, statusCode, itemSchema)
+		return
+	}
+
+	// Generate mock response based on the endpoint, or serve it from the store
+	var response interface{}
+	stored := false
+	if s.store != nil && !ruled {
+		var status int
+		if response, status, stored = s.statefulResponse(rnd, r, *matchedEndpoint, operation, statusKey); status != 0 {
+			statusCode = status
+		}
+	}
+	if !stored {
+		var err error
+		if response, err = s.generateMockResponse(rnd, *matchedEndpoint, operation, statusKey); err != nil {
+			s.logger.Errorf("❌ %v", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Mocktail-Server", "true")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "response generation failed",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		// Generated items carry the id they were requested by; the per-request seed
+		// already keeps each id's data stable. Spec-authored examples stay as written.
+		_, hasExample := generator.ResponseExample(operation, statusKey)
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && matchedEndpoint.Example == nil && !(s.options.PreferExamples && hasExample) {
+			echoID(r, response)
+		}
+	}
+	if s.store != nil && r.Method == http.MethodGet && !strings.Contains(matchedEndpoint.Path, "{") {
+		response = s.paginate(rnd, r, response, stored)
+	}
+	if tmpl := templates[matchedEndpoint.Method]; tmpl != nil {
+		rendered, err := renderTemplate(tmpl, matchedEndpoint.Path, r, response)
+		if err != nil {
+			s.logger.Errorf("Error rendering template for %s %s: %v", matchedEndpoint.Method, matchedEndpoint.Path, err)
+		} else {
+			response = rendered
+		}
+	}
+	if s.options.MinBodySize > 0 {
+		response = s.padResponse(rnd, response, s.options.MinBodySize)
+	}
+
+	mockResponse := &MockResponse{
+		Status:  statusCode,
+		Headers: http.Header{},
+		Body:    response,
+	}
+	// Vendor types such as application/vnd.api+json are answered as declared
+	contentType := generator.ResponseMediaType(operation, statusKey)
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	mockResponse.Headers.Set("Content-Type", contentType)
+	mockResponse.Headers.Set("X-Mocktail-Server", "true")
+	s.setCookies(rnd, mockResponse.Headers, *matchedEndpoint)
+	if s.options.ResponseHook != nil {
+		if err := s.options.ResponseHook(r, mockResponse); err != nil {
+			s.logger.Errorf("Response hook failed for %s %s: %v", r.Method, r.URL.Path, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Mocktail-Server", "true")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "response hook failed",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	// Marshaled here rather than with Generator.GenerateJSON: templates, padding and the
+	// hook may have replaced the generated value by now
+	body, err := json.Marshal(mockResponse.Body)
+	if err != nil {
+		s.logger.Errorf("Error encoding response: %v", err)
+		http.Error(w, "failed to encode mock response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+
+	if s.options.CacheHeaders {
+		setCacheHeaders(r, mockResponse, body)
+	}
+
+	if !s.sleep(r, s.delayFor(r, s.latencyFor(*matchedEndpoint), len(body))) {
+		return
+	}
+
+	for name, values := range mockResponse.Headers {
+		w.Header()[name] = values
+	}
+	if mockResponse.Status == http.StatusNotModified {
+		// A 304 carries the validators but no body or content headers
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(mockResponse.Status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(body); err != nil {
+		s.logger.Errorf("Error writing response: %v", err)
+	}
+}
+
+// setResponseHeaders adds the configured Options.ResponseHeaders to a response
+func (s *Server) setResponseHeaders(w http.ResponseWriter) {
+	for name, values := range s.options.ResponseHeaders {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+}
+
+// allowedMethods lists the methods defined for a path, for the Allow header
+func allowedMethods(endpoints []parser.Endpoint) string {
+	methods := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		methods = append(methods, endpoint.Method)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// paddingField is the filler field added to JSON objects by padResponse
+const paddingField = "_mocktailPadding"
+
+// padResponse adds a filler field of random hex characters to a JSON object response
+// so its encoding reaches at least minSize bytes
+func (s *Server) padResponse(rnd *requestRandom, response interface{}, minSize int) interface{} {
+	obj, ok := response.(map[string]interface{})
+	if !ok {
+		return response
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil || len(data) >= minSize {
+		return response
+	}
+
+	// Account for the field's own key, quotes, colon, and separating comma
+	overhead := len(paddingField) + 6
+	needed := minSize - len(data) - overhead
+	if needed < 0 {
+		needed = 0
+	}
+
+	const hexDigits = "0123456789abcdef"
+	filler := make([]byte, needed)
+	for i := range filler {
+		filler[i] = hexDigits[rnd.rng.Intn(len(hexDigits))]
+	}
+
+	obj[paddingField] = string(filler)
+	return obj
+}
+
+// templateFor returns the first response template configured for an endpoint
+func (s *Server) templateFor(endpoint parser.Endpoint) string {
+	for _, config := range s.options.Config.endpointConfigs(endpoint.Method, endpoint.Path) {
+		if config.Template != "" {
+			return config.Template
+		}
+	}
+	return ""
+}
+
+// parseTemplates parses the response template configured for each endpoint, keyed by
+// method. An invalid template is logged and left out, so its endpoint is served as
+// generated; LoadConfig already rejects those, leaving only hand-built configs.
+func (s *Server) parseTemplates(endpoints []parser.Endpoint) map[string]*template.Template {
+	templates := make(map[string]*template.Template)
+	for _, endpoint := range endpoints {
+		text := s.templateFor(endpoint)
+		if text == "" {
+			continue
+		}
+		tmpl, err := parseTemplate(text)
+		if err != nil {
+			s.logger.Errorf("Invalid response template for %s %s: %v", endpoint.Method, endpoint.Path, err)
+			continue
+		}
+		templates[endpoint.Method] = tmpl
+	}
+	return templates
+}
+
+// latencyFor returns the configured delay for an endpoint, then the spec's x-mocktail-latency,
+// falling back to the global latency
+func (s *Server) latencyFor(endpoint parser.Endpoint) time.Duration {
+	for _, config := range s.options.Config.endpointConfigs(endpoint.Method, endpoint.Path) {
+		if config.Latency > 0 {
+			return config.Latency
+		}
+	}
+	if endpoint.Latency > 0 {
+		return endpoint.Latency
+	}
+	return s.options.Latency
+}
+
+// sleep waits for d unless the client goes away first; it reports whether to keep responding
+func (s *Server) sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// checkContentType reports whether a request with a body declares one of the
+// operation's request media types, returning the supported types either way.
+// Declared wildcards such as image/* or */* match any subtype or type.
+func checkContentType(operation *openapi3.Operation, r *http.Request) ([]string, bool) {
+	if operation == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil, true
+	}
+	content := operation.RequestBody.Value.Content
+	if len(content) == 0 {
+		return nil, true
+	}
+	supported := make([]string, 0, len(content))
+	for mediaType := range content {
+		supported = append(supported, mediaType)
+	}
+	sort.Strings(supported)
+
+	header := r.Header.Get("Content-Type")
+	if header == "" && (r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody) {
+		return supported, true
+	}
+	actual, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return supported, false
+	}
+	actualType, _, _ := strings.Cut(actual, "/")
+	for _, declared := range supported {
+		declared = strings.ToLower(declared)
+		declaredType, declaredSubtype, _ := strings.Cut(declared, "/")
+		if declared == actual || declared == "*/*" || (declaredSubtype == "*" && declaredType == actualType) {
+			return supported, true
+		}
+	}
+	return supported, false
+}
+
+// validateRequest checks the request body against the endpoint's request schema, as
+// a patch document when its Content-Type is a JSON Patch or merge patch. The body is
+// restored afterwards so later handlers can still read it.
+func (s *Server) validateRequest(operation *openapi3.Operation, r *http.Request) error {
+	if operation == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return validator.ValidateRequest(operation, r.Header.Get("Content-Type"), body)
+}
+
+// limitBody buffers the request body up to MaxBodySize so later handlers can read it
+// freely, answering 413 and returning false when it is larger
+func (s *Server) limitBody(w http.ResponseWriter, r *http.Request) bool {
+	limit := s.options.MaxBodySize
+	if limit == 0 {
+		limit = defaultMaxBodySize
+	}
+	if limit < 0 || r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Mocktail-Server", "true")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "request body too large",
+				"details": fmt.Sprintf("limit is %d bytes", limit),
+			})
+		} else {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+		}
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
+
+// writeValidationError answers with a 400 listing each failing field, when known
+func writeValidationError(w http.ResponseWriter, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Mocktail-Server", "true")
+	w.WriteHeader(http.StatusBadRequest)
+	body := map[string]interface{}{
+		"error":   message,
+		"details": err.Error(),
+	}
+	var validationErr *validator.ValidationError
+	if errors.As(err, &validationErr) {
+		body["errors"] = validationErr.Errors
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+// validatePathParams checks each path parameter of the request against its declared
+// schema; path-level parameters apply unless the operation redeclares them
+func validatePathParams(schema *parser.Schema, endpoint parser.Endpoint, r *http.Request) error {
+	doc, ok := schema.Raw.(*openapi3.T)
+	if !ok {
+		return nil
+	}
+	pathItem := doc.Paths.Value(endpoint.Path)
+	if pathItem == nil {
+		return nil
+	}
+
+	params := make(map[string]*openapi3.Parameter)
+	var operationParams openapi3.Parameters
+	if operation := pathItem.GetOperation(endpoint.Method); operation != nil {
+		operationParams = operation.Parameters
+	}
+	for _, refs := range []openapi3.Parameters{pathItem.Parameters, operationParams} {
+		for _, ref := range refs {
+			if ref != nil && ref.Value != nil && ref.Value.In == openapi3.ParameterInPath {
+				params[ref.Value.Name] = ref.Value
+			}
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fieldErrors []validator.FieldError
+	for _, name := range names {
+		err := validator.ValidateParameter(params[name], r.PathValue(name))
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			fieldErrors = append(fieldErrors, validationErr.Errors...)
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return &validator.ValidationError{Errors: fieldErrors}
+	}
+	return nil
+}
+
+// setCookies adds the Set-Cookie headers configured for an endpoint to header
+func (s *Server) setCookies(rnd *requestRandom, header http.Header, endpoint parser.Endpoint) {
+	for _, config := range s.options.Config.endpointConfigs(endpoint.Method, endpoint.Path) {
+		for _, cookie := range config.Cookies {
+			// Like http.SetCookie, drop cookies that do not serialize
+			if value := s.buildCookie(rnd, cookie).String(); value != "" {
+				header.Add("Set-Cookie", value)
+			}
+		}
+	}
+}
+
+// buildCookie turns a cookie config into an http.Cookie, generating the value if none is fixed
+func (s *Server) buildCookie(rnd *requestRandom, config CookieConfig) *http.Cookie {
+	value := config.Value
+	if value == "" {
+		format := config.Format
+		if format == "" {
+			format = "uuid"
+		}
+		generated, err := rnd.gen.GenerateFromSchema(&openapi3.Schema{
+			Type:   &openapi3.Types{"string"},
+			Format: format,
+		})
+		if err == nil {
+			value = fmt.Sprint(generated)
+		}
+	}
+
+	cookiePath := config.Path
+	if cookiePath == "" {
+		cookiePath = "/"
+	}
+
+	cookie := &http.Cookie{
+		Name:     config.Name,
+		Value:    value,
+		Path:     cookiePath,
+		Domain:   config.Domain,
+		MaxAge:   config.MaxAge,
+		HttpOnly: config.HttpOnly,
+		Secure:   config.Secure,
+	}
+	switch strings.ToLower(config.SameSite) {
+	case "lax":
+		cookie.SameSite = http.SameSiteLaxMode
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+
+	return cookie
+}
+
+// handleUnknownPath answers a request whose path is not declared in the schema
+func (s *Server) handleUnknownPath(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	countRequest(s.unknownPaths, r.Method+" "+r.URL.Path)
+	s.mu.Unlock()
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Mocktail-Server", "true")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  "path not found in schema",
+		"method": r.Method,
+		"path":   r.URL.Path,
+	})
+}
 
-	// Set status code based on method
-	statusCode := s.getStatusCode(matchedEndpoint.Method)
-	w.WriteHeader(statusCode)
+// unknownPathHit summarizes requests made to a path missing from the schema
+type unknownPathHit struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Count  int    `json:"count"`
+}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
+// handleUnknownPaths reports the unknown paths hit so far, sorted by path and method
+func (s *Server) handleUnknownPaths(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	hits := make([]unknownPathHit, 0, len(s.unknownPaths))
+	for key, count := range s.unknownPaths {
+		method, path, _ := strings.Cut(key, " ")
+		hits = append(hits, unknownPathHit{Method: method, Path: path, Count: count})
 	}
+	s.mu.Unlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Path != hits[j].Path {
+			return hits[i].Path < hits[j].Path
+		}
+		return hits[i].Method < hits[j].Method
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"unknownPaths": hits,
+	})
 }
 
-// generateMockResponse creates a mock response for an endpoint
-func (s *Server) generateMockResponse(endpoint parser.Endpoint, r *http.Request) interface{} {
+// findEndpoint returns the mounted schema and endpoint a request's method and URL
+// path hit, outside of the mux
+func (s *Server) findEndpoint(method, path string) (*parser.Schema, parser.Endpoint, bool) {
+	s.mu.Lock()
+	mounts := s.mounts
+	s.mu.Unlock()
+
+	for _, m := range mounts {
+		if m.Prefix != "" && path != m.Prefix && !strings.HasPrefix(path, m.Prefix+"/") {
+			continue
+		}
+		if endpoint, ok := m.Schema.FindEndpoint(method, strings.TrimPrefix(path, m.Prefix)); ok {
+			return m.Schema, endpoint, true
+		}
+	}
+	return nil, parser.Endpoint{}, false
+}
+
+// findOperation returns the OpenAPI operation backing an endpoint, if the schema has one
+func findOperation(schema *parser.Schema, endpoint parser.Endpoint) *openapi3.Operation {
+	doc, ok := schema.Raw.(*openapi3.T)
+	if !ok {
+		return nil
+	}
+	pathItem := doc.Paths.Value(endpoint.Path)
+	if pathItem == nil {
+		return nil
+	}
+	return pathItem.Operations()[endpoint.Method]
+}
+
+// chooseStatus returns the response key to generate from and the HTTP status to send.
+// An x-mocktail-status extension wins; otherwise, with VaryResponses enabled, a declared
+// 2xx response is picked at random.
+func (s *Server) chooseStatus(endpoint parser.Endpoint, operation *openapi3.Operation) (string, int) {
+	if endpoint.Status != 0 {
+		return strconv.Itoa(endpoint.Status), endpoint.Status
+	}
+
+	if s.options.VaryResponses {
+		if operation != nil {
+			if codes := successStatusCodes(operation); len(codes) > 0 {
+				s.mu.Lock()
+				code := codes[s.vary.Intn(len(codes))]
+				s.mu.Unlock()
+
+				status, _ := strconv.Atoi(code)
+				return code, status
+			}
+		}
+	}
+
+	return s.getStatusCodeString(endpoint.Method), s.getStatusCode(endpoint.Method)
+}
+
+// successStatusCodes returns the operation's explicitly declared 2xx status codes in sorted order
+func successStatusCodes(operation *openapi3.Operation) []string {
+	if operation.Responses == nil {
+		return nil
+	}
+
+	var codes []string
+	for code := range operation.Responses.Map() {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	return codes
+}
+
+// generateMockResponse creates a mock response for an endpoint using the response declared
+// for statusCode. When generation fails a placeholder body is served and the error logged;
+// in strict mode the error is returned instead.
+func (s *Server) generateMockResponse(rnd *requestRandom, endpoint parser.Endpoint, operation *openapi3.Operation, statusCode string) (interface{}, error) {
+	// Examples are shared by every request, so each response gets its own copy to
+	// modify: stateful writes, id echoing, and padding all edit the body in place
+	if endpoint.Example != nil {
+		return deepCopy(endpoint.Example), nil
+	}
+
+	// Spec-authored examples are served as written, without list wrapping
+	if s.options.PreferExamples {
+		if example, ok := generator.ResponseExample(operation, statusCode); ok {
+			return deepCopy(example), nil
+		}
+	}
+
 	// Try to generate from OpenAPI schema first
-	if doc, ok := s.schema.Raw.(*openapi3.T); ok {
-		if pathItem := doc.Paths.Value(endpoint.Path); pathItem != nil {
-			operation := pathItem.Operations()[endpoint.Method]
-			if operation != nil {
-				// Determine status code
-				statusCode := s.getStatusCodeString(endpoint.Method)
-
-				// Try to generate from schema
-				if response, err := s.generator.GenerateResponse(operation, statusCode); err == nil {
-					// For list endpoints, wrap in array structure
-					if !strings.Contains(endpoint.Path, "{") && endpoint.Method == "GET" {
-						if items, ok := response.(map[string]interface{}); ok {
-							// If the response is a single object, make it an array
-							return map[string]interface{}{
-								"data":  []interface{}{items, items}, // Generate 2 items for lists
-								"total": 2,
-							}
+	if operation != nil {
+		response, err := rnd.gen.GenerateResponse(operation, statusCode)
+		if err == nil {
+			// For list endpoints, wrap in array structure
+			if !strings.Contains(endpoint.Path, "{") && endpoint.Method == "GET" {
+				switch list := response.(type) {
+				case map[string]interface{}:
+					// Cursor-paginated pages already carry their own items
+					if isPage(list) {
+						return list, nil
+					}
+					// If the response is a single object, make it an array of
+					// independently generated items so records differ
+					size := s.listSize(rnd)
+					items := make([]interface{}, 0, size)
+					if size > 0 {
+						items = append(items, list)
+					}
+					for len(items) < size {
+						item, err := rnd.gen.GenerateResponse(operation, statusCode)
+						if err != nil {
+							break
 						}
+						items = append(items, item)
+					}
+					return map[string]interface{}{
+						"data":  items,
+						"total": len(items),
+					}, nil
+				case []interface{}:
+					if s.options.ListSize != nil {
+						return s.resizeList(rnd, operation, statusCode, list), nil
 					}
-					return response
 				}
 			}
+			return response, nil
 		}
+		if s.options.Strict {
+			return nil, fmt.Errorf("failed to generate %s response for %s %s: %w", statusCode, endpoint.Method, endpoint.Path, err)
+		}
+		s.logger.Warnf("⚠️  Generation failed for %s %s, serving a placeholder body: %v", endpoint.Method, endpoint.Path, err)
 	}
 
 	// Fallback to basic mock response structure
@@ -176,7 +1266,7 @@ func (s *Server) generateMockResponse(endpoint parser.Endpoint, r *http.Request)
 		response["message"] = "Resource deleted successfully"
 	}
 
-	return response
+	return response, nil
 }
 
 // getStatusCodeString returns the status code as a string for looking up responses
@@ -203,28 +1293,81 @@ func (s *Server) getStatusCode(method string) int {
 	}
 }
 
-// loggingMiddleware logs all incoming requests
+// loggingMiddleware logs all incoming requests and records them when recording is enabled
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		// Capture the request body for the recording, then hand it back untouched
+		var requestBody []byte
+		if s.recorder != nil && r.Body != nil {
+			requestBody, _ = io.ReadAll(io.LimitReader(r.Body, maxRecordedBodySize))
+			r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(requestBody), r.Body))
+		}
+
 		// Create a response writer wrapper to capture status code
 		lrw := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		if s.recorder != nil {
+			lrw.body = &cappedBuffer{limit: maxRecordedBodySize}
+		}
 
 		next.ServeHTTP(lrw, r)
 
 		duration := time.Since(start)
-		log.Printf("%s %s %d %v", r.Method, r.URL.Path, lrw.statusCode, duration)
+		s.logger.Infof("%s %s %d %v", r.Method, r.URL.Path, lrw.statusCode, duration)
+
+		if s.recorder != nil {
+			err := s.recorder.record(RecordedExchange{
+				Time:            start.UTC(),
+				Method:          r.Method,
+				Path:            r.URL.Path,
+				Query:           r.URL.RawQuery,
+				Route:           routeFromPattern(r.Pattern),
+				RequestHeaders:  s.redactHeaders(r.Header),
+				RequestBody:     string(s.redactRequestBody(r.Method, r.URL.Path, requestBody)),
+				Status:          lrw.statusCode,
+				ResponseHeaders: flattenHeaders(lrw.Header()),
+				ResponseBody:    string(lrw.body.data),
+				DurationMs:      float64(duration.Microseconds()) / 1000,
+			})
+			if err != nil {
+				s.logger.Errorf("Error recording request: %v", err)
+			}
+		}
 	})
 }
 
+// routeFromPattern strips the optional method prefix from a ServeMux pattern
+func routeFromPattern(pattern string) string {
+	if _, path, found := strings.Cut(pattern, " "); found {
+		return path
+	}
+	return pattern
+}
+
 // loggingResponseWriter wraps http.ResponseWriter to capture status code
+// and, when recording, a copy of the body
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	body       *cappedBuffer
 }
 
 func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+func (lrw *loggingResponseWriter) Write(p []byte) (int, error) {
+	if lrw.body != nil {
+		lrw.body.Write(p)
+	}
+	return lrw.ResponseWriter.Write(p)
+}
+
+// Flush passes flushes through so streaming responses keep working
+func (lrw *loggingResponseWriter) Flush() {
+	if flusher, ok := lrw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
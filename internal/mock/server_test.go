@@ -1,15 +1,24 @@
 package mock
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
 )
 
 func TestNewServer(t *testing.T) {
@@ -25,8 +34,8 @@ func TestNewServer(t *testing.T) {
 	if server == nil {
 		t.Fatal("Expected server to be created")
 	}
-	if server.schema != schema {
-		t.Error("Expected schema to be set")
+	if len(server.mounts) != 1 || server.mounts[0].Schema != schema || server.mounts[0].Prefix != "" {
+		t.Error("Expected schema to be mounted at the root")
 	}
 	if server.port != 8080 {
 		t.Errorf("Expected port 8080, got %d", server.port)
@@ -309,6 +318,1340 @@ func TestMethodNotAllowed(t *testing.T) {
 	}
 }
 
+// parseSpec writes an inline OpenAPI spec to a temp file and parses it
+func parseSpec(t *testing.T, spec string) *parser.Schema {
+	t.Helper()
+
+	specFile := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	schema, err := parser.NewOpenAPIParser().Parse(specFile)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	return schema
+}
+
+func TestVaryResponses(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Reports API
+  version: 1.0.0
+paths:
+  /reports/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Complete report
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [complete]
+                properties:
+                  complete:
+                    type: boolean
+        '206':
+          description: Partial report
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [partial]
+                properties:
+                  partial:
+                    type: boolean
+`)
+
+	server := NewServerWithOptions(schema, 8101, Options{VaryResponses: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	seen := make(map[int]bool)
+	for i := 0; i < 30; i++ {
+		resp, err := http.Get("http://localhost:8101/reports/1")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+
+		var body map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		seen[resp.StatusCode] = true
+		switch resp.StatusCode {
+		case http.StatusOK:
+			if _, ok := body["complete"]; !ok {
+				t.Errorf("Expected 200 body to have 'complete', got %v", body)
+			}
+		case http.StatusPartialContent:
+			if _, ok := body["partial"]; !ok {
+				t.Errorf("Expected 206 body to have 'partial', got %v", body)
+			}
+		default:
+			t.Errorf("Unexpected status %d", resp.StatusCode)
+		}
+	}
+
+	if !seen[http.StatusOK] || !seen[http.StatusPartialContent] {
+		t.Errorf("Expected both 200 and 206 responses, saw %v", seen)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              allOf:
+                - type: object
+                  required: [email]
+                  properties:
+                    email:
+                      type: string
+                - type: object
+                  required: [name]
+                  properties:
+                    name:
+                      type: string
+      responses:
+        '201':
+          description: Created
+`)
+
+	server := NewServerWithOptions(schema, 8102, Options{ValidateRequests: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "valid body", body: `{"email": "a@example.com", "name": "Ann"}`, expectedStatus: http.StatusCreated},
+		{name: "missing inherited field", body: `{"name": "Ann"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post("http://localhost:8102/users", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus != http.StatusBadRequest {
+				return
+			}
+
+			var body struct {
+				Errors []validator.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode error body: %v", err)
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Field != "email" {
+				t.Errorf("Expected a single error for field 'email', got %+v", body.Errors)
+			}
+		})
+	}
+}
+
+func TestConfiguredCookies(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Auth API",
+		Paths: map[string][]parser.Endpoint{
+			"/login": {
+				{Method: "POST", Path: "/login", Summary: "Log in"},
+			},
+		},
+	}
+
+	config := &Config{Endpoints: []EndpointConfig{{
+		Method:  "POST",
+		Path:    "/login",
+		Cookies: []CookieConfig{{Name: "session", Path: "/app", HttpOnly: true}},
+	}}}
+
+	// Hooks see the generated cookies along with the other response headers
+	var hookCookie string
+	hook := func(r *http.Request, resp *MockResponse) error {
+		hookCookie = resp.Headers.Get("Set-Cookie")
+		return nil
+	}
+
+	server := NewServerWithOptions(schema, 8103, Options{Config: config, ResponseHook: hook})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Post("http://localhost:8103/login", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	cookies := resp.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got %d", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != "session" || cookie.Value == "" {
+		t.Errorf("Expected generated session cookie, got %+v", cookie)
+	}
+	if cookie.Path != "/app" || !cookie.HttpOnly {
+		t.Errorf("Expected Path=/app and HttpOnly, got %+v", cookie)
+	}
+	if hookCookie != resp.Header.Get("Set-Cookie") {
+		t.Errorf("Expected the hook to see Set-Cookie %q, got %q", resp.Header.Get("Set-Cookie"), hookCookie)
+	}
+}
+
+func TestPerPathLatency(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Reports API",
+		Paths: map[string][]parser.Endpoint{
+			"/reports/{id}": {{Method: "GET", Path: "/reports/{id}"}},
+			"/status":       {{Method: "GET", Path: "/status"}},
+		},
+	}
+
+	config := &Config{Endpoints: []EndpointConfig{{Path: "/reports/*", Latency: 300 * time.Millisecond}}}
+	server := NewServerWithOptions(schema, 8104, Options{Config: config, Latency: 10 * time.Millisecond})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	timeRequest := func(path string) time.Duration {
+		start := time.Now()
+		resp, err := http.Get("http://localhost:8104" + path)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		return time.Since(start)
+	}
+
+	if elapsed := timeRequest("/reports/1"); elapsed < 300*time.Millisecond {
+		t.Errorf("Expected slow path to take at least 300ms, took %v", elapsed)
+	}
+	if elapsed := timeRequest("/status"); elapsed < 10*time.Millisecond || elapsed > 200*time.Millisecond {
+		t.Errorf("Expected fast path to use the 10ms global latency, took %v", elapsed)
+	}
+}
+
+func TestMinBodySize(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Test API",
+		Paths: map[string][]parser.Endpoint{
+			"/items/{id}": {{Method: "GET", Path: "/items/{id}"}},
+		},
+	}
+
+	server := NewServerWithOptions(schema, 8105, Options{MinBodySize: 4096})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8105/items/1")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response body: %v", err)
+	}
+	if len(body) < 4096 {
+		t.Errorf("Expected body of at least 4096 bytes, got %d", len(body))
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("Padded body is not valid JSON: %v", err)
+	}
+	if _, ok := response["id"]; !ok {
+		t.Error("Expected original fields to be preserved")
+	}
+}
+
+func TestOverlappingRoutes(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Items API",
+		Paths: map[string][]parser.Endpoint{
+			"/items/{id}":    {{Method: "GET", Path: "/items/{id}"}},
+			"/items/search":  {{Method: "POST", Path: "/items/search"}},
+			"/{kind}/latest": {{Method: "GET", Path: "/{kind}/latest"}},
+		},
+	}
+
+	// Ambiguous routes must not panic the mux
+	server := NewServer(schema, 8106)
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	// /items/search is served by the static route, which only allows POST
+	resp, err := http.Get("http://localhost:8106/items/search")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected static route to win with 405 for GET, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get("http://localhost:8106/items/42")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected wildcard route to serve /items/42, got %d", resp.StatusCode)
+	}
+}
+
+func TestRecording(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Test API",
+		Paths: map[string][]parser.Endpoint{
+			"/items/{id}": {{Method: "PUT", Path: "/items/{id}"}},
+		},
+	}
+
+	var record syncBuffer
+	server := NewServerWithOptions(schema, 8107, Options{Record: &record})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	req, _ := http.NewRequest("PUT", "http://localhost:8107/items/7?dry=1", strings.NewReader(`{"name":"alpha"}`))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// The entry is written after the response is sent, so give it a moment
+	for i := 0; i < 20 && record.String() == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	exchanges, err := ReadRecording(strings.NewReader(record.String()))
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+	if len(exchanges) != 1 {
+		t.Fatalf("Expected 1 recorded exchange, got %d", len(exchanges))
+	}
+
+	exchange := exchanges[0]
+	if exchange.Method != "PUT" || exchange.Path != "/items/7" || exchange.Query != "dry=1" {
+		t.Errorf("Unexpected request line in recording: %+v", exchange)
+	}
+	if exchange.Route != "/items/{id}" {
+		t.Errorf("Expected route '/items/{id}', got '%s'", exchange.Route)
+	}
+	if exchange.RequestBody != `{"name":"alpha"}` {
+		t.Errorf("Expected request body to be recorded, got '%s'", exchange.RequestBody)
+	}
+	if exchange.Status != http.StatusOK || exchange.ResponseBody != string(body) {
+		t.Errorf("Expected recorded response to match served response, got %d %q", exchange.Status, exchange.ResponseBody)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the server goroutine to write while the test reads
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestListItemsAreDistinct(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: Item list
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                    format: uuid
+                  name:
+                    type: string
+`)
+
+	server := NewServer(schema, 8108)
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8108/items")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data  []map[string]interface{} `json:"data"`
+		Total int                      `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Data) != 2 || response.Total != 2 {
+		t.Fatalf("Expected 2 items with total 2, got %d items, total %d", len(response.Data), response.Total)
+	}
+	if response.Data[0]["id"] == response.Data[1]["id"] {
+		t.Errorf("Expected distinct items, got duplicate id %v", response.Data[0]["id"])
+	}
+}
+
+func TestDeprecatedEndpoints(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Legacy API
+  version: 1.0.0
+paths:
+  /v1/users:
+    get:
+      deprecated: true
+      responses:
+        '200':
+          description: Users
+  /v2/users:
+    get:
+      responses:
+        '200':
+          description: Users
+`)
+
+	tests := []struct {
+		name           string
+		port           int
+		gone           bool
+		path           string
+		expectedStatus int
+		expectedHeader string
+	}{
+		{name: "deprecated mocks normally", port: 8109, path: "/v1/users", expectedStatus: http.StatusOK, expectedHeader: "true"},
+		{name: "deprecated gone", port: 8110, gone: true, path: "/v1/users", expectedStatus: http.StatusGone, expectedHeader: "true"},
+		{name: "current endpoint unaffected", port: 8110, gone: true, path: "/v2/users", expectedStatus: http.StatusOK},
+	}
+
+	servers := make(map[int]*Server)
+	defer func() {
+		for _, server := range servers {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			server.Stop(ctx)
+			cancel()
+		}
+	}()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if servers[tt.port] == nil {
+				servers[tt.port] = NewServerWithOptions(schema, tt.port, Options{DeprecatedGone: tt.gone})
+				go servers[tt.port].Start()
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", tt.port, tt.path))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Deprecation"); got != tt.expectedHeader {
+				t.Errorf("Expected Deprecation header %q, got %q", tt.expectedHeader, got)
+			}
+		})
+	}
+}
+
+func TestResponseTemplate(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Items API",
+		Paths: map[string][]parser.Endpoint{
+			"/items/{id}": {{Method: "GET", Path: "/items/{id}"}},
+		},
+	}
+
+	config := &Config{Endpoints: []EndpointConfig{{
+		Path:     "/items/{id}",
+		Template: `{"id": {{json .PathParams.id}}, "view": {{json .Query.view}}, "fetchedAt": "{{now}}", "name": {{json .Response.name}}}`,
+	}}}
+
+	server := NewServerWithOptions(schema, 8111, Options{Config: config})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8111/items/abc123?view=full")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response["id"] != "abc123" {
+		t.Errorf("Expected path id echoed, got %v", response["id"])
+	}
+	if response["view"] != "full" {
+		t.Errorf("Expected query param echoed, got %v", response["view"])
+	}
+	if _, err := time.Parse(time.RFC3339, fmt.Sprint(response["fetchedAt"])); err != nil {
+		t.Errorf("Expected RFC3339 timestamp, got %v", response["fetchedAt"])
+	}
+	if response["name"] != "Mock Resource" {
+		t.Errorf("Expected generated name to be available, got %v", response["name"])
+	}
+}
+
+func TestSpecExtensions(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Jobs API
+  version: 1.0.0
+paths:
+  /jobs:
+    post:
+      x-mocktail-status: 202
+      x-mocktail-latency: 150ms
+      x-mocktail-example:
+        id: job-1
+        state: queued
+      responses:
+        '202':
+          description: Accepted
+`)
+
+	server := NewServerWithOptions(schema, 8112, Options{})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	start := time.Now()
+	resp, err := http.Post("http://localhost:8112/jobs", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	elapsed := time.Since(start)
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected x-mocktail-status 202, got %d", resp.StatusCode)
+	}
+	if elapsed < 150*time.Millisecond {
+		t.Errorf("Expected x-mocktail-latency delay, request took %v", elapsed)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["id"] != "job-1" || response["state"] != "queued" {
+		t.Errorf("Expected x-mocktail-example body, got %v", response)
+	}
+}
+
+func TestResponseHook(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Items API",
+		Paths: map[string][]parser.Endpoint{
+			"/items/{id}": {{Method: "GET", Path: "/items/{id}"}},
+			"/secret":     {{Method: "GET", Path: "/secret"}},
+		},
+	}
+
+	// An example hook that signs bodies and refuses one path
+	hook := func(r *http.Request, resp *MockResponse) error {
+		if r.URL.Path == "/secret" {
+			return fmt.Errorf("access denied")
+		}
+		body, ok := resp.Body.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		body["signed"] = true
+		resp.Headers.Set("X-Signature", "abc123")
+		resp.Status = http.StatusAccepted
+		return nil
+	}
+
+	server := NewServerWithOptions(schema, 8113, Options{ResponseHook: hook})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name  string
+		path  string
+		check func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "hook rewrites response",
+			path: "/items/1",
+			check: func(t *testing.T, resp *http.Response) {
+				if resp.StatusCode != http.StatusAccepted {
+					t.Errorf("Expected hook status 202, got %d", resp.StatusCode)
+				}
+				if resp.Header.Get("X-Signature") != "abc123" {
+					t.Errorf("Expected hook header, got %q", resp.Header.Get("X-Signature"))
+				}
+				if resp.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Expected default headers to be kept, got %q", resp.Header.Get("Content-Type"))
+				}
+				var body map[string]interface{}
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if body["signed"] != true {
+					t.Errorf("Expected hook to modify body, got %v", body)
+				}
+			},
+		},
+		{
+			name: "hook error",
+			path: "/secret",
+			check: func(t *testing.T, resp *http.Response) {
+				if resp.StatusCode != http.StatusInternalServerError {
+					t.Errorf("Expected 500 from failing hook, got %d", resp.StatusCode)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get("http://localhost:8113" + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+			tt.check(t, resp)
+		})
+	}
+}
+
+func TestMountedSchemas(t *testing.T) {
+	rest := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+`)
+
+	sdlFile := filepath.Join(t.TempDir(), "gateway.graphql")
+	sdl := `
+enum Status { ACTIVE RETIRED }
+type Pet { id: ID! name: String status: Status }
+type Owner { name: String }
+union Match = Pet | Owner
+type Query { pets: [Pet!]! search: [Match] }
+`
+	if err := os.WriteFile(sdlFile, []byte(sdl), 0644); err != nil {
+		t.Fatalf("Failed to write SDL: %v", err)
+	}
+	p, err := parser.Detect(sdlFile, parser.ParseOptions{})
+	if err != nil {
+		t.Fatalf("Detect() failed: %v", err)
+	}
+	graph, err := p.Parse(sdlFile)
+	if err != nil {
+		t.Fatalf("Failed to parse SDL: %v", err)
+	}
+
+	server := NewServerWithMounts([]Mount{
+		{Prefix: "/rest", Schema: rest},
+		{Prefix: "/gateway", Schema: graph},
+	}, 8114, Options{})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name           string
+		request        func() (*http.Response, error)
+		expectedStatus int
+		check          func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name: "openapi mount",
+			request: func() (*http.Response, error) {
+				return http.Get("http://localhost:8114/rest/pets/7")
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if _, ok := body["name"]; !ok {
+					t.Errorf("Expected generated pet, got %v", body)
+				}
+			},
+		},
+		{
+			name: "graphql mount",
+			request: func() (*http.Response, error) {
+				query := `{"query": "{ pets { id kind: status __typename } }"}`
+				return http.Post("http://localhost:8114/gateway/graphql", "application/json", strings.NewReader(query))
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				data, _ := body["data"].(map[string]interface{})
+				pets, _ := data["pets"].([]interface{})
+				if len(pets) == 0 {
+					t.Fatalf("Expected pets list, got %v", body)
+				}
+				pet := pets[0].(map[string]interface{})
+				if pet["__typename"] != "Pet" {
+					t.Errorf("Expected __typename Pet, got %v", pet["__typename"])
+				}
+				if pet["kind"] != "ACTIVE" && pet["kind"] != "RETIRED" {
+					t.Errorf("Expected aliased enum value, got %v", pet["kind"])
+				}
+				if _, ok := pet["name"]; ok {
+					t.Error("Expected only selected fields")
+				}
+			},
+		},
+		{
+			name: "graphql union fragments",
+			request: func() (*http.Response, error) {
+				query := `{"query": "{ search { __typename ... on Pet { id } ... on Owner { name } } }"}`
+				return http.Post("http://localhost:8114/gateway/graphql", "application/json", strings.NewReader(query))
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if errs := body["errors"]; errs != nil {
+					t.Errorf("Expected no errors, got %v", errs)
+				}
+				data, _ := body["data"].(map[string]interface{})
+				matches, _ := data["search"].([]interface{})
+				if len(matches) == 0 {
+					t.Fatalf("Expected search results, got %v", body)
+				}
+				for _, match := range matches {
+					match := match.(map[string]interface{})
+					_, hasID := match["id"]
+					_, hasName := match["name"]
+					switch match["__typename"] {
+					case "Pet":
+						if !hasID || hasName {
+							t.Errorf("Expected only the Pet fragment's fields, got %v", match)
+						}
+					case "Owner":
+						if hasID || !hasName {
+							t.Errorf("Expected only the Owner fragment's fields, got %v", match)
+						}
+					default:
+						t.Errorf("Expected a Pet or an Owner, got %v", match)
+					}
+				}
+			},
+		},
+		{
+			name: "graphql unknown field",
+			request: func() (*http.Response, error) {
+				query := `{"query": "{ owners { id } }"}`
+				return http.Post("http://localhost:8114/gateway/graphql", "application/json", strings.NewReader(query))
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if errs, _ := body["errors"].([]interface{}); len(errs) != 1 {
+					t.Errorf("Expected one GraphQL error, got %v", body["errors"])
+				}
+			},
+		},
+		{
+			name: "graphql schema introspection",
+			request: func() (*http.Response, error) {
+				query := `{"query": "{ __schema { queryType { name } types { kind name } } }"}`
+				return http.Post("http://localhost:8114/gateway/graphql", "application/json", strings.NewReader(query))
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				data, _ := body["data"].(map[string]interface{})
+				introspection, _ := data["__schema"].(map[string]interface{})
+				if queryType, _ := introspection["queryType"].(map[string]interface{}); queryType["name"] != "Query" {
+					t.Errorf("Expected query type Query, got %v", introspection["queryType"])
+				}
+				kinds := make(map[string]interface{})
+				types, _ := introspection["types"].([]interface{})
+				for _, typ := range types {
+					typ := typ.(map[string]interface{})
+					kinds[typ["name"].(string)] = typ["kind"]
+				}
+				if kinds["Pet"] != "OBJECT" || kinds["Status"] != "ENUM" || kinds["String"] != "SCALAR" {
+					t.Errorf("Expected Pet, Status and built-in scalars with their kinds, got %v", kinds)
+				}
+			},
+		},
+		{
+			name: "graphql type introspection",
+			request: func() (*http.Response, error) {
+				query := `{"query": "query($t: String!) { pet: __type(name: $t) { name fields { name type { kind ofType { name } } } } }", "variables": {"t": "Pet"}}`
+				return http.Post("http://localhost:8114/gateway/graphql", "application/json", strings.NewReader(query))
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				data, _ := body["data"].(map[string]interface{})
+				pet, _ := data["pet"].(map[string]interface{})
+				fields, _ := pet["fields"].([]interface{})
+				if pet["name"] != "Pet" || len(fields) != 3 {
+					t.Fatalf("Expected Pet with 3 fields, got %v", pet)
+				}
+				id := fields[0].(map[string]interface{})
+				typ := id["type"].(map[string]interface{})
+				if id["name"] != "id" || typ["kind"] != "NON_NULL" || typ["ofType"].(map[string]interface{})["name"] != "ID" {
+					t.Errorf("Expected id: ID!, got %v", id)
+				}
+				if _, ok := id["args"]; ok {
+					t.Error("Expected only selected fields")
+				}
+			},
+		},
+		{
+			name: "health stays global",
+			request: func() (*http.Response, error) {
+				return http.Get("http://localhost:8114/health")
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.request()
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.check == nil {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestOptionsRequest(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Items API",
+		Paths: map[string][]parser.Endpoint{
+			"/items": {{Method: "GET", Path: "/items"}},
+			"/users": {{Method: "POST", Path: "/users"}, {Method: "GET", Path: "/users"}},
+		},
+	}
+
+	server := NewServer(schema, 8115)
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{name: "GET-only path", method: http.MethodOptions, path: "/items", expectedStatus: http.StatusNoContent, expectedAllow: "GET"},
+		{name: "multiple methods", method: http.MethodOptions, path: "/users", expectedStatus: http.StatusNoContent, expectedAllow: "GET, POST"},
+		{name: "405 lists allowed methods", method: http.MethodDelete, path: "/items", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "http://localhost:8115"+tt.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Allow"); got != tt.expectedAllow {
+				t.Errorf("Expected Allow %q, got %q", tt.expectedAllow, got)
+			}
+		})
+	}
+}
+
+func TestHeadRequest(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Items API",
+		Paths: map[string][]parser.Endpoint{
+			"/items/{id}": {{Method: "GET", Path: "/items/{id}"}},
+		},
+	}
+
+	server := NewServer(schema, 8116)
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Head("http://localhost:8116/items/1")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.ContentLength <= 0 {
+		t.Errorf("Expected Content-Length to be set, got %d", resp.ContentLength)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected GET's Content-Type, got %q", resp.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if len(body) != 0 {
+		t.Errorf("Expected empty body, got %q", body)
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Orders API
+  version: 1.0.0
+paths:
+  /orders/{status}/{page}:
+    parameters:
+      - name: status
+        in: path
+        required: true
+        schema:
+          type: string
+          enum: [open, closed]
+    get:
+      parameters:
+        - name: page
+          in: path
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+      responses:
+        '200':
+          description: OK
+`)
+
+	server := NewServerWithOptions(schema, 8117, Options{ValidateParams: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedField  string
+	}{
+		{name: "valid", path: "/orders/open/2", expectedStatus: http.StatusOK},
+		{name: "outside enum", path: "/orders/pending/2", expectedStatus: http.StatusBadRequest, expectedField: "status"},
+		{name: "not an integer", path: "/orders/closed/first", expectedStatus: http.StatusBadRequest, expectedField: "page"},
+		{name: "below minimum", path: "/orders/closed/0", expectedStatus: http.StatusBadRequest, expectedField: "page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get("http://localhost:8117" + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedField == "" {
+				return
+			}
+
+			var body struct {
+				Errors []validator.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode error body: %v", err)
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Field != tt.expectedField {
+				t.Errorf("Expected a single error for %q, got %+v", tt.expectedField, body.Errors)
+			}
+		})
+	}
+}
+
+func TestSpecEndpoint(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+`)
+
+	servers := map[int]*Server{
+		8118: NewServer(schema, 8118),
+		8119: NewServerWithOptions(schema, 8119, Options{DisableSpecEndpoint: true}),
+	}
+	for _, server := range servers {
+		go server.Start()
+	}
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		for _, server := range servers {
+			server.Stop(ctx)
+		}
+	}()
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "json", url: "http://localhost:8118/openapi.json", expectedStatus: http.StatusOK},
+		{name: "yaml", url: "http://localhost:8118/openapi.yaml", expectedStatus: http.StatusOK},
+		{name: "disabled", url: "http://localhost:8119/openapi.json", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(tt.url)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			// The served document must re-parse to the same API
+			data, _ := io.ReadAll(resp.Body)
+			file := filepath.Join(t.TempDir(), "served."+tt.name)
+			if err := os.WriteFile(file, data, 0644); err != nil {
+				t.Fatalf("Failed to write served spec: %v", err)
+			}
+			reparsed, err := parser.NewOpenAPIParser().Parse(file)
+			if err != nil {
+				t.Fatalf("Failed to re-parse served spec: %v\n%s", err, data)
+			}
+			if reparsed.Title != "Pets API" || len(reparsed.Paths["/pets"]) != 1 {
+				t.Errorf("Expected the original API back, got %q with paths %v", reparsed.Title, reparsed.Paths)
+			}
+		})
+	}
+}
+
+func TestDocsPage(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+`)
+
+	servers := map[int]*Server{
+		8120: NewServerWithOptions(schema, 8120, Options{Docs: true}),
+		8121: NewServer(schema, 8121),
+	}
+	for _, server := range servers {
+		go server.Start()
+	}
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		for _, server := range servers {
+			server.Stop(ctx)
+		}
+	}()
+
+	resp, err := http.Get("http://localhost:8120/docs")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("Expected HTML, got %q", resp.Header.Get("Content-Type"))
+	}
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(page), "openapi.json") {
+		t.Error("Expected the docs page to load openapi.json")
+	}
+
+	off, err := http.Get("http://localhost:8121/docs")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	off.Body.Close()
+	if off.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected docs to be off by default, got %d", off.StatusCode)
+	}
+}
+
+func TestCatchAllPath(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Files API
+  version: 1.0.0
+paths:
+  /files/{path+}:
+    get:
+      parameters:
+        - name: path
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+  /files/{id}/meta:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+`)
+
+	config := &Config{Endpoints: []EndpointConfig{
+		{Path: "/files/{path+}", Template: `{"route": "catch-all", "path": {{json .PathParams.path}}}`},
+		{Path: "/files/{id}/meta", Template: `{"route": "meta", "id": {{json .PathParams.id}}}`},
+	}}
+
+	server := NewServerWithOptions(schema, 8122, Options{Config: config})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		path     string
+		expected map[string]interface{}
+	}{
+		{path: "/files/a/b/c", expected: map[string]interface{}{"route": "catch-all", "path": "a/b/c"}},
+		{path: "/files/report.pdf", expected: map[string]interface{}{"route": "catch-all", "path": "report.pdf"}},
+		{path: "/files/42/meta", expected: map[string]interface{}{"route": "meta", "id": "42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get("http://localhost:8122" + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+			}
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			for key, want := range tt.expected {
+				if body[key] != want {
+					t.Errorf("Expected %s=%v, got %v", key, want, body[key])
+				}
+			}
+		})
+	}
+}
+
 // Helper function for string contains check
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) &&
@@ -322,3 +1665,1963 @@ func contains(s, substr string) bool {
 				return false
 			}()))
 }
+
+func TestFailOnUnknownPath(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Test API",
+		Paths: map[string][]parser.Endpoint{
+			"/test": {
+				{Method: "GET", Path: "/test", Summary: "Test endpoint"},
+			},
+		},
+	}
+
+	server := NewServerWithOptions(schema, 8100, Options{FailOnUnknownPath: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get("http://localhost:8100/missing")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode 404 response: %v", err)
+		}
+		if body["path"] != "/missing" || body["method"] != "GET" {
+			t.Errorf("Expected method and path in 404 body, got %v", body)
+		}
+	}
+
+	resp, err := http.Get("http://localhost:8100/__mocktail/unknown-paths")
+	if err != nil {
+		t.Fatalf("Failed to reach admin endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var report struct {
+		UnknownPaths []unknownPathHit `json:"unknownPaths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode admin response: %v", err)
+	}
+	if len(report.UnknownPaths) != 1 {
+		t.Fatalf("Expected 1 unknown path, got %d", len(report.UnknownPaths))
+	}
+	if hit := report.UnknownPaths[0]; hit.Path != "/missing" || hit.Count != 2 {
+		t.Errorf("Expected /missing hit twice, got %+v", hit)
+	}
+}
+
+func TestBinaryResponse(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Reports API
+  version: 1.0.0
+paths:
+  /reports/{id}/pdf:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/pdf:
+              schema:
+                type: string
+                format: binary
+  /avatar:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            image/png: {}
+`)
+
+	server := NewServerWithOptions(schema, 8123, Options{BinarySize: 256})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		path        string
+		contentType string
+		filename    string
+	}{
+		{path: "/reports/7/pdf", contentType: "application/pdf", filename: "pdf.pdf"},
+		{path: "/avatar", contentType: "image/png", filename: "avatar.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get("http://localhost:8123" + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			expected := fmt.Sprintf("attachment; filename=%q", tt.filename)
+			if got := resp.Header.Get("Content-Disposition"); got != expected {
+				t.Errorf("Expected Content-Disposition %s, got %s", expected, got)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if len(body) != 256 {
+				t.Errorf("Expected a 256 byte body, got %d bytes", len(body))
+			}
+		})
+	}
+}
+
+func TestTextResponse(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Status API
+  version: 1.0.0
+paths:
+  /version:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            text/plain:
+              schema:
+                type: string
+                enum: [v1.2.3]
+  /count:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            text/plain:
+              schema:
+                type: integer
+                minimum: 10
+                maximum: 10
+  /greeting:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            text/plain:
+              schema:
+                type: string
+              example: hello
+  /broken:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            text/plain:
+              schema:
+                type: string
+                enum: [a]
+                not:
+                  enum: [a]
+`)
+
+	server := NewServerWithOptions(schema, 8155, Options{PreferExamples: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/version", expected: "v1.2.3"},
+		{path: "/count", expected: "10"},
+		{path: "/greeting", expected: "hello"},
+		// Without --strict an unsatisfiable schema gets a placeholder, as JSON bodies do
+		{path: "/broken", expected: textPlaceholder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get("http://localhost:8155" + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+				t.Errorf("Expected Content-Type text/plain; charset=utf-8, got %s", got)
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("Expected raw body %q, got %q", tt.expected, body)
+			}
+		})
+	}
+
+	strict := NewServerWithOptions(schema, 8163, Options{Strict: true})
+	go strict.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		strict.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8163/broken")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected --strict to answer 500, got %d", resp.StatusCode)
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	schema := &parser.Schema{
+		Type:    "openapi",
+		Version: "3.0.0",
+		Title:   "Items API",
+		Paths: map[string][]parser.Endpoint{
+			"/items": {{Method: "GET", Path: "/items"}},
+		},
+	}
+	headers := http.Header{}
+	headers.Set("X-Env", "mock")
+	headers.Add("X-Frame-Options", "DENY")
+
+	server := NewServerWithOptions(schema, 8157, Options{ResponseHeaders: headers})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		method         string
+		expectedStatus int
+	}{
+		{method: http.MethodGet, expectedStatus: http.StatusOK},
+		{method: http.MethodDelete, expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "http://localhost:8157/items", nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get("X-Env"); got != "mock" {
+				t.Errorf("Expected X-Env: mock, got %q", got)
+			}
+			if got := resp.Header.Get("X-Frame-Options"); got != "DENY" {
+				t.Errorf("Expected X-Frame-Options: DENY, got %q", got)
+			}
+		})
+	}
+}
+
+func TestStatefulResources(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items:
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+  /items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+    put:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+    delete:
+      responses:
+        '204':
+          description: Deleted
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        id:
+          type: string
+          format: uuid
+        name:
+          type: string
+        createdAt:
+          type: string
+          format: date-time
+`)
+
+	server := NewServerWithOptions(schema, 8124, Options{Stateful: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	do := func(method, path, body string) (int, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(method, "http://localhost:8124"+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read body: %v", err)
+		}
+		return resp.StatusCode, data
+	}
+
+	// Create an item and read it back twice
+	status, created := do(http.MethodPost, "/items", `{"name": "widget"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", status)
+	}
+	var item map[string]interface{}
+	if err := json.Unmarshal(created, &item); err != nil {
+		t.Fatalf("Failed to decode created item: %v", err)
+	}
+	if item["name"] != "widget" {
+		t.Errorf("Expected created item to keep the request's name, got %v", item["name"])
+	}
+	id := item["id"].(string)
+
+	_, first := do(http.MethodGet, "/items/"+id, "")
+	_, second := do(http.MethodGet, "/items/"+id, "")
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected identical reads, got %s and %s", first, second)
+	}
+	if !bytes.Equal(first, created) {
+		t.Errorf("Expected read to return the created item %s, got %s", created, first)
+	}
+
+	// Unknown ids are stable too
+	_, unknown1 := do(http.MethodGet, "/items/other", "")
+	_, unknown2 := do(http.MethodGet, "/items/other", "")
+	if !bytes.Equal(unknown1, unknown2) {
+		t.Errorf("Expected identical reads of an unknown id, got %s and %s", unknown1, unknown2)
+	}
+
+	// Updates change what later reads return
+	do(http.MethodPut, "/items/"+id, `{"name": "gadget"}`)
+	_, updated := do(http.MethodGet, "/items/"+id, "")
+	if err := json.Unmarshal(updated, &item); err != nil {
+		t.Fatalf("Failed to decode updated item: %v", err)
+	}
+	if item["name"] != "gadget" || item["id"] != id {
+		t.Errorf("Expected updated item with the same id, got %v", item)
+	}
+
+	// Deleted items are gone
+	do(http.MethodDelete, "/items/"+id, "")
+	if status, _ := do(http.MethodGet, "/items/"+id, ""); status != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", status)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Upload API
+  version: 1.0.0
+paths:
+  /notes:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        '201':
+          description: Created
+`)
+
+	server := NewServerWithOptions(schema, 8125, Options{MaxBodySize: 64, ValidateRequests: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{name: "within limit", body: `{"text": "short"}`, expected: http.StatusCreated},
+		{name: "oversized", body: `{"text": "` + strings.Repeat("x", 100) + `"}`, expected: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post("http://localhost:8125/notes", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestMediaTypeExample(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                  name:
+                    type: string
+              example:
+                id: 7
+                name: Ada Lovelace
+`)
+
+	tests := []struct {
+		name           string
+		port           int
+		preferExamples bool
+		expectExample  bool
+	}{
+		{name: "example preferred", port: 8126, preferExamples: true, expectExample: true},
+		{name: "example ignored", port: 8127, preferExamples: false, expectExample: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServerWithOptions(schema, tt.port, Options{PreferExamples: tt.preferExamples})
+			go server.Start()
+			time.Sleep(100 * time.Millisecond)
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+				server.Stop(ctx)
+			}()
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/users", tt.port))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			isExample := body["name"] == "Ada Lovelace" && body["id"] == float64(7)
+			if isExample != tt.expectExample {
+				t.Errorf("Expected example=%v, got %v", tt.expectExample, body)
+			}
+		})
+	}
+}
+
+func TestListSize(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Catalog API
+  version: 1.0.0
+paths:
+  /products:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+  /tags:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                maxItems: 3
+                items:
+                  type: string
+`)
+
+	tests := []struct {
+		name string
+		port int
+		size int
+	}{
+		{name: "empty", port: 8128, size: 0},
+		{name: "large", port: 8129, size: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServerWithOptions(schema, tt.port, Options{ListSize: &ListSize{Min: tt.size, Max: tt.size}})
+			go server.Start()
+			time.Sleep(100 * time.Millisecond)
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+				server.Stop(ctx)
+			}()
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/products", tt.port))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var envelope struct {
+				Data  []interface{} `json:"data"`
+				Total int           `json:"total"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(envelope.Data) != tt.size || envelope.Total != tt.size {
+				t.Errorf("Expected %d items and total, got %d items and total %d", tt.size, len(envelope.Data), envelope.Total)
+			}
+
+			// Bare array responses follow the list size over the schema's maxItems
+			resp, err = http.Get(fmt.Sprintf("http://localhost:%d/tags", tt.port))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var tags []interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(tags) != tt.size {
+				t.Errorf("Expected %d tags, got %d", tt.size, len(tags))
+			}
+		})
+	}
+}
+
+func TestProxyHeaders(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"path":          r.URL.Path,
+			"authorization": r.Header.Get("Authorization"),
+		})
+	}))
+	defer upstream.Close()
+
+	target, _ := url.Parse(upstream.URL)
+	headers, err := ParseHeaders([]string{"Authorization: Bearer secret-token"})
+	if err != nil {
+		t.Fatalf("ParseHeaders() failed: %v", err)
+	}
+
+	var record syncBuffer
+	schema := &parser.Schema{Type: "openapi", Version: "3.0.0", Title: "Staging API", Paths: map[string][]parser.Endpoint{}}
+	server := NewServerWithOptions(schema, 8130, Options{Proxy: target, ProxyHeaders: headers, Record: &record})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8130/orders/42", nil)
+	req.Header.Set("Authorization", "Bearer client-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var echoed map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&echoed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if echoed["path"] != "/orders/42" {
+		t.Errorf("Expected upstream path /orders/42, got %s", echoed["path"])
+	}
+	if echoed["authorization"] != "Bearer secret-token" {
+		t.Errorf("Expected injected Authorization header, got %q", echoed["authorization"])
+	}
+
+	for i := 0; i < 20 && record.String() == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	exchanges, err := ReadRecording(strings.NewReader(record.String()))
+	if err != nil || len(exchanges) != 1 {
+		t.Fatalf("Expected 1 recorded exchange, got %d (%v)", len(exchanges), err)
+	}
+	if got := exchanges[0].RequestHeaders["Authorization"]; got != redacted {
+		t.Errorf("Expected configured header to be redacted from the recording, got %q", got)
+	}
+}
+
+func TestProxyInsecure(t *testing.T) {
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	tests := []struct {
+		name     string
+		port     int
+		insecure bool
+		expected int
+	}{
+		{name: "self-signed certificate rejected", port: 8145, expected: http.StatusBadGateway},
+		{name: "verification skipped", port: 8146, insecure: true, expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &parser.Schema{Type: "openapi", Version: "3.0.0", Title: "Staging API", Paths: map[string][]parser.Endpoint{}}
+			server := NewServerWithOptions(schema, tt.port, Options{Proxy: target, ProxyInsecure: tt.insecure})
+			go server.Start()
+			time.Sleep(100 * time.Millisecond)
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+				server.Stop(ctx)
+			}()
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/status", tt.port))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders([]string{"Authorization: Bearer x", "x-api-key:abc"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if headers.Get("Authorization") != "Bearer x" || headers.Get("X-Api-Key") != "abc" {
+		t.Errorf("Unexpected headers: %v", headers)
+	}
+
+	if _, err := ParseHeaders([]string{"no separator"}); err == nil {
+		t.Error("Expected error for a header without a colon")
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Feed API
+  version: 1.0.0
+paths:
+  /posts:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  items:
+                    type: array
+                    items:
+                      type: object
+                      properties:
+                        title:
+                          type: string
+                  pageInfo:
+                    type: object
+                    properties:
+                      endCursor:
+                        type: string
+                      hasNextPage:
+                        type: boolean
+`)
+
+	server := NewServerWithOptions(schema, 8131, Options{Stateful: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	type page struct {
+		Items    []map[string]interface{} `json:"items"`
+		PageInfo struct {
+			EndCursor   string `json:"endCursor"`
+			HasNextPage bool   `json:"hasNextPage"`
+		} `json:"pageInfo"`
+	}
+	fetch := func(query string) page {
+		t.Helper()
+		resp, err := http.Get("http://localhost:8131/posts" + query)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var p page
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return p
+	}
+
+	first := fetch("")
+	if first.PageInfo.EndCursor == "" || !first.PageInfo.HasNextPage {
+		t.Fatalf("Expected a cursor and more pages, got %+v", first.PageInfo)
+	}
+	if got := decodeCursor(first.PageInfo.EndCursor); got != len(first.Items) {
+		t.Errorf("Expected first cursor at offset %d, got %d", len(first.Items), got)
+	}
+
+	second := fetch("?cursor=" + first.PageInfo.EndCursor)
+	if second.PageInfo.EndCursor == first.PageInfo.EndCursor {
+		t.Fatalf("Expected the cursor to advance past %s", first.PageInfo.EndCursor)
+	}
+	if got, want := decodeCursor(second.PageInfo.EndCursor), len(first.Items)+len(second.Items); got != want {
+		t.Errorf("Expected second cursor at offset %d, got %d", want, got)
+	}
+}
+
+func TestPaginateEnds(t *testing.T) {
+	server := NewServerWithOptions(&parser.Schema{Type: "openapi", Paths: map[string][]parser.Endpoint{}}, 0, Options{ListSize: &ListSize{}})
+	rnd := server.seededRandom("GET /posts")
+	request := httptest.NewRequest(http.MethodGet, "/posts?cursor="+encodeCursor(0), nil)
+
+	// Both cursor fields are candidates; the sorted first one is always picked
+	for i := 0; i < 20; i++ {
+		_, cursorKey, hasMoreKey := cursorMetadata(map[string]interface{}{"nextCursor": "", "endCursor": "", "hasNextPage": true, "hasMore": true})
+		if cursorKey != "endCursor" || hasMoreKey != "hasMore" {
+			t.Fatalf("Expected endCursor and hasMore, got %s and %s", cursorKey, hasMoreKey)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		items  []interface{}
+		stored bool
+	}{
+		{name: "stored with list size 0", items: []interface{}{"a", "b"}, stored: true},
+		{name: "empty generated page", items: []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := map[string]interface{}{"items": tt.items, "nextCursor": "", "hasMore": true}
+			server.paginate(rnd, request, page, tt.stored)
+			if page["hasMore"] != false || page["nextCursor"] != nil {
+				t.Errorf("Expected an empty page to end the collection, got %v", page)
+			}
+		})
+	}
+}
+
+func TestStrictContentType(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        '201':
+          description: Created
+`)
+
+	server := NewServerWithOptions(schema, 8132, Options{StrictContentType: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	tests := []struct {
+		name        string
+		contentType string
+		expected    int
+	}{
+		{name: "declared type", contentType: "application/json", expected: http.StatusCreated},
+		{name: "declared type with charset", contentType: "application/json; charset=utf-8", expected: http.StatusCreated},
+		{name: "JSON sent as text", contentType: "text/plain", expected: http.StatusUnsupportedMediaType},
+		{name: "missing content type", contentType: "", expected: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://localhost:8132/items", strings.NewReader(`{"name":"x"}`))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestSeedDerivation(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Shop API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Record'
+  /orders:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Record'
+components:
+  schemas:
+    Record:
+      type: object
+      properties:
+        id:
+          type: string
+          format: uuid
+        name:
+          type: string
+`)
+
+	server := NewServerWithOptions(schema, 8133, Options{Seed: int64Ptr(42)})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := http.Get("http://localhost:8133" + path)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return string(body)
+	}
+
+	users, orders := get("/users"), get("/orders")
+	if again := get("/users"); again != users {
+		t.Errorf("Expected the same request to return the same data\nfirst:  %s\nsecond: %s", users, again)
+	}
+	if again := get("/orders"); again != orders {
+		t.Errorf("Expected the same request to return the same data\nfirst:  %s\nsecond: %s", orders, again)
+	}
+	if users == orders {
+		t.Errorf("Expected different endpoints to return different data, both got %s", users)
+	}
+}
+
+func TestRequestCountsAreBounded(t *testing.T) {
+	server := NewServerWithOptions(&parser.Schema{Type: "openapi", Paths: map[string][]parser.Endpoint{}}, 0, Options{Stateful: true})
+
+	for i := 0; i < maxRequestCounts+100; i++ {
+		server.randomForKey(fmt.Sprintf("GET /items/%d", i))
+	}
+	if len(server.requestCounts) > maxRequestCounts {
+		t.Errorf("Expected at most %d counted paths, got %d", maxRequestCounts, len(server.requestCounts))
+	}
+
+	// Paths still counted keep advancing
+	last := fmt.Sprintf("GET /items/%d", maxRequestCounts+99)
+	server.randomForKey(last)
+	if server.requestCounts[last] != 2 {
+		t.Errorf("Expected the latest path to have 2 requests, got %d", server.requestCounts[last])
+	}
+}
+
+func TestUnknownPathsAreBounded(t *testing.T) {
+	server := NewServerWithOptions(&parser.Schema{Type: "openapi", Paths: map[string][]parser.Endpoint{}}, 0, Options{FailOnUnknownPath: true})
+
+	for i := 0; i < maxRequestCounts+100; i++ {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/random/%d", i), nil)
+		server.handleUnknownPath(httptest.NewRecorder(), r)
+	}
+	if len(server.unknownPaths) > maxRequestCounts {
+		t.Errorf("Expected at most %d unknown paths, got %d", maxRequestCounts, len(server.unknownPaths))
+	}
+}
+
+func TestSeedFromRequestHeader(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Payments API
+  version: 1.0.0
+paths:
+  /payments:
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                    format: uuid
+                  amount:
+                    type: integer
+`)
+
+	// Stateful mode advances the seed on every call, so only the key can repeat a response
+	server := NewServerWithOptions(schema, 8147, Options{Seed: int64Ptr(42), Stateful: true, SeedHeader: "Idempotency-Key"})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	post := func(key string) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8147/payments", nil)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return string(body)
+	}
+
+	first := post("key-1")
+	if retry := post("key-1"); retry != first {
+		t.Errorf("Expected a retry with the same key to get the same response\nfirst: %s\nretry: %s", first, retry)
+	}
+	if other := post("key-2"); other == first {
+		t.Errorf("Expected a different key to get a different response, both got %s", first)
+	}
+	if post("") == post("") {
+		t.Error("Expected requests without a key to keep advancing in stateful mode")
+	}
+}
+
+func TestStatefulIdempotentCreate(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Orders API
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  total:
+                    type: integer
+`)
+
+	server := NewServerWithOptions(schema, 8162, Options{Seed: int64Ptr(42), Stateful: true, SeedHeader: "Idempotency-Key"})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	do := func(method, key string) string {
+		t.Helper()
+		req, _ := http.NewRequest(method, "http://localhost:8162/orders", nil)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return string(body)
+	}
+
+	// The schema declares no id, so each create is numbered by the store; a retry
+	// must get the first order back rather than a second one
+	first := do(http.MethodPost, "key-1")
+	if retry := do(http.MethodPost, "key-1"); retry != first {
+		t.Errorf("Expected the retry to replay the created order\nfirst: %s\nretry: %s", first, retry)
+	}
+
+	var orders []interface{}
+	if err := json.Unmarshal([]byte(do(http.MethodGet, "")), &orders); err != nil {
+		t.Fatalf("Failed to decode list: %v", err)
+	}
+	if len(orders) != 1 {
+		t.Errorf("Expected 1 stored order after a retried create, got %d: %v", len(orders), orders)
+	}
+}
+
+func TestItemResponsesFollowID(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                  sku:
+                    type: string
+                    format: uuid
+`)
+
+	server := NewServer(schema, 8134)
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	get := func(id string) map[string]interface{} {
+		t.Helper()
+		resp, err := http.Get("http://localhost:8134/items/" + id)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var item map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return item
+	}
+
+	seven, eight := get("7"), get("8")
+	if seven["id"] != float64(7) || eight["id"] != float64(8) {
+		t.Errorf("Expected the requested ids echoed as numbers, got %v and %v", seven["id"], eight["id"])
+	}
+	if again := get("7"); !reflect.DeepEqual(again, seven) {
+		t.Errorf("Expected the same id to return the same item, got %v then %v", seven, again)
+	}
+	if seven["sku"] == eight["sku"] {
+		t.Errorf("Expected different ids to return different items, both have sku %v", seven["sku"])
+	}
+}
+
+func TestCacheHeaders(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  updatedAt:
+                    type: string
+                    format: date-time
+`)
+
+	server := NewServerWithOptions(schema, 8135, Options{CacheHeaders: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	request := func(method, ifNoneMatch string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, "http://localhost:8135/items/1", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp
+	}
+	get := func(ifNoneMatch string) *http.Response {
+		t.Helper()
+		return request(http.MethodGet, ifNoneMatch)
+	}
+
+	first := get("")
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("Expected 200 with an ETag, got %d and %q", first.StatusCode, etag)
+	}
+	if first.Header.Get("Cache-Control") == "" {
+		t.Error("Expected a Cache-Control header")
+	}
+
+	if second := get(etag); second.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected 304 for a matching If-None-Match, got %d", second.StatusCode)
+	} else if second.Header.Get("ETag") != etag {
+		t.Errorf("Expected the 304 to repeat ETag %s, got %s", etag, second.Header.Get("ETag"))
+	}
+
+	if stale := get(`"stale"`); stale.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 for a stale If-None-Match, got %d", stale.StatusCode)
+	}
+
+	if head := request(http.MethodHead, ""); head.Header.Get("ETag") != etag {
+		t.Errorf("Expected HEAD to carry GET's ETag %s, got %s", etag, head.Header.Get("ETag"))
+	}
+
+	// Generated timestamps count back from the server's clock, not the request time
+	time.Sleep(1100 * time.Millisecond)
+	if later := get(""); later.Header.Get("ETag") != etag {
+		t.Errorf("Expected the ETag to stay %s a second later, got %s", etag, later.Header.Get("ETag"))
+	}
+}
+
+func TestStoreServesCopies(t *testing.T) {
+	c := newStore().collection("/items")
+	c.put("1", map[string]interface{}{
+		"name":  "widget",
+		"owner": map[string]interface{}{"name": "ada"},
+	})
+
+	// Whatever edits a served list or item, such as a response hook, must not reach the store
+	listed := c.list()[0].(map[string]interface{})
+	listed["name"] = "edited"
+	listed["owner"].(map[string]interface{})["name"] = "edited"
+	read := copyItem(c.items["1"])
+	read["owner"].(map[string]interface{})["name"] = "edited"
+
+	stored := c.items["1"]
+	if stored["name"] != "widget" || stored["owner"].(map[string]interface{})["name"] != "ada" {
+		t.Errorf("Expected the stored item untouched, got %v", stored)
+	}
+}
+
+func TestStatefulExamples(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Things API
+  version: 1.0.0
+paths:
+  /things:
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+              example:
+                name: example
+  /things/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+              example:
+                id: "0"
+                name: example
+`)
+
+	server := NewServerWithOptions(schema, 8160, Options{Stateful: true, PreferExamples: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	do := func(method, path, body string) map[string]interface{} {
+		t.Helper()
+		req, err := http.NewRequest(method, "http://localhost:8160"+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return result
+	}
+
+	// Each POST starts from a fresh copy of the example
+	first := do(http.MethodPost, "/things", `{"color": "red"}`)
+	second := do(http.MethodPost, "/things", `{"size": "L"}`)
+	if first["id"] == second["id"] {
+		t.Errorf("Expected distinct ids, got %v twice", first["id"])
+	}
+	if _, leaked := second["color"]; leaked {
+		t.Errorf("Expected the first request's fields to stay out of the second, got %v", second)
+	}
+
+	// Reading one id must not rewrite the example another id was served from
+	do(http.MethodGet, "/things/7", "")
+	do(http.MethodGet, "/things/8", "")
+	if got := do(http.MethodGet, "/things/7", ""); got["id"] != "7" {
+		t.Errorf("Expected /things/7 to keep id 7, got %v", got["id"])
+	}
+}
+
+func TestStatefulConcurrency(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Items API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Item'
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+  /items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+    patch:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+    delete:
+      responses:
+        '204':
+          description: Deleted
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+`)
+
+	server := NewServerWithOptions(schema, 8136, Options{Stateful: true, MinBodySize: 256})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	do := func(method, path, body string) {
+		req, err := http.NewRequest(method, "http://localhost:8136"+path, strings.NewReader(body))
+		if err != nil {
+			t.Errorf("Failed to create request: %v", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("Failed to make request: %v", err)
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	// Hammer create, read, update, and delete on overlapping ids
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("item-%d", i%4)
+			do(http.MethodPost, "/items", fmt.Sprintf(`{"id":%q}`, id))
+			do(http.MethodGet, "/items", "")
+			do(http.MethodGet, "/items/"+id, "")
+			do(http.MethodPatch, "/items/"+id, `{"name":"renamed"}`)
+			do(http.MethodDelete, "/items/"+id, "")
+		}(i)
+	}
+	wg.Wait()
+
+	// Concurrent PATCHes of one item must all land
+	do(http.MethodPost, "/items", `{"id":"shared"}`)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			do(http.MethodPatch, "/items/shared", fmt.Sprintf(`{"field%d":true}`, i))
+		}(i)
+	}
+	wg.Wait()
+
+	resp, err := http.Get("http://localhost:8136/items/shared")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	var item map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	for i := 0; i < workers; i++ {
+		if item[fmt.Sprintf("field%d", i)] != true {
+			t.Errorf("Expected field%d from a concurrent PATCH to be kept, got %v", i, item)
+		}
+	}
+}
+
+func TestPatchMediaTypes(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    patch:
+      requestBody:
+        required: true
+        content:
+          application/merge-patch+json:
+            schema:
+              $ref: '#/components/schemas/User'
+          application/json-patch+json:
+            schema:
+              type: array
+              items:
+                type: object
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name, email]
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+        email:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string
+        address:
+          type: object
+          properties:
+            city:
+              type: string
+            zip:
+              type: string
+`)
+
+	server := NewServerWithOptions(schema, 8141, Options{Stateful: true, ValidateRequests: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	do := func(method, contentType, body string) (int, map[string]interface{}) {
+		t.Helper()
+		req, err := http.NewRequest(method, "http://localhost:8141/users/u1", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp.StatusCode, result
+	}
+
+	_, original := do(http.MethodGet, "", "")
+
+	// A merge patch only needs the fields it changes; null removes a field and nested
+	// objects merge
+	status, patched := do(http.MethodPatch, "application/merge-patch+json",
+		`{"name": "Ann", "email": null, "address": {"city": "Oslo"}}`)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200 for a merge patch, got %d: %v", status, patched)
+	}
+	if _, ok := patched["email"]; ok || patched["name"] != "Ann" {
+		t.Errorf("Expected name set and email removed, got %v", patched)
+	}
+	address := patched["address"].(map[string]interface{})
+	if address["city"] != "Oslo" || address["zip"] != original["address"].(map[string]interface{})["zip"] {
+		t.Errorf("Expected city replaced and zip kept, got %v", address)
+	}
+
+	// A JSON Patch applies its operations in order
+	status, patched = do(http.MethodPatch, "application/json-patch+json",
+		`[{"op": "test", "path": "/name", "value": "Ann"}, {"op": "add", "path": "/tags/0", "value": "vip"}, {"op": "copy", "from": "/name", "path": "/email"}]`)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200 for a JSON Patch, got %d: %v", status, patched)
+	}
+	if tags := patched["tags"].([]interface{}); tags[0] != "vip" || patched["email"] != "Ann" {
+		t.Errorf("Expected tag inserted and name copied to email, got %v", patched)
+	}
+	if _, stored := do(http.MethodGet, "", ""); !reflect.DeepEqual(stored, patched) {
+		t.Errorf("Expected later reads to return the patched user %v, got %v", patched, stored)
+	}
+
+	// A failed test leaves the resource untouched
+	if status, _ := do(http.MethodPatch, "application/json-patch+json", `[{"op": "test", "path": "/name", "value": "Bob"}]`); status != http.StatusConflict {
+		t.Errorf("Expected status 409 for a failed test, got %d", status)
+	}
+	if status, _ := do(http.MethodPatch, "application/json-patch+json", `[{"op": "remove", "path": "/missing"}]`); status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for a missing path, got %d", status)
+	}
+
+	// Malformed patches fail validation
+	if status, _ := do(http.MethodPatch, "application/json-patch+json", `[{"op": "add", "path": "name"}]`); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid JSON Patch, got %d", status)
+	}
+	if status, _ := do(http.MethodPatch, "application/merge-patch+json", `{"tags": "vip"}`); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a mistyped merge patch, got %d", status)
+	}
+}
+
+func TestStrictGenerationErrors(t *testing.T) {
+	// No boolean satisfies this response schema, so generation always fails
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Flags API
+  version: 1.0.0
+paths:
+  /flag:
+    get:
+      responses:
+        '200':
+          description: A flag
+          content:
+            application/json:
+              schema:
+                type: boolean
+                not:
+                  type: boolean
+`)
+
+	tests := []struct {
+		name     string
+		port     int
+		strict   bool
+		expected int
+	}{
+		{name: "placeholder without strict", port: 8143, expected: http.StatusOK},
+		{name: "500 with strict", port: 8144, strict: true, expected: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServerWithOptions(schema, tt.port, Options{Strict: tt.strict})
+			go server.Start()
+			time.Sleep(100 * time.Millisecond)
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+				server.Stop(ctx)
+			}()
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/flag", tt.port))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expected {
+				t.Fatalf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+			if !tt.strict {
+				return
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body["error"] != "response generation failed" || !strings.Contains(fmt.Sprint(body["details"]), "GET /flag") {
+				t.Errorf("Expected the generation error in the body, got %v", body)
+			}
+		})
+	}
+}
+
+func TestDefaultResponseFallback(t *testing.T) {
+	// The operation declares no explicit status, only a default error response
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Outage API
+  version: 1.0.0
+paths:
+  /services/{id}:
+    get:
+      x-mocktail-status: 503
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        default:
+          description: Unexpected error
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [code, message]
+                properties:
+                  code:
+                    type: integer
+                  message:
+                    type: string
+`)
+
+	server := NewServerWithOptions(schema, 8148, Options{Strict: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8148/services/db")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", resp.StatusCode)
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if _, ok := body["code"].(float64); !ok {
+		t.Errorf("Expected a body generated from the default response, got %v", body)
+	}
+	if _, ok := body["message"].(string); !ok {
+		t.Errorf("Expected a body generated from the default response, got %v", body)
+	}
+}
+
+func TestSeedOverrideParam(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Orders API
+  version: 1.0.0
+paths:
+  /orders/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  total:
+                    type: integer
+                  note:
+                    type: string
+`)
+
+	// The first server picks its base seed from the clock; the others run with --seed 123
+	// and --seed 0, which is a seed like any other
+	server := NewServerWithOptions(schema, 8149, Options{SeedParam: "__seed"})
+	seeded := NewServerWithOptions(schema, 8150, Options{Seed: int64Ptr(123)})
+	zero := NewServerWithOptions(schema, 8164, Options{Seed: int64Ptr(0)})
+	for _, s := range []*Server{server, seeded, zero} {
+		go s.Start()
+		defer func(s *Server) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			s.Stop(ctx)
+		}(s)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	get := func(url string) string {
+		t.Helper()
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return string(body)
+	}
+
+	first := get("http://localhost:8149/orders/7?__seed=123")
+	if again := get("http://localhost:8149/orders/7?__seed=123"); again != first {
+		t.Errorf("Expected the same __seed to reproduce the response\nfirst: %s\nagain: %s", first, again)
+	}
+	if other := get("http://localhost:8149/orders/7?__seed=124"); other == first {
+		t.Errorf("Expected a different __seed to get a different response, both got %s", first)
+	}
+	if base := get("http://localhost:8150/orders/7"); base != first {
+		t.Errorf("Expected __seed=123 to match a server run with --seed 123\nparam: %s\nseed:  %s", first, base)
+	}
+	if param, base := get("http://localhost:8149/orders/7?__seed=0"), get("http://localhost:8164/orders/7"); param != base {
+		t.Errorf("Expected __seed=0 to match a server run with --seed 0\nparam: %s\nseed:  %s", param, base)
+	}
+}
+
+func TestVendorJSONResponse(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Articles API
+  version: 1.0.0
+paths:
+  /articles/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: A JSON:API document
+          content:
+            application/vnd.api+json:
+              schema:
+                type: object
+                required: [data]
+                properties:
+                  data:
+                    type: object
+                    required: [type, id]
+                    properties:
+                      type:
+                        type: string
+                        enum: [articles]
+                      id:
+                        type: string
+`)
+
+	server := NewServerWithOptions(schema, 8154, Options{})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8154/articles/1")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "application/vnd.api+json" {
+		t.Errorf("Expected Content-Type application/vnd.api+json, got %q", got)
+	}
+	var body struct {
+		Data struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Data.Type != "articles" || body.Data.ID == "" {
+		t.Errorf("Expected a generated JSON:API document, got %+v", body)
+	}
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
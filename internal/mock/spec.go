@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// handleSpec serves the loaded OpenAPI document as JSON or YAML so tools such as
+// Swagger UI can point at the running mock. Split specs are served bundled, with
+// references to sibling files moved into components, since clients can't fetch them.
+func (s *Server) handleSpec(doc *openapi3.T, format string) http.HandlerFunc {
+	data, _, err := parser.Bundle(doc, false)
+	if err == nil && format == "yaml" {
+		data, err = parser.JSONToYAML(data)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err != nil {
+			s.logger.Errorf("Error encoding spec: %v", err)
+			http.Error(w, "failed to encode spec", http.StatusInternalServerError)
+			return
+		}
+
+		if format == "yaml" {
+			w.Header().Set("Content-Type", "application/yaml")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Header().Set("X-Mocktail-Server", "true")
+		w.Write(data)
+	}
+}
+
+//go:embed docs/index.html
+var docsFS embed.FS
+
+// handleDocs serves the interactive docs page, which loads the spec from ./openapi.json
+func handleDocs(w http.ResponseWriter, r *http.Request) {
+	page, err := docsFS.ReadFile("docs/index.html")
+	if err != nil {
+		http.Error(w, "docs unavailable", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Mocktail-Server", "true")
+	w.Write(page)
+}
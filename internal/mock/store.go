@@ -0,0 +1,326 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// store keeps resources written through the mock in stateful mode. Resources are
+// grouped by collection route, e.g. "/items" for POST /items and GET /items/{id}.
+//
+// Reads of stored resources share a read lock; anything that writes, including the
+// first read of an unknown id, holds the write lock for its whole read-modify-write,
+// so concurrent PUT/PATCH requests on one item apply one after another without lost
+// updates. Stored items are never modified in place: updates store a fresh copy and
+// responses get their own, so a served body is a consistent snapshot.
+type store struct {
+	mu          sync.RWMutex
+	collections map[string]*collection
+}
+
+// collection holds one resource type's items in creation order
+type collection struct {
+	ids     []string
+	items   map[string]map[string]interface{}
+	deleted map[string]bool
+	nextID  int
+	// created maps the idempotency key of each POST that sent one to the item it
+	// created, so a retry is answered with that item instead of creating another
+	created map[string]map[string]interface{}
+}
+
+// newStore creates an empty resource store
+func newStore() *store {
+	return &store{collections: make(map[string]*collection)}
+}
+
+// collection returns the named collection, creating it on first use; callers hold mu
+func (st *store) collection(name string) *collection {
+	c, ok := st.collections[name]
+	if !ok {
+		c = &collection{
+			items:   make(map[string]map[string]interface{}),
+			deleted: make(map[string]bool),
+			created: make(map[string]map[string]interface{}),
+		}
+		st.collections[name] = c
+	}
+	return c
+}
+
+// lookup returns the named collection, or nil if nothing was stored in it yet;
+// callers hold mu for reading
+func (st *store) lookup(name string) *collection {
+	return st.collections[name]
+}
+
+// put stores an item under id, keeping its original position when replaced
+func (c *collection) put(id string, item map[string]interface{}) {
+	if _, exists := c.items[id]; !exists {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = item
+	delete(c.deleted, id)
+}
+
+// remove deletes an item so later reads answer 404
+func (c *collection) remove(id string) {
+	if _, exists := c.items[id]; exists {
+		delete(c.items, id)
+		for i, existing := range c.ids {
+			if existing == id {
+				c.ids = append(c.ids[:i], c.ids[i+1:]...)
+				break
+			}
+		}
+	}
+	c.deleted[id] = true
+}
+
+// list returns copies of the stored items in creation order
+func (c *collection) list() []interface{} {
+	items := make([]interface{}, 0, len(c.ids))
+	for _, id := range c.ids {
+		items = append(items, copyItem(c.items[id]))
+	}
+	return items
+}
+
+// resourceRoute splits an item route such as /items/{id} into its collection route
+// and id parameter; ok is false for routes that don't end in a single-segment parameter
+func resourceRoute(pattern string) (collection, param string, ok bool) {
+	i := strings.LastIndex(pattern, "/")
+	last := pattern[i+1:]
+	if !isWildcard(last) || isCatchAll(last) {
+		return "", "", false
+	}
+	return pattern[:i], last[1 : len(last)-1], true
+}
+
+// statefulResponse answers a request from the resource store. Reads of unknown ids are
+// generated from a seed derived from the id, then kept, so repeated reads are identical;
+// writes merge the request body into the stored item, and PATCH applies merge patch
+// and JSON Patch bodies as their media types define. status is non-zero when it
+// overrides the chosen status, and ok is false when the request should be mocked as usual.
+func (s *Server) statefulResponse(rnd *requestRandom, r *http.Request, endpoint parser.Endpoint, operation *openapi3.Operation, statusKey string) (response interface{}, status int, ok bool) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if response, status, ok = s.storedRead(rnd, r, endpoint, operation, statusKey); ok {
+			return response, status, true
+		}
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	name, param, isItem := resourceRoute(r.Pattern)
+	if !isItem {
+		c := s.store.collection(r.Pattern)
+		switch r.Method {
+		case http.MethodPost:
+			var idempotencyKey string
+			if s.options.SeedHeader != "" {
+				idempotencyKey = r.Header.Get(s.options.SeedHeader)
+			}
+			if item, replay := c.created[idempotencyKey]; replay {
+				return copyItem(item), 0, true
+			}
+
+			generated, err := s.generateMockResponse(rnd, endpoint, operation, statusKey)
+			item, isObject := generated.(map[string]interface{})
+			if err != nil || !isObject {
+				return nil, 0, false
+			}
+			mergeRequestBody(item, r)
+			id, hasID := item["id"]
+			if !hasID {
+				c.nextID++
+				id = strconv.Itoa(c.nextID)
+				item["id"] = id
+			}
+			c.put(fmt.Sprint(id), item)
+			if idempotencyKey != "" {
+				c.created[idempotencyKey] = item
+			}
+			return copyItem(item), 0, true
+		case http.MethodGet, http.MethodHead:
+			if len(c.ids) == 0 {
+				return nil, 0, false
+			}
+			page, err := s.generateMockResponse(rnd, endpoint, operation, statusKey)
+			if err != nil {
+				return nil, 0, false
+			}
+			return withItems(page, c.list()), 0, true
+		}
+		return nil, 0, false
+	}
+
+	id := r.PathValue(param)
+	c := s.store.collection(name)
+	if c.deleted[id] && r.Method != http.MethodPut {
+		return map[string]interface{}{"error": "resource not found", "id": id}, http.StatusNotFound, true
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch:
+		item, exists := c.items[id]
+		if !exists {
+			generated, err := s.generatorFor(name, id).GenerateResponse(operation, statusKey)
+			if item, ok = generated.(map[string]interface{}); err != nil || !ok {
+				return nil, 0, false
+			}
+			setID(item, param, id)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			item = copyItem(item)
+			mergeRequestBody(item, r)
+			setID(item, param, id)
+		case http.MethodPatch:
+			patched, err := patchItem(item, r)
+			if err != nil {
+				status := http.StatusUnprocessableEntity
+				if errors.Is(err, errPatchTestFailed) {
+					status = http.StatusConflict
+				}
+				return map[string]interface{}{"error": "patch could not be applied", "details": err.Error()}, status, true
+			}
+			item = patched
+			setID(item, param, id)
+		}
+		c.put(id, item)
+		return copyItem(item), 0, true
+	case http.MethodDelete:
+		c.remove(id)
+	}
+	return nil, 0, false
+}
+
+// storedRead answers a GET of resources already in the store under the read lock, so
+// concurrent reads don't wait on each other; ok is false when the read has to
+// generate and store data first
+func (s *Server) storedRead(rnd *requestRandom, r *http.Request, endpoint parser.Endpoint, operation *openapi3.Operation, statusKey string) (response interface{}, status int, ok bool) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	name, param, isItem := resourceRoute(r.Pattern)
+	if !isItem {
+		c := s.store.lookup(r.Pattern)
+		if c == nil || len(c.ids) == 0 {
+			return nil, 0, false
+		}
+		page, err := s.generateMockResponse(rnd, endpoint, operation, statusKey)
+		if err != nil {
+			return nil, 0, false
+		}
+		return withItems(page, c.list()), 0, true
+	}
+
+	id := r.PathValue(param)
+	c := s.store.lookup(name)
+	if c == nil {
+		return nil, 0, false
+	}
+	if c.deleted[id] {
+		return map[string]interface{}{"error": "resource not found", "id": id}, http.StatusNotFound, true
+	}
+	item, exists := c.items[id]
+	if !exists {
+		return nil, 0, false
+	}
+	return copyItem(item), 0, true
+}
+
+// generatorFor returns a generator seeded by the server seed and a resource id, so an
+// id always starts out with the same data
+func (s *Server) generatorFor(collection, id string) *generator.Generator {
+	h := fnv.New64a()
+	h.Write([]byte(collection + "/" + id))
+	return generator.NewGeneratorWithOptions(s.seed^int64(h.Sum64()), s.generateOptions())
+}
+
+// mergeRequestBody copies the fields of a JSON object request body onto item,
+// restoring the body for later readers
+func mergeRequestBody(item map[string]interface{}, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]interface{}
+	if json.Unmarshal(body, &fields) != nil {
+		return
+	}
+	for key, value := range fields {
+		item[key] = value
+	}
+}
+
+// setID writes the path id into the item's id field (named like the path parameter, or
+// "id"), keeping numeric ids numeric
+func setID(item map[string]interface{}, param, id string) {
+	key := "id"
+	if _, ok := item[param]; ok {
+		key = param
+	}
+	var value interface{} = id
+	switch item[key].(type) {
+	case int64, float64:
+		if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+			value = n
+		}
+	}
+	item[key] = value
+}
+
+// echoID writes the id requested from an item route such as /items/{id} into a generated
+// object that has an id field, so GET /items/42 answers with id 42
+func echoID(r *http.Request, response interface{}) {
+	item, ok := response.(map[string]interface{})
+	_, param, isItem := resourceRoute(r.Pattern)
+	if !ok || !isItem {
+		return
+	}
+	_, hasParam := item[param]
+	_, hasID := item["id"]
+	if hasParam || hasID {
+		setID(item, param, r.PathValue(param))
+	}
+}
+
+// copyItem returns a deep copy, so neither updates nor whatever edits a served body
+// share nested objects with the stored item
+func copyItem(item map[string]interface{}) map[string]interface{} {
+	return deepCopy(item).(map[string]interface{})
+}
+
+// withItems swaps the stored items into a list response: a bare array, the
+// {"data": [...], "total": n} wrapper synthesized for list endpoints, or a
+// cursor-paginated page
+func withItems(response interface{}, items []interface{}) interface{} {
+	switch v := response.(type) {
+	case []interface{}:
+		return items
+	case map[string]interface{}:
+		if _, ok := v["data"].([]interface{}); ok {
+			return map[string]interface{}{"data": items, "total": len(items)}
+		}
+		if key := pageItemsKey(v); key != "" && isPage(v) {
+			v[key] = items
+		}
+	}
+	return response
+}
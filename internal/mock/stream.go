@@ -0,0 +1,96 @@
+package mock
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/Vooblin/mocktail/internal/validator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// streamItemSchema returns the item schema of a collection GET whose response is a
+// top-level array, or nil when the response shouldn't be streamed. ResponseHook and
+// CacheHeaders need the whole body before the headers go out, so they turn streaming off.
+func (s *Server) streamItemSchema(r *http.Request, endpoint parser.Endpoint, operation *openapi3.Operation, statusKey string) *openapi3.Schema {
+	if s.options.StreamLists <= 0 || r.Method != http.MethodGet || strings.Contains(endpoint.Path, "{") {
+		return nil
+	}
+	// Stored data, scripted bodies, examples, and templates need the whole response
+	if s.store != nil || endpoint.Example != nil || s.templateFor(endpoint) != "" {
+		return nil
+	}
+	if s.options.ResponseHook != nil || s.options.CacheHeaders {
+		return nil
+	}
+	if _, ok := generator.ResponseExample(operation, statusKey); ok && s.options.PreferExamples {
+		return nil
+	}
+	schema := validator.ResponseSchema(operation, statusKey)
+	if schema == nil || !schema.Type.Is("array") || schema.Items == nil || schema.Items.Value == nil {
+		return nil
+	}
+	return schema.Items.Value
+}
+
+// writeStreamedList answers with a JSON array of Options.StreamLists items, generating
+// and encoding each one as it is written so the list is never held in memory
+func (s *Server) writeStreamedList(rnd *requestRandom, w http.ResponseWriter, r *http.Request, endpoint parser.Endpoint, operation *openapi3.Operation, statusKey string, status int, itemSchema *openapi3.Schema) {
+	// The body size isn't known up front, so only the base latency applies
+	if !s.sleep(r, s.delayFor(r, s.latencyFor(endpoint), 0)) {
+		return
+	}
+
+	contentType := generator.ResponseMediaType(operation, statusKey)
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Mocktail-Server", "true")
+	s.setCookies(rnd, w.Header(), endpoint)
+	w.WriteHeader(status)
+
+	gen := rnd.gen.WithContext(generator.ContextResponse)
+	err := streamArray(w, s.options.StreamLists, func() (interface{}, error) {
+		return gen.GenerateFromSchema(itemSchema)
+	})
+	if err != nil {
+		// The 200 is already sent, so abort the connection rather than let a truncated
+		// list pass for a complete one
+		s.logger.Errorf("Error streaming %s %s: %v", endpoint.Method, endpoint.Path, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// streamArray writes a JSON array of count items to w, taking each from next and
+// encoding it before asking for the following one. When next fails the array is never
+// closed, so the output can't parse as a shorter, complete list.
+func streamArray(w io.Writer, count int, next func() (interface{}, error)) error {
+	buf := bufio.NewWriter(w)
+	buf.WriteByte('[')
+
+	for i := 0; i < count; i++ {
+		item, err := next()
+		if err != nil {
+			return fmt.Errorf("failed to generate item %d: %w", i, err)
+		}
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode item %d: %w", i, err)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if _, err := buf.Write(encoded); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteString("]\n")
+	return buf.Flush()
+}
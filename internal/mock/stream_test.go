@@ -0,0 +1,230 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestStreamArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		failAt   int // -1 never fails
+		expected int
+	}{
+		{name: "empty", count: 0, failAt: -1, expected: 0},
+		{name: "full", count: 3, failAt: -1, expected: 3},
+		{name: "fails midway", count: 5, failAt: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			i := 0
+			err := streamArray(&buf, tt.count, func() (interface{}, error) {
+				if i == tt.failAt {
+					return nil, errors.New("boom")
+				}
+				i++
+				return map[string]interface{}{"n": i}, nil
+			})
+			if (err != nil) != (tt.failAt >= 0) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.failAt >= 0 {
+				// A failed stream must not look like a complete, shorter list
+				if json.Valid(buf.Bytes()) {
+					t.Errorf("Expected an unterminated array, got %q", buf.String())
+				}
+				return
+			}
+
+			var items []interface{}
+			if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+				t.Fatalf("Expected valid JSON, got %q: %v", buf.String(), err)
+			}
+			if len(items) != tt.expected {
+				t.Errorf("Expected %d items, got %d", tt.expected, len(items))
+			}
+		})
+	}
+}
+
+func TestStreamedListResponse(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Events API
+  version: 1.0.0
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                maxItems: 3
+                items:
+                  type: object
+                  required: [id]
+                  properties:
+                    id:
+                      type: string
+                      format: uuid
+`)
+
+	server := NewServerWithOptions(schema, 8159, Options{StreamLists: 1000})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8159/events")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %s", got)
+	}
+	var items []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(items) != 1000 {
+		t.Fatalf("Expected 1000 streamed items despite maxItems, got %d", len(items))
+	}
+	if _, ok := items[999]["id"].(string); !ok {
+		t.Errorf("Expected generated items, got %v", items[999])
+	}
+}
+
+func TestStreamedListNeedsWholeBody(t *testing.T) {
+	schema := parseSpec(t, `openapi: 3.0.0
+info:
+  title: Events API
+  version: 1.0.0
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                maxItems: 3
+                items:
+                  type: string
+`)
+
+	// ETags hash the whole body, so the list is built and served whole instead
+	server := NewServerWithOptions(schema, 8165, Options{StreamLists: 1000, CacheHeaders: true})
+	go server.Start()
+	time.Sleep(100 * time.Millisecond)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	resp, err := http.Get("http://localhost:8165/events")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("ETag") == "" {
+		t.Error("Expected an ETag on the whole response")
+	}
+	var items []string
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(items) > 3 {
+		t.Errorf("Expected a generated list within maxItems, got %d items", len(items))
+	}
+}
+
+// benchmarkListItem is the item schema of the list benchmarks
+var benchmarkListItem = openapi3.NewObjectSchema().
+	WithProperty("id", openapi3.NewUUIDSchema()).
+	WithProperty("name", openapi3.NewStringSchema()).
+	WithProperty("count", openapi3.NewIntegerSchema())
+
+// liveHeap returns the bytes of heap still reachable, after a collection
+func liveHeap() int64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
+
+// BenchmarkListBuffered builds the whole list, then encodes it, as non-streamed responses
+// do. live-B/op is the heap the list holds once built; B/op counts every allocation.
+func BenchmarkListBuffered(b *testing.B) {
+	b.ReportAllocs()
+	var live int64
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		base := liveHeap()
+		b.StartTimer()
+
+		gen := generator.NewGenerator(1)
+		items := make([]interface{}, 10000)
+		for j := range items {
+			items[j], _ = gen.GenerateFromSchema(benchmarkListItem)
+		}
+
+		b.StopTimer()
+		live += liveHeap() - base
+		b.StartTimer()
+
+		json.NewEncoder(io.Discard).Encode(items)
+	}
+	b.ReportMetric(float64(live)/float64(b.N), "live-B/op")
+}
+
+// BenchmarkListStreamed encodes each item as it is generated, so only one is live at a time
+func BenchmarkListStreamed(b *testing.B) {
+	b.ReportAllocs()
+	var live int64
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		base := liveHeap()
+		b.StartTimer()
+
+		gen := generator.NewGenerator(1)
+		n := 0
+		streamArray(io.Discard, 10000, func() (interface{}, error) {
+			// Measure halfway through, when a buffered list would be half built
+			if n++; n == 5000 {
+				b.StopTimer()
+				live += liveHeap() - base
+				b.StartTimer()
+			}
+			return gen.GenerateFromSchema(benchmarkListItem)
+		})
+	}
+	b.ReportMetric(float64(live)/float64(b.N), "live-B/op")
+}
@@ -0,0 +1,88 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"text/template"
+	"time"
+)
+
+// pathParamPattern matches templated path segments such as {id}
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// templateData is the value templates are executed against:
+//
+//	.Method      request method
+//	.Path        request URL path
+//	.PathParams  path parameters by name, e.g. {{.PathParams.id}}; a catch-all
+//	             {path+} or {path...} holds the rest of the URL, e.g. "a/b/c"
+//	.Query       first value of each query parameter
+//	.Headers     first value of each request header
+//	.Body        decoded JSON request body (nil if absent or not JSON)
+//	.Response    the response mocktail generated from the schema
+//
+// Helper functions: now (RFC3339 timestamp), json (encode a value as JSON).
+type templateData struct {
+	Method     string
+	Path       string
+	PathParams map[string]string
+	Query      map[string]string
+	Headers    map[string]string
+	Body       interface{}
+	Response   interface{}
+}
+
+// templateFuncs are the helpers available inside response templates
+var templateFuncs = template.FuncMap{
+	"now": func() string {
+		return time.Now().UTC().Format(time.RFC3339)
+	},
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// parseTemplate compiles a response template with the standard helpers
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("response").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// renderTemplate executes a parsed response template and decodes the output as JSON
+func renderTemplate(tmpl *template.Template, schemaPath string, r *http.Request, generated interface{}) (interface{}, error) {
+	data := templateData{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		PathParams: make(map[string]string),
+		Query:      make(map[string]string),
+		Headers:    flattenHeaders(r.Header),
+		Response:   generated,
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(schemaPath, -1) {
+		name := paramName(match[1])
+		data.PathParams[name] = r.PathValue(name)
+	}
+	for name, values := range r.URL.Query() {
+		data.Query[name] = values[0]
+	}
+	if r.Body != nil {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		json.Unmarshal(body, &data.Body)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("failed to render response template: %w", err)
+	}
+
+	var response interface{}
+	if err := json.Unmarshal(out.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("response template did not produce valid JSON: %w", err)
+	}
+	return response, nil
+}
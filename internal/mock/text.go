@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Vooblin/mocktail/internal/generator"
+	"github.com/Vooblin/mocktail/internal/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// writeText answers with a generated scalar as a raw text body instead of JSON
+func (s *Server) writeText(rnd *requestRandom, w http.ResponseWriter, r *http.Request, endpoint parser.Endpoint, status int, mediaType, text string) {
+	if !s.sleep(r, s.delayFor(r, s.latencyFor(endpoint), len(text))) {
+		return
+	}
+
+	w.Header().Set("Content-Type", textContentType(mediaType))
+	w.Header().Set("Content-Length", strconv.Itoa(len(text)))
+	w.Header().Set("X-Mocktail-Server", "true")
+	s.setCookies(rnd, w.Header(), endpoint)
+	w.WriteHeader(status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write([]byte(text)); err != nil {
+		s.logger.Errorf("Error writing response: %v", err)
+	}
+}
+
+// textContentType declares UTF-8 on a text media type unless the spec already names a charset
+func textContentType(mediaType string) string {
+	if _, params, err := mime.ParseMediaType(mediaType); err == nil && params["charset"] == "" {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+// textPlaceholder is served when a plain-text body can't be generated outside --strict
+const textPlaceholder = "mock response"
+
+// generateText returns a plain-text body: the media-type example when examples are
+// preferred, otherwise a value generated from the schema. Like JSON bodies, a failed
+// generation is an error only with Options.Strict and otherwise a placeholder.
+func (s *Server) generateText(rnd *requestRandom, endpoint parser.Endpoint, operation *openapi3.Operation, statusKey string, schema *openapi3.Schema) (string, error) {
+	if s.options.PreferExamples {
+		if example, ok := generator.TextExample(operation, statusKey); ok {
+			return example, nil
+		}
+	}
+	text, err := rnd.gen.GenerateText(schema)
+	if err == nil {
+		return text, nil
+	}
+	if s.options.Strict {
+		return "", fmt.Errorf("failed to generate %s response for %s %s: %w", statusKey, endpoint.Method, endpoint.Path, err)
+	}
+	s.logger.Warnf("⚠️  Generation failed for %s %s, serving a placeholder body: %v", endpoint.Method, endpoint.Path, err)
+	return textPlaceholder, nil
+}
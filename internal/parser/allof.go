@@ -0,0 +1,268 @@
+package parser
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MergeAllOf returns a copy of schema with every allOf composition, including those
+// nested in properties, items, and additionalProperties, flattened into a single
+// schema. Where branches constrain the same keyword, the most restrictive value wins:
+// the highest minimum, the lowest maximum, the union of required lists, and the
+// intersection of types and enums. The input schema is left untouched.
+func MergeAllOf(schema *openapi3.Schema) *openapi3.Schema {
+	return newAllOfMerger().merge(schema)
+}
+
+// allOfMerger flattens schemas, remembering finished results so recursive schemas
+// terminate and shared components are merged once
+type allOfMerger struct {
+	done map[*openapi3.Schema]*openapi3.Schema
+}
+
+func newAllOfMerger() *allOfMerger {
+	return &allOfMerger{done: make(map[*openapi3.Schema]*openapi3.Schema)}
+}
+
+// merge flattens one schema
+func (m *allOfMerger) merge(schema *openapi3.Schema) *openapi3.Schema {
+	if schema == nil {
+		return nil
+	}
+	if merged, ok := m.done[schema]; ok {
+		return merged
+	}
+
+	merged := &openapi3.Schema{}
+	*merged = *schema
+	m.done[schema] = merged
+
+	merged.AllOf = nil
+	merged.OneOf = append(openapi3.SchemaRefs(nil), schema.OneOf...)
+	merged.AnyOf = append(openapi3.SchemaRefs(nil), schema.AnyOf...)
+	merged.Required = append([]string(nil), schema.Required...)
+	merged.Properties = make(openapi3.Schemas, len(schema.Properties))
+	for name, ref := range schema.Properties {
+		merged.Properties[name] = m.mergeRef(ref)
+	}
+	if len(merged.Properties) == 0 {
+		merged.Properties = nil
+	}
+	merged.Items = m.mergeRef(schema.Items)
+	merged.AdditionalProperties.Schema = m.mergeRef(schema.AdditionalProperties.Schema)
+
+	for _, ref := range schema.AllOf {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		m.combine(merged, m.merge(ref.Value))
+	}
+	return merged
+}
+
+// mergeRef flattens the schema behind a reference, keeping the reference path so
+// the result still reads as the named component it came from
+func (m *allOfMerger) mergeRef(ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if ref == nil || ref.Value == nil {
+		return ref
+	}
+	return &openapi3.SchemaRef{Ref: ref.Ref, Value: m.merge(ref.Value)}
+}
+
+// combine folds an already flattened branch into dst
+func (m *allOfMerger) combine(dst, src *openapi3.Schema) {
+	// A value is only nullable when every branch allows null; an untyped branch
+	// does not restrict it
+	nullable := allowsNull(dst) && allowsNull(src)
+	dst.Type = intersectTypes(dst.Type, src.Type)
+	if dst.Type == nil || len(*dst.Type) == 0 {
+		nullable = dst.Nullable || src.Nullable
+	}
+	dst.Nullable = nullable
+	dst.Enum = intersectEnums(dst.Enum, src.Enum)
+
+	if dst.Title == "" {
+		dst.Title = src.Title
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.Format == "" {
+		dst.Format = src.Format
+	}
+	if dst.Pattern == "" {
+		dst.Pattern = src.Pattern
+	}
+	if dst.Example == nil {
+		dst.Example = src.Example
+	}
+	if dst.Default == nil {
+		dst.Default = src.Default
+	}
+	if dst.MultipleOf == nil {
+		dst.MultipleOf = src.MultipleOf
+	}
+	if dst.Discriminator == nil {
+		dst.Discriminator = src.Discriminator
+	}
+
+	dst.ReadOnly = dst.ReadOnly || src.ReadOnly
+	dst.WriteOnly = dst.WriteOnly || src.WriteOnly
+	dst.Deprecated = dst.Deprecated || src.Deprecated
+	dst.UniqueItems = dst.UniqueItems || src.UniqueItems
+
+	dst.Min, dst.ExclusiveMin = tighterBound(dst.Min, dst.ExclusiveMin, src.Min, src.ExclusiveMin, true)
+	dst.Max, dst.ExclusiveMax = tighterBound(dst.Max, dst.ExclusiveMax, src.Max, src.ExclusiveMax, false)
+	dst.MinLength = max(dst.MinLength, src.MinLength)
+	dst.MaxLength = minLimit(dst.MaxLength, src.MaxLength)
+	dst.MinItems = max(dst.MinItems, src.MinItems)
+	dst.MaxItems = minLimit(dst.MaxItems, src.MaxItems)
+	dst.MinProps = max(dst.MinProps, src.MinProps)
+	dst.MaxProps = minLimit(dst.MaxProps, src.MaxProps)
+
+	for _, name := range src.Required {
+		if !contains(dst.Required, name) {
+			dst.Required = append(dst.Required, name)
+		}
+	}
+
+	if len(src.Properties) > 0 && dst.Properties == nil {
+		dst.Properties = make(openapi3.Schemas, len(src.Properties))
+	}
+	for _, name := range sortedSchemaNames(src.Properties) {
+		ref := src.Properties[name]
+		existing, ok := dst.Properties[name]
+		if !ok || existing == nil || existing.Value == nil {
+			dst.Properties[name] = ref
+			continue
+		}
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		// Both sides declare the property: combine copies so neither input changes
+		both := &openapi3.Schema{AllOf: openapi3.SchemaRefs{existing, ref}}
+		dst.Properties[name] = &openapi3.SchemaRef{Value: newAllOfMerger().merge(both)}
+	}
+
+	if dst.Items == nil {
+		dst.Items = src.Items
+	} else if src.Items != nil && src.Items.Value != nil && dst.Items.Value != nil {
+		both := &openapi3.Schema{AllOf: openapi3.SchemaRefs{dst.Items, src.Items}}
+		dst.Items = &openapi3.SchemaRef{Value: newAllOfMerger().merge(both)}
+	}
+
+	// Closing additional properties in any branch closes them for the whole value
+	if src.AdditionalProperties.Has != nil && !*src.AdditionalProperties.Has {
+		dst.AdditionalProperties = src.AdditionalProperties
+	} else if dst.AdditionalProperties.Has == nil && dst.AdditionalProperties.Schema == nil {
+		dst.AdditionalProperties = src.AdditionalProperties
+	}
+
+	dst.OneOf = append(dst.OneOf, src.OneOf...)
+	dst.AnyOf = append(dst.AnyOf, src.AnyOf...)
+	// A value must match neither not subschema, i.e. not their anyOf
+	if dst.Not == nil {
+		dst.Not = src.Not
+	} else if src.Not != nil {
+		dst.Not = &openapi3.SchemaRef{Value: &openapi3.Schema{AnyOf: openapi3.SchemaRefs{dst.Not, src.Not}}}
+	}
+}
+
+// allowsNull reports whether a schema accepts null, as nullable or untyped schemas do
+func allowsNull(schema *openapi3.Schema) bool {
+	return schema.Nullable || schema.Type == nil || len(*schema.Type) == 0
+}
+
+// intersectTypes keeps the types allowed by both sides; an unset side allows any type
+func intersectTypes(a, b *openapi3.Types) *openapi3.Types {
+	if a == nil || len(*a) == 0 {
+		return b
+	}
+	if b == nil || len(*b) == 0 {
+		return a
+	}
+	var types openapi3.Types
+	for _, t := range *a {
+		if b.Includes(t) {
+			types = append(types, t)
+		}
+	}
+	return &types
+}
+
+// intersectEnums keeps the values allowed by both sides; an empty side allows any value
+func intersectEnums(a, b []interface{}) []interface{} {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	var values []interface{}
+	for _, v := range a {
+		for _, w := range b {
+			if reflect.DeepEqual(v, w) {
+				values = append(values, v)
+				break
+			}
+		}
+	}
+	return values
+}
+
+// tighterBound picks the more restrictive of two numeric bounds. lower selects whether
+// they are minimums (higher wins) or maximums (lower wins); on a tie, an exclusive bound
+// is the stricter one.
+func tighterBound(a *float64, aExclusive bool, b *float64, bExclusive bool, lower bool) (*float64, bool) {
+	switch {
+	case b == nil:
+		return a, aExclusive
+	case a == nil:
+		return b, bExclusive
+	case *a == *b:
+		return a, aExclusive || bExclusive
+	case (*b > *a) == lower:
+		return b, bExclusive
+	default:
+		return a, aExclusive
+	}
+}
+
+// minLimit returns the smaller of two optional upper limits
+func minLimit(a, b *uint64) *uint64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+// contains reports whether names includes name
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedSchemaNames returns the keys of a schema map in order, for deterministic merging
+func sortedSchemaNames(schemas openapi3.Schemas) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonContentSchema returns the application/json schema of a content map, or nil
+func jsonContentSchema(content openapi3.Content) *openapi3.Schema {
+	media := content.Get("application/json")
+	if media == nil || media.Schema == nil {
+		return nil
+	}
+	return media.Schema.Value
+}
@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestMergeAllOf(t *testing.T) {
+	uint64Ptr := func(n uint64) *uint64 { return &n }
+	float64Ptr := func(f float64) *float64 { return &f }
+	object := func(required []string, properties openapi3.Schemas) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef("", &openapi3.Schema{
+			Type:       &openapi3.Types{"object"},
+			Required:   required,
+			Properties: properties,
+		})
+	}
+
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+		check  func(t *testing.T, merged *openapi3.Schema)
+	}{
+		{
+			name: "not constraints of both branches kept",
+			schema: &openapi3.Schema{
+				AllOf: openapi3.SchemaRefs{
+					openapi3.NewSchemaRef("", &openapi3.Schema{Not: openapi3.NewSchemaRef("", &openapi3.Schema{Enum: []interface{}{"a"}})}),
+					openapi3.NewSchemaRef("", &openapi3.Schema{Not: openapi3.NewSchemaRef("", &openapi3.Schema{Enum: []interface{}{"b"}})}),
+				},
+			},
+			check: func(t *testing.T, merged *openapi3.Schema) {
+				if merged.Not == nil || len(merged.Not.Value.AnyOf) != 2 {
+					t.Fatalf("Expected not to reject either branch's values, got %+v", merged.Not)
+				}
+				for _, value := range []string{"a", "b"} {
+					if merged.VisitJSON(value) == nil {
+						t.Errorf("Expected %q to be rejected", value)
+					}
+				}
+				if err := merged.VisitJSON("c"); err != nil {
+					t.Errorf("Expected \"c\" to pass, got %v", err)
+				}
+			},
+		},
+		{
+			name: "combined required lists",
+			schema: &openapi3.Schema{
+				Required: []string{"id"},
+				AllOf: openapi3.SchemaRefs{
+					object([]string{"id", "name"}, openapi3.Schemas{"id": openapi3.NewStringSchema().NewRef()}),
+					object([]string{"email"}, openapi3.Schemas{"email": openapi3.NewStringSchema().NewRef()}),
+				},
+			},
+			check: func(t *testing.T, merged *openapi3.Schema) {
+				if !slices.Equal(merged.Required, []string{"id", "name", "email"}) {
+					t.Errorf("Expected required [id name email], got %v", merged.Required)
+				}
+				if len(merged.AllOf) != 0 {
+					t.Errorf("Expected allOf to be flattened, got %d branches", len(merged.AllOf))
+				}
+				if !merged.Type.Is("object") {
+					t.Errorf("Expected object type, got %v", merged.Type)
+				}
+				if len(merged.Properties) != 2 {
+					t.Errorf("Expected 2 properties, got %v", merged.Properties)
+				}
+			},
+		},
+		{
+			name: "overlapping properties take the most restrictive constraints",
+			schema: &openapi3.Schema{
+				AllOf: openapi3.SchemaRefs{
+					object(nil, openapi3.Schemas{
+						"name": openapi3.NewSchemaRef("", &openapi3.Schema{
+							Type: &openapi3.Types{"string"}, MinLength: 1, MaxLength: uint64Ptr(100),
+						}),
+						"age": openapi3.NewSchemaRef("", &openapi3.Schema{
+							Type: &openapi3.Types{"integer"}, Min: float64Ptr(0), Max: float64Ptr(150),
+						}),
+						"role": openapi3.NewSchemaRef("", &openapi3.Schema{
+							Type: &openapi3.Types{"string"}, Enum: []interface{}{"admin", "user", "guest"},
+						}),
+					}),
+					object(nil, openapi3.Schemas{
+						"name": openapi3.NewSchemaRef("", &openapi3.Schema{
+							MinLength: 3, MaxLength: uint64Ptr(50),
+						}),
+						"age": openapi3.NewSchemaRef("", &openapi3.Schema{
+							Type: &openapi3.Types{"integer", "number"}, Min: float64Ptr(18), ExclusiveMax: true, Max: float64Ptr(150),
+						}),
+						"role": openapi3.NewSchemaRef("", &openapi3.Schema{
+							Enum: []interface{}{"user", "admin", "owner"},
+						}),
+					}),
+				},
+			},
+			check: func(t *testing.T, merged *openapi3.Schema) {
+				name := merged.Properties["name"].Value
+				if name.MinLength != 3 || name.MaxLength == nil || *name.MaxLength != 50 {
+					t.Errorf("Expected name length 3..50, got %d..%v", name.MinLength, name.MaxLength)
+				}
+				if !name.Type.Is("string") {
+					t.Errorf("Expected name to stay a string, got %v", name.Type)
+				}
+
+				age := merged.Properties["age"].Value
+				if *age.Min != 18 || *age.Max != 150 || !age.ExclusiveMax {
+					t.Errorf("Expected age in [18, 150), got [%v, %v) exclusive=%v", *age.Min, *age.Max, age.ExclusiveMax)
+				}
+				if !age.Type.Is("integer") {
+					t.Errorf("Expected age types to intersect to integer, got %v", age.Type)
+				}
+
+				role := merged.Properties["role"].Value
+				if !slices.Equal(role.Enum, []interface{}{"admin", "user"}) {
+					t.Errorf("Expected role enum [admin user], got %v", role.Enum)
+				}
+			},
+		},
+		{
+			name: "nested allOf in properties and items",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"array"},
+				Items: openapi3.NewSchemaRef("", &openapi3.Schema{
+					AllOf: openapi3.SchemaRefs{
+						object([]string{"id"}, openapi3.Schemas{"id": openapi3.NewIntegerSchema().NewRef()}),
+						object(nil, openapi3.Schemas{"tags": openapi3.NewSchemaRef("", &openapi3.Schema{
+							AllOf: openapi3.SchemaRefs{
+								openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"array"}, MinItems: 1}),
+								openapi3.NewSchemaRef("", &openapi3.Schema{MaxItems: uint64Ptr(5), UniqueItems: true}),
+							},
+						})}),
+					},
+				}),
+			},
+			check: func(t *testing.T, merged *openapi3.Schema) {
+				item := merged.Items.Value
+				if len(item.AllOf) != 0 || !slices.Equal(item.Required, []string{"id"}) {
+					t.Fatalf("Expected flattened item requiring id, got %+v", item)
+				}
+				tags := item.Properties["tags"].Value
+				if len(tags.AllOf) != 0 || tags.MinItems != 1 || *tags.MaxItems != 5 || !tags.UniqueItems {
+					t.Errorf("Expected tags with 1..5 unique items, got %+v", tags)
+				}
+			},
+		},
+		{
+			name: "nullable and additionalProperties",
+			schema: &openapi3.Schema{
+				AllOf: openapi3.SchemaRefs{
+					openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"object"}, Nullable: true}),
+					openapi3.NewSchemaRef("", &openapi3.Schema{
+						Nullable:             true,
+						AdditionalProperties: openapi3.AdditionalProperties{Has: openapi3.BoolPtr(false)},
+					}),
+				},
+			},
+			check: func(t *testing.T, merged *openapi3.Schema) {
+				if !merged.Nullable {
+					t.Error("Expected nullable when every branch allows null")
+				}
+				if has := merged.AdditionalProperties.Has; has == nil || *has {
+					t.Error("Expected additionalProperties to stay closed")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(tt.schema.AllOf)
+			tt.check(t, MergeAllOf(tt.schema))
+			if len(tt.schema.AllOf) != before {
+				t.Error("Expected the input schema to be left untouched")
+			}
+		})
+	}
+}
+
+func TestMergeAllOfRecursive(t *testing.T) {
+	node := &openapi3.Schema{Type: &openapi3.Types{"object"}}
+	node.Properties = openapi3.Schemas{
+		"children": openapi3.NewSchemaRef("", &openapi3.Schema{
+			Type:  &openapi3.Types{"array"},
+			Items: openapi3.NewSchemaRef("#/components/schemas/Node", node),
+		}),
+	}
+	schema := &openapi3.Schema{AllOf: openapi3.SchemaRefs{openapi3.NewSchemaRef("#/components/schemas/Node", node)}}
+
+	merged := MergeAllOf(schema)
+	if _, ok := merged.Properties["children"]; !ok {
+		t.Fatalf("Expected children property, got %v", merged.Properties)
+	}
+}
+
+func TestOpenAPIParser_ParseAllOfSchemas(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: AllOf API
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - $ref: '#/components/schemas/Base'
+                - type: object
+                  required: [name]
+                  properties:
+                    name:
+                      type: string
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: '#/components/schemas/Base'
+                  - required: [createdAt]
+                    properties:
+                      createdAt:
+                        type: string
+                        format: date-time
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+`
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	schema, err := NewOpenAPIParser().Parse(path)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	endpoint := schema.Paths["/pets"][0]
+
+	if endpoint.RequestSchema == nil || !slices.Equal(endpoint.RequestSchema.Required, []string{"id", "name"}) {
+		t.Errorf("Expected request schema requiring id and name, got %+v", endpoint.RequestSchema)
+	}
+}
@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle serializes a parsed OpenAPI document as one self-contained JSON file; the
+// parser has already moved references to other files into components, and OpenAPI 3.1
+// keywords it rewrote for loading are written as the spec declared them. With dereference
+// set, every internal $ref is also replaced by the definition it points to. Recursive
+// schemas can't be expanded, so their references are kept and reported in the returned
+// warnings. The document is only read, so a server may bundle the spec it is serving.
+func Bundle(doc *openapi3.T, dereference bool) ([]byte, []string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	restoreOriginals(tree)
+
+	var warnings []string
+	if dereference {
+		root, ok := tree.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("spec is not a JSON object")
+		}
+		d := &dereferencer{root: root}
+		if tree, err = d.inline(tree, nil); err != nil {
+			return nil, nil, err
+		}
+		for _, ref := range d.recursive {
+			warnings = append(warnings, fmt.Sprintf("recursive reference %s kept as $ref", ref))
+		}
+	}
+
+	data, err = json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	return append(data, '\n'), warnings, nil
+}
+
+// restoreOriginals undoes normalizeOpenAPI31 on a decoded document: every keyword a
+// schema remembers under originalExtension gets back its declared value, or is removed
+// if the spec didn't declare it
+func restoreOriginals(node interface{}) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if original, ok := v[originalExtension].(map[string]interface{}); ok {
+			delete(v, originalExtension)
+			for keyword, value := range original {
+				if value == nil {
+					delete(v, keyword)
+				} else {
+					v[keyword] = value
+				}
+			}
+		}
+		for _, child := range v {
+			restoreOriginals(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			restoreOriginals(child)
+		}
+	}
+}
+
+// dereferencer inlines internal references of a decoded JSON document
+type dereferencer struct {
+	root      map[string]interface{}
+	recursive []string
+}
+
+// inline returns a copy of node with every $ref replaced by its target; stack holds the
+// references being expanded so cycles are detected
+func (d *dereferencer) inline(node interface{}, stack []string) (interface{}, error) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if slices.Contains(stack, ref) {
+				if !slices.Contains(d.recursive, ref) {
+					d.recursive = append(d.recursive, ref)
+				}
+				return v, nil
+			}
+			target, err := d.resolve(ref)
+			if err != nil {
+				return nil, err
+			}
+			return d.inline(target, append(stack, ref))
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			inlined, err := d.inline(value, stack)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = inlined
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			inlined, err := d.inline(value, stack)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = inlined
+		}
+		return result, nil
+	default:
+		return node, nil
+	}
+}
+
+// resolve looks up a local JSON pointer such as #/components/schemas/Pet
+func (d *dereferencer) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("cannot dereference external reference %s", ref)
+	}
+
+	var node interface{} = d.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %s", ref)
+		}
+		if node, ok = object[token]; !ok {
+			return nil, fmt.Errorf("unresolvable reference %s", ref)
+		}
+	}
+	return node, nil
+}
+
+// JSONToYAML re-encodes a JSON document as block-style YAML, keeping key order
+func JSONToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	clearStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// clearStyle drops the flow and quoting styles inherited from JSON; the encoder
+// still quotes strings that would otherwise read as another type
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// DereferenceSchema renders one schema of a loaded document as indented JSON with every
+// internal $ref replaced by its definition, the way Bundle dereferences a whole spec.
+// Recursive references are kept as $ref. Like Bundle, it only reads the document.
+func DereferenceSchema(doc *openapi3.T, schema *openapi3.Schema) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+
+	if data, err = json.Marshal(schema); err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	restoreOriginals(root)
+	restoreOriginals(tree)
+
+	d := &dereferencer{root: root}
+	if tree, err = d.inline(tree, nil); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(tree, "", "  ")
+}
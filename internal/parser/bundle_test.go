@@ -0,0 +1,357 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const bundleSpec = `openapi: 3.0.0
+info:
+  title: Tree API
+  version: 1.0.0
+paths:
+  /nodes/{id}:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/NodeID'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Node'
+  /labels:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Label'
+components:
+  parameters:
+    NodeID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    Label:
+      type: object
+      properties:
+        name:
+          type: string
+    Node:
+      type: object
+      properties:
+        label:
+          $ref: '#/components/schemas/Label'
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`
+
+func TestBundle(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tree.yaml")
+	if err := os.WriteFile(file, []byte(bundleSpec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		dereference bool
+		check       func(t *testing.T, data string, warnings []string)
+	}{
+		{
+			name: "references kept",
+			check: func(t *testing.T, data string, warnings []string) {
+				if !strings.Contains(data, `"$ref": "#/components/schemas/Label"`) {
+					t.Error("Expected references to be kept without --dereference")
+				}
+				if len(warnings) != 0 {
+					t.Errorf("Expected no warnings, got %v", warnings)
+				}
+			},
+		},
+		{
+			name:        "dereferenced",
+			dereference: true,
+			check: func(t *testing.T, data string, warnings []string) {
+				if strings.Contains(data, "#/components/schemas/Label") || strings.Contains(data, "#/components/parameters/") {
+					t.Errorf("Expected non-recursive references to be inlined:\n%s", data)
+				}
+				if len(warnings) != 1 || !strings.Contains(warnings[0], "#/components/schemas/Node") {
+					t.Errorf("Expected a warning for the recursive Node schema, got %v", warnings)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewOpenAPIParser().Parse(file)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			data, warnings, err := Bundle(schema.Raw.(*openapi3.T), tt.dereference)
+			if err != nil {
+				t.Fatalf("Bundle() failed: %v", err)
+			}
+			tt.check(t, string(data), warnings)
+
+			// The bundle must load back as a valid spec
+			doc, err := openapi3.NewLoader().LoadFromData(data)
+			if err != nil {
+				t.Fatalf("Bundled spec failed to load: %v", err)
+			}
+			if err := doc.Validate(openapi3.NewLoader().Context); err != nil {
+				t.Errorf("Bundled spec failed validation: %v", err)
+			}
+		})
+	}
+}
+
+// writeSplitSpec writes a spec whose response schema lives in a sibling file and
+// returns the root file's path
+func writeSplitSpec(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"api.yaml": `openapi: 3.0.0
+info:
+  title: Split API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: './user.yaml'
+`,
+		"user.yaml": `type: object
+properties:
+  email:
+    type: string
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	return filepath.Join(tmpDir, "api.yaml")
+}
+
+func TestBundleLeavesDocumentUnchanged(t *testing.T) {
+	schema, err := NewOpenAPIParser().Parse(writeSplitSpec(t))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	doc := schema.Raw.(*openapi3.T)
+	before, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+
+	data, _, err := Bundle(doc, false)
+	if err != nil {
+		t.Fatalf("Bundle() failed: %v", err)
+	}
+	if strings.Contains(string(data), "user.yaml") {
+		t.Errorf("Expected the sibling file to be moved into components:\n%s", data)
+	}
+
+	after, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Error("Expected Bundle to leave the parsed document unchanged")
+	}
+}
+
+func TestDereferenceSchema(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tree.yaml")
+	if err := os.WriteFile(file, []byte(bundleSpec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	schema, err := NewOpenAPIParser().Parse(file)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	doc := schema.Raw.(*openapi3.T)
+
+	data, err := DereferenceSchema(doc, doc.Components.Schemas["Node"].Value)
+	if err != nil {
+		t.Fatalf("DereferenceSchema() failed: %v", err)
+	}
+	output := string(data)
+	if strings.Contains(output, "#/components/schemas/Label") || !strings.Contains(output, `"name"`) {
+		t.Errorf("Expected Label to be inlined:\n%s", output)
+	}
+	if !strings.Contains(output, `"$ref": "#/components/schemas/Node"`) {
+		t.Errorf("Expected the recursive Node reference to be kept:\n%s", output)
+	}
+}
+
+func TestDereferenceSchemaLeavesDocumentUnchanged(t *testing.T) {
+	schema, err := NewOpenAPIParser().Parse(writeSplitSpec(t))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	doc := schema.Raw.(*openapi3.T)
+	before, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+
+	response := doc.Paths.Value("/users").Get.Responses.Status(200).Value
+	data, err := DereferenceSchema(doc, response.Content.Get("application/json").Schema.Value)
+	if err != nil {
+		t.Fatalf("DereferenceSchema() failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"email"`) {
+		t.Errorf("Expected the sibling schema's properties, got %s", data)
+	}
+
+	after, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Error("Expected DereferenceSchema to leave the parsed document unchanged")
+	}
+}
+
+func TestBundleServesOpenAPI31AsWritten(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scores.yaml")
+	spec := `openapi: 3.1.0
+info:
+  title: Scores API
+  version: 1.0.0
+paths:
+  /scores:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  score:
+                    type: number
+                    exclusiveMinimum: 0
+                    maximum: 100
+                    exclusiveMaximum: 200
+                  note:
+                    type: [string, "null"]
+                  pair:
+                    type: array
+                    prefixItems:
+                      - type: string
+                      - type: integer
+                        exclusiveMinimum: 0
+                    items: false
+                  roles:
+                    type: array
+                    items:
+                      type: string
+                    contains:
+                      enum: [admin]
+                    minContains: 2
+              example:
+                score: 5
+                limits:
+                  exclusiveMinimum: 3
+                filter:
+                  type: [open, "null"]
+`
+	if err := os.WriteFile(file, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	schema, err := NewOpenAPIParser().Parse(file)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	data, _, err := Bundle(schema.Raw.(*openapi3.T), false)
+	if err != nil {
+		t.Fatalf("Bundle() failed: %v", err)
+	}
+	var bundled struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]interface{} `json:"properties"`
+					} `json:"schema"`
+					Example map[string]interface{} `json:"example"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &bundled); err != nil {
+		t.Fatalf("Failed to decode bundle: %v", err)
+	}
+	content := bundled.Paths["/scores"]["get"].Responses["200"].Content["application/json"]
+
+	score := content.Schema.Properties["score"]
+	expected := map[string]interface{}{"type": "number", "exclusiveMinimum": float64(0), "maximum": float64(100), "exclusiveMaximum": float64(200)}
+	if len(score) != len(expected) {
+		t.Errorf("Expected score schema %v, got %v", expected, score)
+	}
+	for keyword, value := range expected {
+		if score[keyword] != value {
+			t.Errorf("Expected %s: %v as written, got %v", keyword, value, score[keyword])
+		}
+	}
+
+	if note := content.Schema.Properties["note"]; len(note) != 1 || fmt.Sprint(note["type"]) != "[string null]" {
+		t.Errorf("Expected note's type list as written, got %v", note)
+	}
+
+	var pair map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"type": "array", "prefixItems": [{"type": "string"}, {"type": "integer", "exclusiveMinimum": 0}], "items": false}`), &pair); err != nil {
+		t.Fatalf("Failed to decode expected tuple: %v", err)
+	}
+	if got := content.Schema.Properties["pair"]; !reflect.DeepEqual(got, pair) {
+		t.Errorf("Expected tuple %v as written, got %v", pair, got)
+	}
+	var roles map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"type": "array", "items": {"type": "string"}, "contains": {"enum": ["admin"]}, "minContains": 2}`), &roles); err != nil {
+		t.Fatalf("Failed to decode expected contains: %v", err)
+	}
+	if got := content.Schema.Properties["roles"]; !reflect.DeepEqual(got, roles) {
+		t.Errorf("Expected contains %v as written, got %v", roles, got)
+	}
+	if strings.Contains(string(data), "x-mocktail") {
+		t.Errorf("Expected no mocktail extensions in the bundle, got %s", data)
+	}
+
+	limits, _ := content.Example["limits"].(map[string]interface{})
+	filter, _ := content.Example["filter"].(map[string]interface{})
+	if limits["exclusiveMinimum"] != float64(3) || fmt.Sprint(filter["type"]) != "[open null]" {
+		t.Errorf("Expected the example to be left alone, got %v", content.Example)
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// cacheFormatVersion is bumped whenever the cached representation changes, including
+// any change to how normalizeOpenAPI31 rewrites the documents it stores
+const cacheFormatVersion = "2"
+
+// DefaultCacheDir returns the per-user directory for cached parsed schemas
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "mocktail", "schemas"), nil
+}
+
+// schemaCacheKey derives a cache key from the spec's absolute path, mtime, and content hash
+func schemaCacheKey(path string, data []byte) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%s",
+		cacheFormatVersion, absPath, info.ModTime().UnixNano(), contentHash(data))))
+	return hex.EncodeToString(key[:]), nil
+}
+
+// cacheEntry is what a cache file holds: the normalized document, the warnings parsing
+// it produced, and the content hash of every file its external $refs read
+type cacheEntry struct {
+	Doc      json.RawMessage   `json:"doc"`
+	Warnings []string          `json:"warnings,omitempty"`
+	Files    map[string]string `json:"files,omitempty"`
+}
+
+// fileRecorder reads the files external $refs point to, recording the content hash of
+// each so a cache entry is invalidated when any of them changes
+type fileRecorder struct {
+	files map[string]string
+	// remote is set once a reference is fetched over HTTP, which can't be checked
+	// for changes, so the document is not cached
+	remote bool
+}
+
+func newFileRecorder() *fileRecorder {
+	return &fileRecorder{files: make(map[string]string)}
+}
+
+// read is an openapi3.ReadFromURIFunc. It bypasses kin-openapi's process-wide read
+// cache so every parse sees the files as they are now.
+func (r *fileRecorder) read(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+	data, err := openapi3.ReadFromURIs(openapi3.ReadFromHTTP(http.DefaultClient), openapi3.ReadFromFile)(loader, location)
+	if err != nil {
+		return nil, err
+	}
+	if location.Host != "" {
+		r.remote = true
+		return data, nil
+	}
+	if absPath, err := filepath.Abs(filepath.FromSlash(location.Path)); err == nil {
+		r.files[absPath] = contentHash(data)
+	}
+	return data, nil
+}
+
+// contentHash returns the hex SHA-256 of data
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// unchanged reports whether every recorded file still has its recorded content
+func unchanged(files map[string]string) bool {
+	for path, hash := range files {
+		data, err := os.ReadFile(path)
+		if err != nil || contentHash(data) != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// loadCachedDoc returns the cached document for key and the warnings parsing it
+// produced, or nil on a miss, an unreadable entry, or a changed referenced file
+func loadCachedDoc(dir, key, specPath string) (*openapi3.T, []string) {
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return nil, nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || !unchanged(entry.Files) {
+		return nil, nil
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromDataWithPath(entry.Doc, &url.URL{Path: specPath})
+	if err != nil {
+		return nil, nil
+	}
+	return doc, entry.Warnings
+}
+
+// storeCachedDoc writes a validated document, its warnings, and the files it read to
+// the cache; failures are ignored because the cache is only an optimization
+func storeCachedDoc(dir, key string, doc *openapi3.T, warnings []string, files *fileRecorder) {
+	if files.remote {
+		return
+	}
+	docData, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cacheEntry{Doc: docData, Warnings: warnings, Files: files.files})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	// Write atomically so concurrent invocations never read a partial entry
+	tmp, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, key+".json")); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
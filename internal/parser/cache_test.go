@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAPIParser_ParseCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specFile := filepath.Join(tmpDir, "api.yaml")
+
+	writeSpec := func(title string, mtime time.Time) {
+		spec := `openapi: 3.0.0
+info:
+  title: ` + title + `
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: Successful response
+`
+		if err := os.WriteFile(specFile, []byte(spec), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+		if err := os.Chtimes(specFile, mtime, mtime); err != nil {
+			t.Fatalf("Failed to set mtime: %v", err)
+		}
+	}
+
+	p := NewOpenAPIParserWithOptions(ParseOptions{CacheDir: cacheDir})
+	writeSpec("First", time.Unix(1000, 0))
+
+	schema, err := p.Parse(specFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 cache entry after first parse, got %d", len(entries))
+	}
+
+	// A cache hit must produce the same schema
+	cached, err := p.Parse(specFile)
+	if err != nil {
+		t.Fatalf("Cached Parse() failed: %v", err)
+	}
+	if cached.Title != schema.Title || len(cached.Paths) != len(schema.Paths) {
+		t.Errorf("Expected cached schema to match, got %+v", cached)
+	}
+
+	// Changing the file invalidates the entry
+	writeSpec("Second", time.Unix(2000, 0))
+	changed, err := p.Parse(specFile)
+	if err != nil {
+		t.Fatalf("Parse() after change failed: %v", err)
+	}
+	if changed.Title != "Second" {
+		t.Errorf("Expected updated title 'Second', got '%s'", changed.Title)
+	}
+}
+
+func TestOpenAPIParser_ParseCacheChecksReferencedFiles(t *testing.T) {
+	specFile := writeSplitSpec(t)
+	p := NewOpenAPIParserWithOptions(ParseOptions{CacheDir: t.TempDir()})
+
+	if _, err := p.Parse(specFile); err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// Only the sibling file changes; the root file and its mtime stay the same
+	user := `type: object
+properties:
+  email:
+    type: string
+  name:
+    type: string
+`
+	if err := os.WriteFile(filepath.Join(filepath.Dir(specFile), "user.yaml"), []byte(user), 0644); err != nil {
+		t.Fatalf("Failed to update user.yaml: %v", err)
+	}
+
+	schema, err := p.Parse(specFile)
+	if err != nil {
+		t.Fatalf("Parse() after change failed: %v", err)
+	}
+	if properties := okResponseSchema(schema, "/users").Properties; properties["name"] == nil {
+		t.Errorf("Expected the changed sibling file to be read again, got properties %v", properties)
+	}
+}
+
+func TestOpenAPIParser_ParseCacheReplaysWarnings(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "tree.yaml")
+	spec := `openapi: 3.1.0
+info:
+  title: Tree API
+  version: 1.0.0
+paths:
+  /nodes:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                unevaluatedProperties: false
+`
+	if err := os.WriteFile(specFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	p := NewOpenAPIParserWithOptions(ParseOptions{CacheDir: t.TempDir()})
+
+	first, err := p.Parse(specFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	cached, err := p.Parse(specFile)
+	if err != nil {
+		t.Fatalf("Cached Parse() failed: %v", err)
+	}
+
+	if len(first.Warnings) == 0 || !strings.Contains(strings.Join(first.Warnings, "\n"), "unevaluatedProperties") {
+		t.Fatalf("Expected a warning about unevaluatedProperties, got %v", first.Warnings)
+	}
+	if !slices.Equal(cached.Warnings, first.Warnings) {
+		t.Errorf("Expected a cache hit to report %v, got %v", first.Warnings, cached.Warnings)
+	}
+}
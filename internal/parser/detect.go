@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// graphQLSDLPattern matches the start of a GraphQL type or schema definition
+var graphQLSDLPattern = regexp.MustCompile(`(?m)^\s*(type|schema|extend\s+type)\s+\w*\s*\{|^\s*type\s+\w+`)
+
+// Detect picks the parser for a schema file, first by extension (.graphql, .graphqls,
+// .gql) and otherwise by content: a top-level "openapi" key means OpenAPI 3.x.
+func Detect(filename string, opts ParseOptions) (Parser, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".graphql", ".graphqls", ".gql":
+		return NewGraphQLParser(), nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// JSON is valid YAML, so one decode covers both spec encodings
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err == nil && doc != nil {
+		if _, ok := doc["openapi"]; ok {
+			return NewOpenAPIParserWithOptions(opts), nil
+		}
+		if _, ok := doc["swagger"]; ok {
+			return nil, fmt.Errorf("%s is a Swagger 2.0 spec; convert it to OpenAPI 3.x first", filename)
+		}
+	}
+
+	if graphQLSDLPattern.Match(data) {
+		return NewGraphQLParser(), nil
+	}
+
+	return nil, fmt.Errorf("could not detect the schema format of %s (expected OpenAPI 3.x or GraphQL SDL)", filename)
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		expected  string
+		expectErr bool
+	}{
+		{name: "graphql extension", file: "api.graphql", content: librarySDL, expected: "graphql"},
+		{name: "openapi yaml", file: "api.yaml", content: "openapi: 3.0.0\ninfo:\n  title: T\n  version: '1'\npaths: {}\n", expected: "openapi"},
+		{name: "openapi json", file: "api.json", content: `{"openapi": "3.0.0"}`, expected: "openapi"},
+		{name: "graphql by content", file: "schema.txt", content: "type Query {\n  ping: String\n}\n", expected: "graphql"},
+		{name: "swagger 2", file: "old.yaml", content: "swagger: '2.0'\n", expectErr: true},
+		{name: "unknown", file: "notes.txt", content: "hello world\n", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			p, err := Detect(file, ParseOptions{})
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Detect() failed: %v", err)
+			}
+
+			var got string
+			switch p.(type) {
+			case *OpenAPIParser:
+				got = "openapi"
+			case *GraphQLParser:
+				got = "graphql"
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %s parser, got %T", tt.expected, p)
+			}
+		})
+	}
+}
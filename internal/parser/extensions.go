@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Vendor extensions that let an operation carry its own mock behavior
+const (
+	extensionStatus  = "x-mocktail-status"  // status code to answer with, e.g. 202
+	extensionLatency = "x-mocktail-latency" // delay as a duration string ("250ms") or milliseconds
+	extensionExample = "x-mocktail-example" // fixed response body returned instead of generated data
+)
+
+// applyExtensions copies x-mocktail-* overrides from an operation into its endpoint,
+// returning a warning for each extension with an unusable value
+func applyExtensions(endpoint *Endpoint, operation *openapi3.Operation) []string {
+	var warnings []string
+	warn := func(name string, value interface{}) {
+		warnings = append(warnings, fmt.Sprintf("%s %s has an invalid %s value %v; ignoring it",
+			endpoint.Method, endpoint.Path, name, value))
+	}
+
+	if value, ok := operation.Extensions[extensionStatus]; ok {
+		status, err := extensionInt(value)
+		if err != nil || status < 100 || status > 599 {
+			warn(extensionStatus, value)
+		} else {
+			endpoint.Status = status
+		}
+	}
+
+	if value, ok := operation.Extensions[extensionLatency]; ok {
+		latency, err := extensionDuration(value)
+		if err != nil || latency < 0 {
+			warn(extensionLatency, value)
+		} else {
+			endpoint.Latency = latency
+		}
+	}
+
+	if value, ok := operation.Extensions[extensionExample]; ok {
+		endpoint.Example = value
+	}
+
+	return warnings
+}
+
+// extensionInt reads an integer written as a number or a numeric string
+func extensionInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		return strconv.Atoi(v)
+	}
+	return 0, fmt.Errorf("unsupported type %T", value)
+}
+
+// extensionDuration reads a duration string, or a bare number of milliseconds
+func extensionDuration(value interface{}) (time.Duration, error) {
+	if s, ok := value.(string); ok {
+		return time.ParseDuration(s)
+	}
+	ms, err := extensionInt(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
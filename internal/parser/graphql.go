@@ -0,0 +1,739 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GraphQLEndpointPath is the route a GraphQL schema is served on
+const GraphQLEndpointPath = "/graphql"
+
+// GraphQLParser implements Parser for GraphQL SDL schema files
+type GraphQLParser struct{}
+
+// NewGraphQLParser creates a new GraphQL SDL parser
+func NewGraphQLParser() *GraphQLParser {
+	return &GraphQLParser{}
+}
+
+// GraphQLSchema is the Raw value of a parsed GraphQL schema
+type GraphQLSchema struct {
+	Types        map[string]*GraphQLType
+	QueryType    string
+	MutationType string
+}
+
+// GraphQLType is an object, interface, input, enum, union, or scalar definition
+type GraphQLType struct {
+	Name       string
+	Kind       string // "type", "interface", "input", "enum", "union", "scalar"
+	Fields     []GraphQLField
+	Values     []string // enum values or union members
+	Interfaces []string // interfaces an object type implements
+}
+
+// GraphQLField is a field of an object, interface, or input type
+type GraphQLField struct {
+	Name string
+	Type GraphQLTypeRef
+}
+
+// GraphQLTypeRef is a field type such as String, [User!]!, or ID!
+type GraphQLTypeRef struct {
+	Name    string          // named type; empty for lists
+	OfType  *GraphQLTypeRef // element type of a list
+	NonNull bool
+}
+
+func (t GraphQLTypeRef) String() string {
+	s := t.Name
+	if t.OfType != nil {
+		s = "[" + t.OfType.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// Field returns the named field of a type, if declared
+func (t *GraphQLType) Field(name string) (GraphQLField, bool) {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return GraphQLField{}, false
+}
+
+// Parse reads a GraphQL SDL file and exposes it as a single POST /graphql endpoint
+func (p *GraphQLParser) Parse(filename string) (*Schema, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	gql, err := ParseGraphQLSchema(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema: %w", err)
+	}
+
+	schema := &Schema{
+		Type:    "graphql",
+		Version: "SDL",
+		Title:   strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)),
+		Paths: map[string][]Endpoint{
+			GraphQLEndpointPath: {{
+				Method:  "POST",
+				Path:    GraphQLEndpointPath,
+				Summary: "GraphQL endpoint",
+			}},
+		},
+		Raw: gql,
+	}
+	if gql.MutationType == "" {
+		schema.Paths[GraphQLEndpointPath][0].Description = "Queries against " + gql.QueryType
+	} else {
+		schema.Paths[GraphQLEndpointPath][0].Description = "Queries against " + gql.QueryType + ", mutations against " + gql.MutationType
+	}
+	return schema, nil
+}
+
+// ParseGraphQLSchema parses GraphQL SDL source into its type definitions
+func ParseGraphQLSchema(source string) (*GraphQLSchema, error) {
+	tokens, err := lexGraphQL(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &gqlParser{tokens: tokens}
+	schema := &GraphQLSchema{Types: make(map[string]*GraphQLType)}
+
+	for !p.done() {
+		p.skipDescription()
+		if p.done() {
+			break
+		}
+
+		keyword := p.next()
+		if keyword == "extend" {
+			keyword = p.next()
+		}
+
+		switch keyword {
+		case "schema":
+			p.skipDirectives()
+			if err := p.expect("{"); err != nil {
+				return nil, err
+			}
+			for !p.done() && p.peek() != "}" {
+				operation := p.next()
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				switch operation {
+				case "query":
+					schema.QueryType = p.next()
+				case "mutation":
+					schema.MutationType = p.next()
+				default:
+					p.next()
+				}
+			}
+			if err := p.expect("}"); err != nil {
+				return nil, err
+			}
+
+		case "type", "interface", "input":
+			t := schema.define(p.next(), keyword)
+			if p.peek() == "implements" {
+				p.next()
+				for p.peek() == "&" || isGraphQLName(p.peek()) {
+					if name := p.next(); name != "&" {
+						t.Interfaces = append(t.Interfaces, name)
+					}
+				}
+			}
+			p.skipDirectives()
+			if p.peek() != "{" {
+				continue
+			}
+			fields, err := p.parseFieldDefinitions()
+			if err != nil {
+				return nil, fmt.Errorf("type %s: %w", t.Name, err)
+			}
+			t.Fields = append(t.Fields, fields...)
+
+		case "enum":
+			t := schema.define(p.next(), keyword)
+			p.skipDirectives()
+			if p.peek() != "{" {
+				continue
+			}
+			p.next()
+			for !p.done() && p.peek() != "}" {
+				p.skipDescription()
+				t.Values = append(t.Values, p.next())
+				p.skipDirectives()
+			}
+			if err := p.expect("}"); err != nil {
+				return nil, err
+			}
+
+		case "union":
+			t := schema.define(p.next(), keyword)
+			p.skipDirectives()
+			if p.peek() == "=" {
+				p.next()
+				for !p.done() {
+					if p.peek() == "|" {
+						p.next()
+						continue
+					}
+					if !isGraphQLName(p.peek()) || isGraphQLKeyword(p.peek()) {
+						break
+					}
+					t.Values = append(t.Values, p.next())
+				}
+			}
+
+		case "scalar":
+			schema.define(p.next(), keyword)
+			p.skipDirectives()
+
+		case "directive":
+			p.next() // @name
+			if p.peek() == "(" {
+				p.skipBalanced("(", ")")
+			}
+			if p.peek() == "repeatable" {
+				p.next()
+			}
+			if p.peek() == "on" {
+				p.next()
+				for !p.done() && (p.peek() == "|" || (isGraphQLName(p.peek()) && !isGraphQLKeyword(p.peek()))) {
+					p.next()
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("unexpected %q", keyword)
+		}
+	}
+
+	if schema.QueryType == "" {
+		schema.QueryType = "Query"
+	}
+	if schema.MutationType == "" {
+		if _, ok := schema.Types["Mutation"]; ok {
+			schema.MutationType = "Mutation"
+		}
+	}
+	if _, ok := schema.Types[schema.QueryType]; !ok {
+		return nil, fmt.Errorf("schema has no %s type", schema.QueryType)
+	}
+
+	return schema, nil
+}
+
+// define returns the type with the given name, creating it on first use
+func (s *GraphQLSchema) define(name, kind string) *GraphQLType {
+	t, ok := s.Types[name]
+	if !ok {
+		t = &GraphQLType{Name: name, Kind: kind}
+		s.Types[name] = t
+	}
+	return t
+}
+
+// TypeNames returns the defined type names in sorted order
+func (s *GraphQLSchema) TypeNames() []string {
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GraphQLSelection is a field requested by a query, with its nested selections
+type GraphQLSelection struct {
+	Name       string
+	Alias      string // response key; equals Name when no alias is given
+	Selections []GraphQLSelection
+
+	// Arguments holds scalar argument values: strings unquoted, variables as $name.
+	// List and input object arguments are skipped.
+	Arguments map[string]string
+
+	// On is the type condition of the fragment the field was selected in, e.g. Book
+	// for ... on Book { title }; empty when the field applies to any type
+	On string
+}
+
+// GraphQLOperation is the executable part of a query document
+type GraphQLOperation struct {
+	Type       string // "query", "mutation", or "subscription"
+	Name       string
+	Selections []GraphQLSelection
+}
+
+// ParseGraphQLQuery parses a query document and returns the named operation (or the
+// first one when operationName is empty), with fragment spreads expanded inline
+func ParseGraphQLQuery(query, operationName string) (*GraphQLOperation, error) {
+	tokens, err := lexGraphQL(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &gqlParser{tokens: tokens}
+	fragments := make(map[string]gqlRawSelection)
+	var operations []struct {
+		op  GraphQLOperation
+		raw []gqlRawSelection
+	}
+
+	for !p.done() {
+		var op GraphQLOperation
+		switch p.peek() {
+		case "{":
+			op.Type = "query"
+		case "query", "mutation", "subscription":
+			op.Type = p.next()
+			if isGraphQLName(p.peek()) {
+				op.Name = p.next()
+			}
+			if p.peek() == "(" {
+				p.skipBalanced("(", ")")
+			}
+			p.skipDirectives()
+		case "fragment":
+			p.next()
+			name := p.next()
+			if p.next() != "on" {
+				return nil, fmt.Errorf("fragment %s is missing a type condition", name)
+			}
+			on := p.next()
+			p.skipDirectives()
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			if selections == nil {
+				selections = []gqlRawSelection{}
+			}
+			fragments[name] = gqlRawSelection{inline: selections, on: on}
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected %q", p.peek())
+		}
+
+		raw, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, struct {
+			op  GraphQLOperation
+			raw []gqlRawSelection
+		}{op, raw})
+	}
+
+	for _, candidate := range operations {
+		if operationName == "" || candidate.op.Name == operationName {
+			op := candidate.op
+			budget := maxExpandedSelections
+			selections, err := expandSelections(candidate.raw, fragments, "", 0, &budget)
+			if err != nil {
+				return nil, err
+			}
+			op.Selections = selections
+			return &op, nil
+		}
+	}
+	if operationName != "" {
+		return nil, fmt.Errorf("operation %q not found", operationName)
+	}
+	return nil, fmt.Errorf("query has no operations")
+}
+
+// gqlRawSelection is a selection before fragment spreads are expanded
+type gqlRawSelection struct {
+	field    GraphQLSelection
+	spread   string            // named fragment spread
+	inline   []gqlRawSelection // inline or named fragment contents
+	on       string            // the fragment's type condition, if any
+	children []gqlRawSelection
+}
+
+// maxFragmentDepth bounds fragment expansion so cyclic fragments can't loop forever
+const maxFragmentDepth = 32
+
+// maxExpandedSelections bounds the fields a query expands to, so fragments spread
+// several times per level can't multiply into billions of selections
+const maxExpandedSelections = 10000
+
+// expandSelections resolves fragment spreads and inline fragments into plain fields,
+// marking each with the type condition it was selected under (on, outside any
+// fragment). budget counts down the fields still allowed.
+func expandSelections(raw []gqlRawSelection, fragments map[string]gqlRawSelection, on string, depth int, budget *int) ([]GraphQLSelection, error) {
+	if depth > maxFragmentDepth {
+		return nil, fmt.Errorf("fragments nested too deeply")
+	}
+
+	var selections []GraphQLSelection
+	for _, r := range raw {
+		if r.spread != "" {
+			fragment, ok := fragments[r.spread]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q", r.spread)
+			}
+			r = fragment
+		}
+
+		if r.inline != nil {
+			condition := on
+			if r.on != "" {
+				condition = r.on
+			}
+			expanded, err := expandSelections(r.inline, fragments, condition, depth+1, budget)
+			if err != nil {
+				return nil, err
+			}
+			selections = append(selections, expanded...)
+			continue
+		}
+
+		if *budget--; *budget < 0 {
+			return nil, fmt.Errorf("query expands to more than %d fields", maxExpandedSelections)
+		}
+		field := r.field
+		field.On = on
+		children, err := expandSelections(r.children, fragments, "", depth+1, budget)
+		if err != nil {
+			return nil, err
+		}
+		field.Selections = children
+		selections = append(selections, field)
+	}
+	return selections, nil
+}
+
+// gqlParser walks a token stream for both SDL and query documents
+type gqlParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *gqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *gqlParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *gqlParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *gqlParser) expect(token string) error {
+	if got := p.next(); got != token {
+		return fmt.Errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+// skipDescription skips a string literal preceding a definition
+func (p *gqlParser) skipDescription() {
+	for strings.HasPrefix(p.peek(), `"`) {
+		p.next()
+	}
+}
+
+// skipDirectives skips any @directive(args) annotations
+func (p *gqlParser) skipDirectives() {
+	for strings.HasPrefix(p.peek(), "@") {
+		p.next()
+		if p.peek() == "(" {
+			p.skipBalanced("(", ")")
+		}
+	}
+}
+
+// skipBalanced skips from an opening token to its matching closing token
+func (p *gqlParser) skipBalanced(open, close string) {
+	depth := 0
+	for !p.done() {
+		switch p.next() {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// skipValue skips a single default value, which may be a list or input object
+func (p *gqlParser) skipValue() {
+	switch p.peek() {
+	case "[":
+		p.skipBalanced("[", "]")
+	case "{":
+		p.skipBalanced("{", "}")
+	default:
+		p.next()
+	}
+}
+
+// parseFieldDefinitions parses a { name(args): Type } block of an SDL type
+func (p *gqlParser) parseFieldDefinitions() ([]GraphQLField, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []GraphQLField
+	for !p.done() && p.peek() != "}" {
+		p.skipDescription()
+		name := p.next()
+		if p.peek() == "(" {
+			p.skipBalanced("(", ")")
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		ref, err := p.parseTypeRef()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		if p.peek() == "=" {
+			p.next()
+			p.skipValue()
+		}
+		p.skipDirectives()
+		fields = append(fields, GraphQLField{Name: name, Type: ref})
+	}
+	return fields, p.expect("}")
+}
+
+// parseTypeRef parses a type reference such as [String!]!
+func (p *gqlParser) parseTypeRef() (GraphQLTypeRef, error) {
+	var ref GraphQLTypeRef
+	if p.peek() == "[" {
+		p.next()
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return ref, err
+		}
+		if err := p.expect("]"); err != nil {
+			return ref, err
+		}
+		ref.OfType = &inner
+	} else {
+		name := p.next()
+		if !isGraphQLName(name) {
+			return ref, fmt.Errorf("expected a type name, got %q", name)
+		}
+		ref.Name = name
+	}
+	if p.peek() == "!" {
+		p.next()
+		ref.NonNull = true
+	}
+	return ref, nil
+}
+
+// parseSelectionSet parses a { field alias: field(args) { ... } ...Fragment } block
+func (p *gqlParser) parseSelectionSet() ([]gqlRawSelection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []gqlRawSelection
+	for !p.done() && p.peek() != "}" {
+		if p.peek() == "..." {
+			p.next()
+			if p.peek() == "on" || p.peek() == "{" || strings.HasPrefix(p.peek(), "@") {
+				var on string
+				if p.peek() == "on" {
+					p.next()
+					on = p.next()
+				}
+				p.skipDirectives()
+				inline, err := p.parseSelectionSet()
+				if err != nil {
+					return nil, err
+				}
+				if inline == nil {
+					inline = []gqlRawSelection{}
+				}
+				selections = append(selections, gqlRawSelection{inline: inline, on: on})
+				continue
+			}
+			selections = append(selections, gqlRawSelection{spread: p.next()})
+			p.skipDirectives()
+			continue
+		}
+
+		name := p.next()
+		if !isGraphQLName(name) {
+			return nil, fmt.Errorf("expected a field name, got %q", name)
+		}
+		field := GraphQLSelection{Name: name, Alias: name}
+		if p.peek() == ":" {
+			p.next()
+			field.Name = p.next()
+		}
+		if p.peek() == "(" {
+			field.Arguments = p.parseArguments()
+		}
+		p.skipDirectives()
+
+		selection := gqlRawSelection{field: field}
+		if p.peek() == "{" {
+			children, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			selection.children = children
+		}
+		selections = append(selections, selection)
+	}
+	return selections, p.expect("}")
+}
+
+// parseArguments parses a (name: value, ...) list, keeping the scalar values
+func (p *gqlParser) parseArguments() map[string]string {
+	p.next()
+	arguments := make(map[string]string)
+	for !p.done() && p.peek() != ")" {
+		name := p.next()
+		if p.peek() != ":" {
+			continue
+		}
+		p.next()
+		if value := p.peek(); value == "[" || value == "{" {
+			p.skipValue()
+			continue
+		}
+		arguments[name] = unquoteGraphQL(p.next())
+	}
+	p.next()
+	return arguments
+}
+
+// unquoteGraphQL returns the contents of a string literal token; other tokens are
+// returned as-is
+func unquoteGraphQL(token string) string {
+	if strings.HasPrefix(token, `"""`) && len(token) >= 6 {
+		return strings.TrimSpace(token[3 : len(token)-3])
+	}
+	if strings.HasPrefix(token, `"`) {
+		if value, err := strconv.Unquote(token); err == nil {
+			return value
+		}
+		return strings.Trim(token, `"`)
+	}
+	return token
+}
+
+// lexGraphQL splits GraphQL source into names, punctuators, numbers, and string
+// literals (kept with their quotes); commas, whitespace, and comments are dropped
+func lexGraphQL(source string) ([]string, error) {
+	var tokens []string
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',' || r == '\uFEFF':
+			i++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			start := i
+			if hasRunePrefix(runes[i:], `"""`) {
+				i += 3
+				for i < len(runes) && !hasRunePrefix(runes[i:], `"""`) {
+					i++
+				}
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated block string")
+				}
+				i += 3
+			} else {
+				i++
+				for i < len(runes) && runes[i] != '"' {
+					if runes[i] == '\\' {
+						i++
+					}
+					if i < len(runes) && runes[i] == '\n' {
+						return nil, fmt.Errorf("unterminated string")
+					}
+					i++
+				}
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case r == '.':
+			if !hasRunePrefix(runes[i:], "...") {
+				return nil, fmt.Errorf("unexpected '.'")
+			}
+			tokens = append(tokens, "...")
+			i += 3
+		case r == '@' || r == '$' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-':
+			start := i
+			i++
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '.' && unicode.IsDigit(runes[start])) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case strings.ContainsRune("{}()[]:!=|&", r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+// hasRunePrefix reports whether runes begins with prefix
+func hasRunePrefix(runes []rune, prefix string) bool {
+	return strings.HasPrefix(string(runes[:min(len(runes), len(prefix))]), prefix)
+}
+
+// isGraphQLName reports whether a token is a name rather than punctuation or a literal
+func isGraphQLName(token string) bool {
+	if token == "" {
+		return false
+	}
+	r := []rune(token)[0]
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isGraphQLKeyword reports whether a token starts a new SDL definition
+func isGraphQLKeyword(token string) bool {
+	switch token {
+	case "type", "interface", "input", "enum", "union", "scalar", "schema", "extend", "directive":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const librarySDL = `
+"""A library catalogue"""
+schema {
+  query: Query
+  mutation: Mutation
+}
+
+directive @cost(weight: Int = 1) on FIELD_DEFINITION | OBJECT
+
+scalar DateTime
+
+enum Genre { FICTION, SCIENCE HISTORY }
+
+interface Node {
+  id: ID!
+}
+
+type Book implements Node & Searchable @cost(weight: 2) {
+  id: ID!
+  "The book's title"
+  title: String!
+  genre: Genre
+  authors: [Author!]!
+  published: DateTime
+}
+
+type Author implements Node {
+  id: ID!
+  name(format: String = "full"): String
+}
+
+union SearchResult = Book | Author
+
+input BookFilter {
+  genre: Genre = FICTION
+  tags: [String] = ["a", "b"]
+}
+
+type Query {
+  books(filter: BookFilter, first: Int = 10): [Book!]! # paginated
+  search(term: String!): [SearchResult]
+}
+
+type Mutation {
+  addBook(title: String!): Book
+}
+`
+
+func TestParseGraphQLSchema(t *testing.T) {
+	schema, err := ParseGraphQLSchema(librarySDL)
+	if err != nil {
+		t.Fatalf("ParseGraphQLSchema() failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{
+			name: "root types",
+			check: func(t *testing.T) {
+				if schema.QueryType != "Query" || schema.MutationType != "Mutation" {
+					t.Errorf("Expected Query/Mutation roots, got %s/%s", schema.QueryType, schema.MutationType)
+				}
+			},
+		},
+		{
+			name: "field types",
+			check: func(t *testing.T) {
+				field, ok := schema.Types["Query"].Field("books")
+				if !ok {
+					t.Fatal("Expected Query.books")
+				}
+				if field.Type.String() != "[Book!]!" {
+					t.Errorf("Expected [Book!]!, got %s", field.Type)
+				}
+				if len(schema.Types["Book"].Fields) != 5 {
+					t.Errorf("Expected 5 Book fields, got %d", len(schema.Types["Book"].Fields))
+				}
+			},
+		},
+		{
+			name: "enums and unions",
+			check: func(t *testing.T) {
+				if got := schema.Types["Genre"].Values; len(got) != 3 || got[2] != "HISTORY" {
+					t.Errorf("Expected 3 enum values, got %v", got)
+				}
+				if got := schema.Types["SearchResult"].Values; len(got) != 2 || got[1] != "Author" {
+					t.Errorf("Expected union members Book and Author, got %v", got)
+				}
+			},
+		},
+		{
+			name: "input defaults",
+			check: func(t *testing.T) {
+				if got := len(schema.Types["BookFilter"].Fields); got != 2 {
+					t.Errorf("Expected 2 input fields, got %d", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
+
+func TestParseGraphQLSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sdl  string
+	}{
+		{name: "no query type", sdl: `type Book { id: ID }`},
+		{name: "missing colon", sdl: `type Query { books [Book] }`},
+		{name: "unterminated string", sdl: `"oops
+type Query { id: ID }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseGraphQLSchema(tt.sdl); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestParseGraphQLQuery(t *testing.T) {
+	query := `
+query Shelf($first: Int = 5) {
+  latest: books(first: $first, filter: {genre: FICTION}) {
+    ...BookFields
+    authors { name }
+  }
+  search(term: "go") {
+    __typename
+    ... on Book { title }
+  }
+}
+
+mutation Add { addBook(title: "x") { id } }
+
+fragment BookFields on Book { id title }
+`
+
+	op, err := ParseGraphQLQuery(query, "")
+	if err != nil {
+		t.Fatalf("ParseGraphQLQuery() failed: %v", err)
+	}
+	if op.Type != "query" || op.Name != "Shelf" || len(op.Selections) != 2 {
+		t.Fatalf("Unexpected operation: %+v", op)
+	}
+
+	latest := op.Selections[0]
+	if latest.Alias != "latest" || latest.Name != "books" {
+		t.Errorf("Expected alias latest for books, got %+v", latest)
+	}
+	var names []string
+	for _, selection := range latest.Selections {
+		names = append(names, selection.Name)
+	}
+	if len(names) != 3 || names[0] != "id" || names[1] != "title" || names[2] != "authors" {
+		t.Errorf("Expected fragment fields expanded, got %v", names)
+	}
+	if got := op.Selections[1].Selections; len(got) != 2 || got[1].Name != "title" || got[1].On != "Book" || got[0].On != "" {
+		t.Errorf("Expected inline fragment fields expanded with their type condition, got %+v", got)
+	}
+	if latest.Selections[0].On != "Book" || latest.Selections[2].On != "" {
+		t.Errorf("Expected named fragment fields to carry their type condition, got %+v", latest.Selections)
+	}
+	if args := latest.Arguments; args["first"] != "$first" || len(args) != 1 {
+		t.Errorf("Expected the variable argument and the input object skipped, got %v", args)
+	}
+	if term := op.Selections[1].Arguments["term"]; term != "go" {
+		t.Errorf("Expected the string argument unquoted, got %q", term)
+	}
+
+	mutation, err := ParseGraphQLQuery(query, "Add")
+	if err != nil {
+		t.Fatalf("ParseGraphQLQuery() with operation name failed: %v", err)
+	}
+	if mutation.Type != "mutation" || mutation.Selections[0].Name != "addBook" {
+		t.Errorf("Expected addBook mutation, got %+v", mutation)
+	}
+
+	if _, err := ParseGraphQLQuery(query, "Missing"); err == nil {
+		t.Error("Expected error for unknown operation name")
+	}
+	if _, err := ParseGraphQLQuery(`{ ...Nope }`, ""); err == nil {
+		t.Error("Expected error for unknown fragment")
+	}
+
+	// Each level spreads the next twice: 2^30 fields if expanded in full
+	var fanOut strings.Builder
+	fanOut.WriteString("{ ...F0 }\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&fanOut, "fragment F%d on Query { ...F%d ...F%d }\n", i, i+1, i+1)
+	}
+	fanOut.WriteString("fragment F30 on Query { id }\n")
+	if _, err := ParseGraphQLQuery(fanOut.String(), ""); err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Errorf("Expected the expansion to be capped, got %v", err)
+	}
+}
+
+func TestGraphQLParser_Parse(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "library.graphql")
+	if err := os.WriteFile(testFile, []byte(librarySDL), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := NewGraphQLParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if schema.Type != "graphql" || schema.Title != "library" {
+		t.Errorf("Unexpected schema: %s %q", schema.Type, schema.Title)
+	}
+	endpoints := schema.Paths[GraphQLEndpointPath]
+	if len(endpoints) != 1 || endpoints[0].Method != "POST" {
+		t.Errorf("Expected a single POST %s endpoint, got %+v", GraphQLEndpointPath, endpoints)
+	}
+	if _, ok := schema.Raw.(*GraphQLSchema); !ok {
+		t.Errorf("Expected Raw to be *GraphQLSchema, got %T", schema.Raw)
+	}
+}
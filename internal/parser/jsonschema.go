@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadJSONSchema reads a standalone JSON Schema document (JSON or YAML) and returns it
+// as an OpenAPI schema. Local references into "definitions" or "$defs" are resolved by
+// hosting them as components of a synthetic OpenAPI document.
+func LoadJSONSchema(filepath string) (*openapi3.Schema, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON Schema: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("failed to parse JSON Schema: document is empty")
+	}
+
+	// Move local definitions into components and point references at them
+	components := make(map[string]interface{})
+	for _, key := range []string{"definitions", "$defs"} {
+		if defs, ok := root[key].(map[string]interface{}); ok {
+			for name, def := range defs {
+				components[name] = def
+			}
+			delete(root, key)
+		}
+	}
+	for _, key := range []string{"$schema", "$id"} {
+		delete(root, key)
+	}
+	rewriteLocalRefs(root)
+	for _, def := range components {
+		rewriteLocalRefs(def)
+	}
+	components["__root"] = root
+
+	wrapper := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": "JSON Schema", "version": "1.0.0"},
+		"paths":   map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": components,
+		},
+	}
+	wrapped, err := json.Marshal(wrapper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert JSON Schema: %w", err)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JSON Schema: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+
+	return doc.Components.Schemas["__root"].Value, nil
+}
+
+// rewriteLocalRefs points "#/definitions/X" and "#/$defs/X" references at "#/components/schemas/X"
+func rewriteLocalRefs(node interface{}) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			for _, prefix := range []string{"#/definitions/", "#/$defs/"} {
+				if strings.HasPrefix(ref, prefix) {
+					v["$ref"] = "#/components/schemas/" + strings.TrimPrefix(ref, prefix)
+				}
+			}
+		}
+		for _, child := range v {
+			rewriteLocalRefs(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			rewriteLocalRefs(child)
+		}
+	}
+}
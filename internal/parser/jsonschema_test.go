@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadJSONSchema(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "order.json")
+	content := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "customer"],
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "customer": {"$ref": "#/$defs/Customer"},
+    "items": {"type": "array", "items": {"$ref": "#/definitions/Item"}}
+  },
+  "$defs": {
+    "Customer": {"type": "object", "properties": {"email": {"type": "string", "format": "email"}}}
+  },
+  "definitions": {
+    "Item": {"type": "object", "properties": {"sku": {"type": "string"}}}
+  }
+}`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := LoadJSONSchema(testFile)
+	if err != nil {
+		t.Fatalf("LoadJSONSchema() failed: %v", err)
+	}
+
+	if len(schema.Required) != 2 {
+		t.Errorf("Expected 2 required fields, got %v", schema.Required)
+	}
+
+	customer := schema.Properties["customer"]
+	if customer == nil || customer.Value == nil {
+		t.Fatal("Expected $defs reference to be resolved")
+	}
+	if customer.Value.Properties["email"].Value.Format != "email" {
+		t.Error("Expected resolved customer schema to have an email property")
+	}
+
+	items := schema.Properties["items"].Value.Items
+	if items == nil || items.Value == nil || items.Value.Properties["sku"] == nil {
+		t.Error("Expected definitions reference to be resolved")
+	}
+}
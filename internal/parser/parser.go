@@ -2,10 +2,17 @@ package parser
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
 )
 
 // Parser defines the interface for schema parsers
@@ -20,15 +27,92 @@ type Schema struct {
 	Title   string                // API title
 	Paths   map[string][]Endpoint // Path -> methods
 	Raw     interface{}           // Original parsed object
+
+	// Warnings lists non-fatal problems found while parsing, such as
+	// operations that will mock an empty body
+	Warnings []string
+}
+
+// FindByOperationID returns the endpoint declared with the given operationId
+func (s *Schema) FindByOperationID(id string) (Endpoint, bool) {
+	for _, endpoints := range s.Paths {
+		for _, endpoint := range endpoints {
+			if id != "" && endpoint.OperationID == id {
+				return endpoint, true
+			}
+		}
+	}
+	return Endpoint{}, false
+}
+
+// FindEndpoint returns the endpoint serving a request: the one with the method whose
+// path template fits the URL path, preferring the template with the most static segments
+func (s *Schema) FindEndpoint(method, path string) (Endpoint, bool) {
+	var best Endpoint
+	bestScore := -1
+	for template, endpoints := range s.Paths {
+		score, ok := MatchPath(template, path)
+		if !ok || score <= bestScore {
+			continue
+		}
+		for _, endpoint := range endpoints {
+			if strings.EqualFold(endpoint.Method, method) {
+				best, bestScore = endpoint, score
+				break
+			}
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// MatchPath reports whether a URL path fits a path template and how specific the fit is:
+// the number of static segments matched. {name} matches one segment; a trailing
+// {name+} or {name...} matches one or more.
+func MatchPath(template, path string) (int, bool) {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	score := 0
+	for i, segment := range templateSegments {
+		wildcard := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+		if wildcard && i == len(templateSegments)-1 && (strings.HasSuffix(segment, "+}") || strings.HasSuffix(segment, "...}")) {
+			return score, len(pathSegments) > i && pathSegments[i] != ""
+		}
+		if i >= len(pathSegments) {
+			return 0, false
+		}
+		switch {
+		case wildcard:
+			if pathSegments[i] == "" {
+				return 0, false
+			}
+		case segment == pathSegments[i]:
+			score++
+		default:
+			return 0, false
+		}
+	}
+	return score, len(templateSegments) == len(pathSegments)
 }
 
 // Endpoint represents a single API endpoint
 type Endpoint struct {
 	Method      string
 	Path        string
+	OperationID string
 	Summary     string
 	Description string
 	Parameters  []Parameter
+	Deprecated  bool
+
+	// Resolved JSON request body schema with allOf compositions flattened; nil when
+	// the operation declares none
+	RequestSchema *openapi3.Schema
+
+	// Overrides declared with x-mocktail-* operation extensions
+	Status  int           // x-mocktail-status; 0 means the method's default
+	Latency time.Duration // x-mocktail-latency
+	Example interface{}   // x-mocktail-example; nil means generate from the schema
 }
 
 // Parameter represents an API parameter
@@ -39,14 +123,33 @@ type Parameter struct {
 	Type     string
 }
 
+// ParseOptions tunes how schemas are loaded
+type ParseOptions struct {
+	// SkipValidation downgrades spec validation failures to an entry of
+	// Schema.Warnings, for mocking slightly non-conformant specs that still load
+	SkipValidation bool
+
+	// CacheDir, when set, stores validated documents keyed by file path, mtime,
+	// and content hash, along with the hashes of the files their $refs read, so
+	// repeated parses of an unchanged spec skip validation
+	CacheDir string
+}
+
 // OpenAPIParser implements Parser for OpenAPI 3.x specifications
-type OpenAPIParser struct{}
+type OpenAPIParser struct {
+	opts ParseOptions
+}
 
 // NewOpenAPIParser creates a new OpenAPI parser
 func NewOpenAPIParser() *OpenAPIParser {
 	return &OpenAPIParser{}
 }
 
+// NewOpenAPIParserWithOptions creates a new OpenAPI parser with custom options
+func NewOpenAPIParserWithOptions(opts ParseOptions) *OpenAPIParser {
+	return &OpenAPIParser{opts: opts}
+}
+
 // Parse reads and parses an OpenAPI 3.x specification file
 func (p *OpenAPIParser) Parse(filepath string) (*Schema, error) {
 	// Read the file
@@ -55,21 +158,64 @@ func (p *OpenAPIParser) Parse(filepath string) (*Schema, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Parse the OpenAPI document
+	cacheKey := ""
+	if p.opts.CacheDir != "" {
+		cacheKey, err = schemaCacheKey(filepath, data)
+		if err == nil {
+			if doc, warnings := loadCachedDoc(p.opts.CacheDir, cacheKey, filepath); doc != nil {
+				schema := buildSchema(doc)
+				schema.Warnings = append(schema.Warnings, warnings...)
+				return schema, nil
+			}
+		}
+	}
+
+	// OpenAPI 3.1 constructs kin-openapi doesn't understand must be rewritten before loading
+	data, unsupported, err := normalizeOpenAPI31(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	// Parse the OpenAPI document, resolving relative external $refs against the
+	// spec's own location so split specs load their sibling files
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
+	files := newFileRecorder()
+	loader.ReadFromURIFunc = files.read
 
-	doc, err := loader.LoadFromData(data)
+	doc, err := loader.LoadFromDataWithPath(data, &url.URL{Path: filepath})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
 	}
 
-	// Validate the document
+	// Move references to sibling files into components now, while the document is
+	// still private, so it is self-contained and Bundle never has to modify it
 	ctx := context.Background()
+	doc.InternalizeRefs(ctx, nil)
+
+	var warnings []string
+	for _, keyword := range unsupported {
+		warnings = append(warnings, "ignored unsupported OpenAPI 3.1 keyword "+keyword)
+	}
+
+	// Validate the document
 	if err := doc.Validate(ctx); err != nil {
-		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+		if !p.opts.SkipValidation {
+			return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+		}
+		warnings = append(warnings, fmt.Sprintf("ignoring invalid OpenAPI spec (validation disabled): %v", err))
+	} else if cacheKey != "" {
+		// Only documents that passed validation are cached
+		storeCachedDoc(p.opts.CacheDir, cacheKey, doc, warnings, files)
 	}
 
+	schema := buildSchema(doc)
+	schema.Warnings = append(schema.Warnings, warnings...)
+	return schema, nil
+}
+
+// buildSchema converts a loaded OpenAPI document to our Schema format
+func buildSchema(doc *openapi3.T) *Schema {
 	// Convert to our Schema format
 	schema := &Schema{
 		Type:    "openapi",
@@ -87,11 +233,22 @@ func (p *OpenAPIParser) Parse(filepath string) (*Schema, error) {
 			endpoint := Endpoint{
 				Method:      method,
 				Path:        path,
+				OperationID: operation.OperationID,
 				Summary:     operation.Summary,
 				Description: operation.Description,
 				Parameters:  extractParameters(operation),
+				Deprecated:  operation.Deprecated,
+			}
+			if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+				endpoint.RequestSchema = MergeAllOf(jsonContentSchema(operation.RequestBody.Value.Content))
 			}
+			schema.Warnings = append(schema.Warnings, applyExtensions(&endpoint, operation)...)
 			endpoints = append(endpoints, endpoint)
+
+			if !hasJSONResponseSchema(operation) {
+				schema.Warnings = append(schema.Warnings,
+					fmt.Sprintf("%s %s has no JSON response schema; mock responses will be empty", method, path))
+			}
 		}
 
 		if len(endpoints) > 0 {
@@ -99,7 +256,25 @@ func (p *OpenAPIParser) Parse(filepath string) (*Schema, error) {
 		}
 	}
 
-	return schema, nil
+	sort.Strings(schema.Warnings)
+
+	return schema
+}
+
+// hasJSONResponseSchema reports whether any declared response carries an application/json schema
+func hasJSONResponseSchema(operation *openapi3.Operation) bool {
+	if operation.Responses == nil {
+		return false
+	}
+	for _, responseRef := range operation.Responses.Map() {
+		if responseRef == nil || responseRef.Value == nil {
+			continue
+		}
+		if content := responseRef.Value.Content.Get("application/json"); content != nil && content.Schema != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // extractParameters converts OpenAPI parameters to our simplified format
@@ -117,9 +292,15 @@ func extractParameters(operation *openapi3.Operation) []Parameter {
 			Required: paramRef.Value.Required,
 		}
 
-		// Extract type from schema if available
+		// Extract type from schema if available; of an OpenAPI 3.1 type list such as
+		// ["integer", "null"], the first non-null type is kept
 		if paramRef.Value.Schema != nil && paramRef.Value.Schema.Value != nil {
-			param.Type = paramRef.Value.Schema.Value.Type.Slice()[0]
+			for _, t := range paramRef.Value.Schema.Value.Type.Slice() {
+				if t != openapi3.TypeNull {
+					param.Type = t
+					break
+				}
+			}
 		}
 
 		params = append(params, param)
@@ -127,3 +308,291 @@ func extractParameters(operation *openapi3.Operation) []Parameter {
 
 	return params
 }
+
+// unsupportedKeywords are OpenAPI 3.1 (JSON Schema 2020-12) keywords kin-openapi can't
+// represent; they are removed before loading and reported as warnings with their location
+var unsupportedKeywords = []string{
+	"$dynamicRef", "$dynamicAnchor", "unevaluatedProperties",
+	"unevaluatedItems", "dependentSchemas", "contentSchema",
+	// Left over only on tuples, which rewriteContains skips
+	"contains", "minContains", "maxContains",
+}
+
+// normalizeOpenAPI31 rewrites OpenAPI 3.1 constructs into the 3.0 forms understood by
+// kin-openapi: numeric exclusiveMinimum/exclusiveMaximum become minimum/maximum plus a
+// boolean flag, type lists with "null" become the remaining type plus nullable, and
+// prefixItems tuples and contains become items branches (see rewritePrefixItems and
+// rewriteContains). Rewritten schemas remember what they declared under
+// originalExtension, which Bundle uses to serve the spec unchanged.
+// Unsupported keywords are stripped and returned as "keyword at /json/pointer".
+// Documents declaring any other version are returned unchanged.
+func normalizeOpenAPI31(data []byte) ([]byte, []string, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	// Unquoted status codes such as 200: decode as int keys; the walk only follows
+	// string-keyed maps, so they would hide every schema beneath them
+	root, ok := stringKeys(doc).(map[string]interface{})
+	if !ok {
+		return data, nil, nil
+	}
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.1") {
+		return data, nil, nil
+	}
+
+	walkSchemas(root, "", rewriteExclusiveBounds)
+	walkSchemas(root, "", rewriteNullableTypes)
+	walkSchemas(root, "", rewriteContains)
+	walkSchemas(root, "", rewritePrefixItems)
+	var unsupported []string
+	walkSchemas(root, "", func(schema map[string]interface{}, pointer string) {
+		unsupported = append(unsupported, stripUnsupportedKeywords(schema, pointer)...)
+	})
+	sort.Strings(unsupported)
+
+	data, err := json.Marshal(root)
+	return data, unsupported, err
+}
+
+// rewriteNullableTypes turns type lists such as ["string", "null"] into type: string
+// with nullable: true
+func rewriteNullableTypes(schema map[string]interface{}, _ string) {
+	types, ok := schema["type"].([]interface{})
+	if !ok {
+		return
+	}
+	var remaining []interface{}
+	for _, t := range types {
+		if t != "null" {
+			remaining = append(remaining, t)
+		}
+	}
+	if len(remaining) == 0 || len(remaining) == len(types) {
+		return
+	}
+
+	remember(schema, "type", "nullable")
+	schema["nullable"] = true
+	if len(remaining) == 1 {
+		schema["type"] = remaining[0]
+	} else {
+		schema["type"] = remaining
+	}
+}
+
+// ContainsExtension records an array's minContains once rewriteContains has moved its
+// contains schema into items; MaxContainsExtension records its maxContains
+const (
+	ContainsExtension    = "x-mocktail-contains"
+	MaxContainsExtension = "x-mocktail-max-contains"
+)
+
+// rewriteContains turns contains, which kin-openapi can't represent, into
+// items: {anyOf: [items, {allOf: [items, contains]}]} plus ContainsExtension holding
+// minContains (default 1) and, when set, MaxContainsExtension. Validation still holds
+// every item to items, and the generator draws contains items from the allOf branch.
+// Tuples are left alone.
+func rewriteContains(schema map[string]interface{}, _ string) {
+	contains, ok := schema["contains"].(map[string]interface{})
+	if _, isTuple := schema["prefixItems"]; !ok || isTuple {
+		return
+	}
+	remember(schema, "contains", "minContains", "maxContains", "items", ContainsExtension, MaxContainsExtension)
+
+	items, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		items = map[string]interface{}{}
+	}
+	minContains, ok := toFloat(schema["minContains"])
+	if !ok {
+		minContains = 1
+	}
+	schema["items"] = map[string]interface{}{"anyOf": []interface{}{
+		items,
+		map[string]interface{}{"allOf": []interface{}{items, contains}},
+	}}
+	schema[ContainsExtension] = minContains
+	if maxContains, ok := toFloat(schema["maxContains"]); ok {
+		schema[MaxContainsExtension] = maxContains
+	}
+	delete(schema, "contains")
+	delete(schema, "minContains")
+	delete(schema, "maxContains")
+}
+
+// PrefixItemsExtension records how many leading items branches are tuple positions;
+// the generator reads it to generate each position from its own schema
+const PrefixItemsExtension = "x-mocktail-prefix-items"
+
+// rewritePrefixItems turns a prefixItems tuple, which kin-openapi can't represent, into
+// items: {anyOf: [position schemas..., items]} plus PrefixItemsExtension. Validation
+// then accepts any tuple schema at any position; items: false, which closes the tuple,
+// becomes maxItems.
+func rewritePrefixItems(schema map[string]interface{}, _ string) {
+	prefix, ok := schema["prefixItems"].([]interface{})
+	if !ok || len(prefix) == 0 {
+		return
+	}
+	remember(schema, "prefixItems", "items", "maxItems", PrefixItemsExtension)
+
+	branches := append([]interface{}(nil), prefix...)
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		branches = append(branches, items)
+	case bool:
+		if !items {
+			if max, ok := toFloat(schema["maxItems"]); !ok || max > float64(len(prefix)) {
+				schema["maxItems"] = len(prefix)
+			}
+		}
+	}
+	schema["items"] = map[string]interface{}{"anyOf": branches}
+	schema[PrefixItemsExtension] = len(prefix)
+	delete(schema, "prefixItems")
+}
+
+// stripUnsupportedKeywords removes unsupportedKeywords from a schema and returns where
+// each was found
+func stripUnsupportedKeywords(schema map[string]interface{}, pointer string) []string {
+	var found []string
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := schema[keyword]; ok {
+			found = append(found, fmt.Sprintf("%s at %s", keyword, orRoot(pointer)))
+			remember(schema, keyword)
+			delete(schema, keyword)
+		}
+	}
+	return found
+}
+
+// escapePointer escapes a key for use as a JSON pointer segment
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// orRoot returns pointer, or "/" for the document root
+func orRoot(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+	return pointer
+}
+
+// rewriteExclusiveBounds converts numeric exclusive bounds into minimum/maximum plus a
+// boolean flag. A schema may declare both kinds of bound; whichever is tighter is kept.
+func rewriteExclusiveBounds(schema map[string]interface{}, _ string) {
+	for _, bound := range []struct {
+		exclusive, inclusive string
+		lower                bool
+	}{
+		{exclusive: "exclusiveMinimum", inclusive: "minimum", lower: true},
+		{exclusive: "exclusiveMaximum", inclusive: "maximum", lower: false},
+	} {
+		exclusive, ok := toFloat(schema[bound.exclusive])
+		if !ok {
+			continue
+		}
+		remember(schema, bound.exclusive, bound.inclusive)
+
+		// x >= inclusive only wins over x > exclusive when it lies strictly inside
+		inclusive, hasInclusive := toFloat(schema[bound.inclusive])
+		if hasInclusive && ((bound.lower && inclusive > exclusive) || (!bound.lower && inclusive < exclusive)) {
+			delete(schema, bound.exclusive)
+			continue
+		}
+		schema[bound.inclusive] = exclusive
+		schema[bound.exclusive] = true
+	}
+}
+
+// originalExtension is added to every schema normalizeOpenAPI31 rewrites. It maps the
+// rewritten keywords to the values the spec declared, null for keywords it didn't
+// declare, so Bundle can serve the document as written.
+const originalExtension = "x-mocktail-original"
+
+// remember records keywords of a schema as declared, before a rewrite first changes them
+func remember(schema map[string]interface{}, keywords ...string) {
+	original, ok := schema[originalExtension].(map[string]interface{})
+	if !ok {
+		original = make(map[string]interface{})
+		schema[originalExtension] = original
+	}
+	for _, keyword := range keywords {
+		if _, recorded := original[keyword]; !recorded {
+			original[keyword] = schema[keyword]
+		}
+	}
+}
+
+// literalKeywords hold values rather than schemas, so rewrites never look inside them
+var literalKeywords = []string{"example", "examples", "default", "enum", "const"}
+
+// nameMapKeywords map names to schemas or other objects; their keys are never keywords
+var nameMapKeywords = []string{"properties", "patternProperties", "$defs", "schemas", "dependentSchemas", "responses"}
+
+// walkSchemas calls visit on every object of a decoded document, parents before their
+// children, with its JSON pointer. Literal values such as examples and extensions are
+// skipped, and the keys of name maps such as properties are not mistaken for keywords.
+func walkSchemas(node interface{}, pointer string, visit func(object map[string]interface{}, pointer string)) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		visit(v, pointer)
+		for key, child := range v {
+			if slices.Contains(literalKeywords, key) || strings.HasPrefix(key, "x-") {
+				continue
+			}
+			path := pointer + "/" + escapePointer(key)
+			if names, ok := child.(map[string]interface{}); ok && slices.Contains(nameMapKeywords, key) {
+				for name, value := range names {
+					walkSchemas(value, path+"/"+escapePointer(name), visit)
+				}
+				continue
+			}
+			walkSchemas(child, path, visit)
+		}
+	case []interface{}:
+		for i, child := range v {
+			walkSchemas(child, fmt.Sprintf("%s/%d", pointer, i), visit)
+		}
+	}
+}
+
+// stringKeys converts the maps YAML decodes with non-string keys, such as an unquoted
+// 200: response code, into string-keyed maps, recursively
+func stringKeys(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			converted[fmt.Sprint(key)] = stringKeys(child)
+		}
+		return converted
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = stringKeys(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = stringKeys(child)
+		}
+	}
+	return node
+}
+
+// toFloat reports whether a decoded YAML value is numeric and returns it as float64
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
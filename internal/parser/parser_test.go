@@ -3,7 +3,12 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 func TestOpenAPIParser_Parse(t *testing.T) {
@@ -38,6 +43,8 @@ paths:
   /users/{id}:
     get:
       summary: Get user by ID
+      operationId: getUserById
+      deprecated: true
       parameters:
         - name: id
           in: path
@@ -115,6 +122,396 @@ paths:
 	if limitParam.Type != "integer" {
 		t.Errorf("Expected parameter type 'integer', got '%s'", limitParam.Type)
 	}
+
+	if usersEndpoints[0].Deprecated {
+		t.Error("Expected GET /users not to be deprecated")
+	}
+	if id := schema.Paths["/users/{id}"][0].OperationID; id != "getUserById" {
+		t.Errorf("Expected operationId getUserById, got %q", id)
+	}
+	if !schema.Paths["/users/{id}"][0].Deprecated {
+		t.Error("Expected GET /users/{id} to be deprecated")
+	}
+
+	// GET /users/{id} declares a response without content
+	expectedWarning := "GET /users/{id} has no JSON response schema; mock responses will be empty"
+	if len(schema.Warnings) != 1 || schema.Warnings[0] != expectedWarning {
+		t.Errorf("Expected warning %q, got %v", expectedWarning, schema.Warnings)
+	}
+}
+
+func TestSchema_FindByOperationID(t *testing.T) {
+	schema := &Schema{Paths: map[string][]Endpoint{
+		"/users": {
+			{Method: "GET", Path: "/users", OperationID: "listUsers"},
+			{Method: "POST", Path: "/users"},
+		},
+		"/users/{id}": {{Method: "GET", Path: "/users/{id}", OperationID: "getUserById"}},
+	}}
+
+	tests := []struct {
+		id       string
+		found    bool
+		expected string
+	}{
+		{id: "getUserById", found: true, expected: "GET /users/{id}"},
+		{id: "listUsers", found: true, expected: "GET /users"},
+		{id: "deleteUser", found: false},
+		{id: "", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			endpoint, found := schema.FindByOperationID(tt.id)
+			if found != tt.found {
+				t.Fatalf("Expected found=%v, got %v", tt.found, found)
+			}
+			if got := endpoint.Method + " " + endpoint.Path; found && got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOpenAPIParser_ParseSplitSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "schemas"), 0755); err != nil {
+		t.Fatalf("Failed to create schemas dir: %v", err)
+	}
+
+	files := map[string]string{
+		"api.yaml": `openapi: 3.0.0
+info:
+  title: Split API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: './schemas/user.yaml'
+`,
+		"schemas/user.yaml": `type: object
+required:
+  - email
+properties:
+  email:
+    type: string
+    format: email
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts ParseOptions
+	}{
+		{name: "uncached"},
+		{name: "cached", opts: ParseOptions{CacheDir: filepath.Join(tmpDir, "cache")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Parse twice so the cached case also reads its entry back
+			for i := 0; i < 2; i++ {
+				schema, err := NewOpenAPIParserWithOptions(tt.opts).Parse(filepath.Join(tmpDir, "api.yaml"))
+				if err != nil {
+					t.Fatalf("Parse() failed: %v", err)
+				}
+
+				doc := schema.Raw.(*openapi3.T)
+				response := doc.Paths.Value("/users/{id}").Get.Responses.Status(200).Value
+				user := response.Content.Get("application/json").Schema.Value
+				if user == nil || user.Properties["email"] == nil {
+					t.Fatalf("Expected the sibling user schema to resolve, got %+v", user)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIParser_ParseTypeArrays(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "types.yaml")
+
+	spec := `openapi: 3.1.0
+info:
+  title: Search API
+  version: 1.0.0
+paths:
+  /search:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: ["null", "integer"]
+        - name: q
+          in: query
+          schema: {}
+      responses:
+        '200':
+          description: OK
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := NewOpenAPIParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	params := schema.Paths["/search"][0].Parameters
+	if len(params) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(params))
+	}
+	if params[0].Type != "integer" {
+		t.Errorf("Expected the first non-null type 'integer', got %q", params[0].Type)
+	}
+	if params[1].Type != "" {
+		t.Errorf("Expected no type for an untyped schema, got %q", params[1].Type)
+	}
+}
+
+func TestOpenAPIParser_ParseNullableTypeUnquotedStatus(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "nullable.yaml")
+
+	// YAML decodes the unquoted 200 as an integer key
+	spec := `openapi: 3.1.0
+info:
+  title: Profile API
+  version: 1.0.0
+paths:
+  /profile:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: [object, "null"]
+                properties:
+                  name:
+                    type: string
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := NewOpenAPIParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	profile := okResponseSchema(schema, "/profile")
+	if !profile.Type.Is("object") || !profile.Nullable {
+		t.Errorf("Expected a nullable object, got type %v nullable %v", profile.Type, profile.Nullable)
+	}
+}
+
+func TestOpenAPIParser_ParsePrefixItems(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "tuples.yaml")
+
+	spec := `openapi: 3.1.0
+info:
+  title: Map API
+  version: 1.0.0
+paths:
+  /route:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  origin:
+                    type: array
+                    prefixItems:
+                      - type: string
+                      - type: integer
+                    items: false
+                  path:
+                    type: array
+                    prefixItems:
+                      - $ref: '#/components/schemas/Label'
+                    items:
+                      type: number
+components:
+  schemas:
+    Label:
+      type: string
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := NewOpenAPIParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	properties := okResponseSchema(schema, "/route").Properties
+
+	origin := properties["origin"].Value
+	if origin.Extensions[PrefixItemsExtension] != float64(2) || len(origin.Items.Value.AnyOf) != 2 {
+		t.Fatalf("Expected a 2-position tuple, got %v with %d branches", origin.Extensions, len(origin.Items.Value.AnyOf))
+	}
+	if origin.MaxItems == nil || *origin.MaxItems != 2 {
+		t.Errorf("Expected items: false to close the tuple at 2 items, got %v", origin.MaxItems)
+	}
+	if err := origin.VisitJSON([]interface{}{"home", float64(3)}); err != nil {
+		t.Errorf("Expected a matching tuple to validate, got %v", err)
+	}
+	if err := origin.VisitJSON([]interface{}{"home", float64(3), "extra"}); err == nil {
+		t.Error("Expected a closed tuple to reject extra items")
+	}
+
+	path := properties["path"].Value
+	branches := path.Items.Value.AnyOf
+	if path.Extensions[PrefixItemsExtension] != float64(1) || len(branches) != 2 {
+		t.Fatalf("Expected 1 position plus additional items, got %v with %d branches", path.Extensions, len(branches))
+	}
+	if !branches[0].Value.Type.Is("string") || !branches[1].Value.Type.Is("number") {
+		t.Errorf("Expected the $ref position to resolve and items to follow it, got %v, %v", branches[0].Value.Type, branches[1].Value.Type)
+	}
+}
+
+func TestOpenAPIParser_ParseContains(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "contains.yaml")
+
+	spec := `openapi: 3.1.0
+info:
+  title: Roles API
+  version: 1.0.0
+paths:
+  /roles:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  roles:
+                    type: array
+                    items:
+                      type: string
+                    contains:
+                      $ref: '#/components/schemas/Admin'
+                    minContains: 2
+                    maxContains: 3
+                  tags:
+                    type: array
+                    contains:
+                      type: string
+components:
+  schemas:
+    Admin:
+      type: string
+      enum: [admin]
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := NewOpenAPIParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	properties := okResponseSchema(schema, "/roles").Properties
+
+	roles := properties["roles"].Value
+	branches := roles.Items.Value.AnyOf
+	if roles.Extensions[ContainsExtension] != float64(2) || roles.Extensions[MaxContainsExtension] != float64(3) || len(branches) != 2 {
+		t.Fatalf("Expected minContains 2 and maxContains 3 with 2 branches, got %v with %d branches", roles.Extensions, len(branches))
+	}
+	if !branches[0].Value.Type.Is("string") || len(branches[1].Value.AllOf) != 2 {
+		t.Fatalf("Expected items then items and contains combined, got %v, %v", branches[0].Value, branches[1].Value)
+	}
+	if combined := branches[1].Value.AllOf; !combined[0].Value.Type.Is("string") || len(combined[1].Value.Enum) != 1 {
+		t.Errorf("Expected items and the resolved contains schema, got %v, %v", combined[0].Value, combined[1].Value)
+	}
+
+	tags := properties["tags"].Value
+	if tags.Extensions[ContainsExtension] != float64(1) {
+		t.Errorf("Expected minContains to default to 1, got %v", tags.Extensions[ContainsExtension])
+	}
+	if _, ok := tags.Extensions[MaxContainsExtension]; ok {
+		t.Error("Expected no maxContains when the spec sets none")
+	}
+}
+
+func TestOpenAPIParser_ParseUnsupportedKeywords(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "tree.yaml")
+
+	spec := `openapi: 3.1.0
+info:
+  title: Tree API
+  version: 1.0.0
+paths:
+  /nodes:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  children:
+                    $dynamicRef: '#node'
+                  prefixItems:
+                    type: string
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	location := "$dynamicRef at /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children"
+
+	schema, err := NewOpenAPIParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Expected unsupported keywords to be stripped, got %v", err)
+	}
+	if !slices.Contains(schema.Warnings, "ignored unsupported OpenAPI 3.1 keyword "+location) {
+		t.Errorf("Expected a warning for the ignored keyword, got %v", schema.Warnings)
+	}
+	for _, warning := range schema.Warnings {
+		if strings.Contains(warning, "prefixItems at") {
+			t.Errorf("Expected a property named prefixItems not to be flagged, got %v", warning)
+		}
+	}
+
+	// The stripped keyword is still served as written
+	data, _, err := Bundle(schema.Raw.(*openapi3.T), false)
+	if err != nil {
+		t.Fatalf("Bundle() failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"$dynamicRef": "#node"`) {
+		t.Errorf("Expected $dynamicRef in the bundled spec:\n%s", data)
+	}
 }
 
 func TestOpenAPIParser_ParseInvalidFile(t *testing.T) {
@@ -146,3 +543,306 @@ missing: openapi
 		t.Error("Expected error for invalid OpenAPI spec, got nil")
 	}
 }
+
+func TestOpenAPIParser_ParseExclusiveBounds(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		status       string // response key as written; '200' when empty
+		bounds       string
+		min, max     float64
+		exclusiveMin bool
+		exclusiveMax bool
+	}{
+		{
+			name:    "OpenAPI 3.0 boolean style",
+			version: "3.0.3",
+			bounds: `minimum: 5
+                    exclusiveMinimum: true
+                    maximum: 10
+                    exclusiveMaximum: true`,
+			min: 5, max: 10, exclusiveMin: true, exclusiveMax: true,
+		},
+		{
+			name:    "OpenAPI 3.1 numeric style",
+			version: "3.1.0",
+			bounds: `exclusiveMinimum: 5
+                    exclusiveMaximum: 10`,
+			min: 5, max: 10, exclusiveMin: true, exclusiveMax: true,
+		},
+		{
+			name:    "OpenAPI 3.1 exclusive bounds tighter",
+			version: "3.1.0",
+			bounds: `minimum: 2
+                    exclusiveMinimum: 5
+                    maximum: 20
+                    exclusiveMaximum: 10`,
+			min: 5, max: 10, exclusiveMin: true, exclusiveMax: true,
+		},
+		{
+			name:    "OpenAPI 3.1 under an unquoted status code",
+			version: "3.1.0",
+			status:  "200",
+			bounds: `exclusiveMinimum: 5
+                    exclusiveMaximum: 10`,
+			min: 5, max: 10, exclusiveMin: true, exclusiveMax: true,
+		},
+		{
+			name:    "OpenAPI 3.1 inclusive bounds tighter",
+			version: "3.1.0",
+			bounds: `minimum: 7
+                    exclusiveMinimum: 5
+                    maximum: 8
+                    exclusiveMaximum: 10`,
+			min: 7, max: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			if status == "" {
+				status = "'200'"
+			}
+			testFile := filepath.Join(t.TempDir(), "bounds.yaml")
+			spec := `openapi: ` + tt.version + `
+info:
+  title: Bounds API
+  version: 1.0.0
+paths:
+  /scores:
+    get:
+      responses:
+        ` + status + `:
+          description: Successful response
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  score:
+                    type: integer
+                    ` + tt.bounds + `
+`
+			if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			schema, err := NewOpenAPIParser().Parse(testFile)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			doc := schema.Raw.(*openapi3.T)
+			score := doc.Paths.Value("/scores").Get.Responses.Status(200).Value.
+				Content.Get("application/json").Schema.Value.Properties["score"].Value
+
+			if score.Min == nil || *score.Min != tt.min || score.ExclusiveMin != tt.exclusiveMin {
+				t.Errorf("Expected minimum %v (exclusive %v), got min=%v exclusive=%v", tt.min, tt.exclusiveMin, score.Min, score.ExclusiveMin)
+			}
+			if score.Max == nil || *score.Max != tt.max || score.ExclusiveMax != tt.exclusiveMax {
+				t.Errorf("Expected maximum %v (exclusive %v), got max=%v exclusive=%v", tt.max, tt.exclusiveMax, score.Max, score.ExclusiveMax)
+			}
+		})
+	}
+}
+
+func TestOpenAPIParser_ParseSkipValidation(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "loose.yaml")
+
+	// Loadable, but invalid: the path parameter is never declared
+	spec := `openapi: 3.0.0
+info:
+  title: Loose API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      responses:
+        '200':
+          description: Successful response
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if _, err := NewOpenAPIParser().Parse(testFile); err == nil {
+		t.Fatal("Expected validation error with validation enabled")
+	}
+
+	schema, err := NewOpenAPIParserWithOptions(ParseOptions{SkipValidation: true}).Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() with validation disabled failed: %v", err)
+	}
+	if _, ok := schema.Paths["/users/{id}"]; !ok {
+		t.Error("Expected /users/{id} to be parsed")
+	}
+	// The failure is reported through the warnings callers log, not printed directly
+	if !slices.ContainsFunc(schema.Warnings, func(w string) bool { return strings.Contains(w, "invalid OpenAPI spec") }) {
+		t.Errorf("Expected the validation failure as a warning, got %v", schema.Warnings)
+	}
+}
+
+func TestOpenAPIParser_ParseExtensions(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "extensions.yaml")
+
+	spec := `openapi: 3.0.0
+info:
+  title: Jobs API
+  version: 1.0.0
+paths:
+  /jobs:
+    post:
+      x-mocktail-status: 202
+      x-mocktail-latency: 250ms
+      x-mocktail-example:
+        id: job-1
+        state: queued
+      responses:
+        '202':
+          description: Accepted
+    get:
+      x-mocktail-status: teapot
+      x-mocktail-latency: 40
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+`
+	if err := os.WriteFile(testFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	schema, err := NewOpenAPIParser().Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	endpoints := make(map[string]Endpoint)
+	for _, endpoint := range schema.Paths["/jobs"] {
+		endpoints[endpoint.Method] = endpoint
+	}
+
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{
+			name: "status",
+			check: func(t *testing.T) {
+				if endpoints["POST"].Status != 202 {
+					t.Errorf("Expected status 202, got %d", endpoints["POST"].Status)
+				}
+			},
+		},
+		{
+			name: "latency",
+			check: func(t *testing.T) {
+				if endpoints["POST"].Latency != 250*time.Millisecond {
+					t.Errorf("Expected 250ms latency, got %v", endpoints["POST"].Latency)
+				}
+				if endpoints["GET"].Latency != 40*time.Millisecond {
+					t.Errorf("Expected numeric latency in milliseconds, got %v", endpoints["GET"].Latency)
+				}
+			},
+		},
+		{
+			name: "example",
+			check: func(t *testing.T) {
+				example, ok := endpoints["POST"].Example.(map[string]interface{})
+				if !ok || example["state"] != "queued" {
+					t.Errorf("Expected example body, got %v", endpoints["POST"].Example)
+				}
+			},
+		},
+		{
+			name: "invalid value",
+			check: func(t *testing.T) {
+				if endpoints["GET"].Status != 0 {
+					t.Errorf("Expected invalid status to be ignored, got %d", endpoints["GET"].Status)
+				}
+				found := false
+				for _, warning := range schema.Warnings {
+					if strings.Contains(warning, "GET /jobs has an invalid x-mocktail-status") {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("Expected a warning for the invalid status, got %v", schema.Warnings)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		score    int
+		ok       bool
+	}{
+		{template: "/pets", path: "/pets", score: 1, ok: true},
+		{template: "/pets", path: "/pets/", score: 1, ok: true},
+		{template: "/pets/{petId}", path: "/pets/42", score: 1, ok: true},
+		{template: "/pets/{petId}", path: "/pets", ok: false},
+		{template: "/pets/{petId}", path: "/pets/42/toys", ok: false},
+		{template: "/pets/mine", path: "/pets/mine", score: 2, ok: true},
+		{template: "/files/{path+}", path: "/files/a/b/c", score: 1, ok: true},
+		{template: "/files/{path...}", path: "/files/a", score: 1, ok: true},
+		{template: "/files/{path+}", path: "/files", ok: false},
+		{template: "/users", path: "/pets", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.path, func(t *testing.T) {
+			score, ok := MatchPath(tt.template, tt.path)
+			if ok != tt.ok {
+				t.Fatalf("Expected match %v, got %v", tt.ok, ok)
+			}
+			if ok && score != tt.score {
+				t.Errorf("Expected score %d, got %d", tt.score, score)
+			}
+		})
+	}
+}
+
+func TestFindEndpoint(t *testing.T) {
+	schema := &Schema{Paths: map[string][]Endpoint{
+		"/pets":         {{Method: "GET", Path: "/pets"}, {Method: "POST", Path: "/pets"}},
+		"/pets/{petId}": {{Method: "GET", Path: "/pets/{petId}"}},
+		"/pets/mine":    {{Method: "GET", Path: "/pets/mine"}},
+	}}
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: "post", path: "/pets", expected: "/pets"},
+		{method: "GET", path: "/pets/42", expected: "/pets/{petId}"},
+		{method: "GET", path: "/pets/mine", expected: "/pets/mine"},
+		{method: "DELETE", path: "/pets/42"},
+		{method: "GET", path: "/owners"},
+	}
+
+	for _, tt := range tests {
+		endpoint, ok := schema.FindEndpoint(tt.method, tt.path)
+		if ok != (tt.expected != "") || endpoint.Path != tt.expected {
+			t.Errorf("FindEndpoint(%s, %s) = %q, %v; expected %q", tt.method, tt.path, endpoint.Path, ok, tt.expected)
+		}
+	}
+}
+
+// okResponseSchema returns the application/json schema of the GET 200 response at path
+func okResponseSchema(schema *Schema, path string) *openapi3.Schema {
+	operation := schema.Raw.(*openapi3.T).Paths.Find(path).Get
+	return operation.Responses.Value("200").Value.Content.Get("application/json").Schema.Value
+}
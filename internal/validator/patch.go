@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	// MergePatchMediaType is an RFC 7386 JSON Merge Patch: a partial resource where
+	// null removes a field
+	MergePatchMediaType = "application/merge-patch+json"
+
+	// JSONPatchMediaType is an RFC 6902 JSON Patch: an array of operations
+	JSONPatchMediaType = "application/json-patch+json"
+)
+
+// jsonPatchOps lists the RFC 6902 operations and whether each needs a value or a from
+var jsonPatchOps = map[string]struct{ value, from bool }{
+	"add":     {value: true},
+	"remove":  {},
+	"replace": {value: true},
+	"move":    {from: true},
+	"copy":    {from: true},
+	"test":    {value: true},
+}
+
+// ValidateRequest checks a raw request body against the operation's request schema,
+// following the body's Content-Type: a JSON Patch is checked as an array of patch
+// operations, a JSON Merge Patch against the resource schema with every field optional,
+// and anything else as a plain JSON body.
+func ValidateRequest(operation *openapi3.Operation, contentType string, body []byte) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case JSONPatchMediaType:
+		if operation == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+			return nil
+		}
+		return ValidateJSONPatch(body)
+	case MergePatchMediaType:
+		return ValidateMergePatch(mergePatchSchema(operation), body)
+	default:
+		return ValidateRequestBody(operation, body)
+	}
+}
+
+// ValidateJSONPatch checks that a body is a well-formed JSON Patch document: an array of
+// operations, each with a known op, a JSON Pointer path, and the value or from its op needs
+func ValidateJSONPatch(body []byte) error {
+	var ops []map[string]interface{}
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return fmt.Errorf("request body is not a JSON Patch array: %w", err)
+	}
+
+	var fieldErrors []FieldError
+	for i, op := range ops {
+		field := strconv.Itoa(i)
+		name, _ := op["op"].(string)
+		spec, known := jsonPatchOps[name]
+		if !known {
+			fieldErrors = append(fieldErrors, FieldError{Field: field + ".op", Message: fmt.Sprintf("unknown patch operation %q", name)})
+			continue
+		}
+		if path, ok := op["path"].(string); !ok || !isJSONPointer(path) {
+			fieldErrors = append(fieldErrors, FieldError{Field: field + ".path", Message: "path must be a JSON Pointer"})
+		}
+		if _, ok := op["value"]; spec.value && !ok {
+			fieldErrors = append(fieldErrors, FieldError{Field: field + ".value", Message: fmt.Sprintf("%s needs a value", name)})
+		}
+		if from, ok := op["from"].(string); spec.from && (!ok || !isJSONPointer(from)) {
+			fieldErrors = append(fieldErrors, FieldError{Field: field + ".from", Message: fmt.Sprintf("%s needs a JSON Pointer in from", name)})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return fmt.Errorf("schema validation failed: %w", &ValidationError{Errors: fieldErrors})
+	}
+	return nil
+}
+
+// ValidateMergePatch checks a JSON Merge Patch body against a resource schema leniently:
+// every field is optional and may be null, which removes it
+func ValidateMergePatch(schema *openapi3.Schema, body []byte) error {
+	if schema == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return fmt.Errorf("schema validation failed: %w", &ValidationError{Errors: []FieldError{{Message: "merge patch must be a JSON object"}}})
+	}
+
+	return ValidateValue(optionalFields(schema, make(map[*openapi3.Schema]*openapi3.Schema)), value)
+}
+
+// mergePatchSchema returns the schema declared for merge patch bodies, falling back to
+// the operation's JSON request schema
+func mergePatchSchema(operation *openapi3.Operation) *openapi3.Schema {
+	if operation == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil
+	}
+	if media := operation.RequestBody.Value.Content.Get(MergePatchMediaType); media != nil && media.Schema != nil {
+		return media.Schema.Value
+	}
+	return RequestSchema(operation)
+}
+
+// optionalFields returns a copy of schema, and of every schema nested in it, with no
+// required fields and null allowed; done maps schemas already copied, for recursion
+func optionalFields(schema *openapi3.Schema, done map[*openapi3.Schema]*openapi3.Schema) *openapi3.Schema {
+	if schema == nil {
+		return nil
+	}
+	if copied, ok := done[schema]; ok {
+		return copied
+	}
+
+	copied := &openapi3.Schema{}
+	*copied = *schema
+	done[schema] = copied
+
+	copied.Required = nil
+	copied.Nullable = true
+	copied.MinProps = 0
+	copied.Properties = make(openapi3.Schemas, len(schema.Properties))
+	for name, ref := range schema.Properties {
+		copied.Properties[name] = optionalRef(ref, done)
+	}
+	copied.AllOf = optionalRefs(schema.AllOf, done)
+	copied.AnyOf = optionalRefs(schema.AnyOf, done)
+	copied.OneOf = optionalRefs(schema.OneOf, done)
+	copied.AdditionalProperties.Schema = optionalRef(schema.AdditionalProperties.Schema, done)
+	return copied
+}
+
+// optionalRef applies optionalFields behind a schema reference
+func optionalRef(ref *openapi3.SchemaRef, done map[*openapi3.Schema]*openapi3.Schema) *openapi3.SchemaRef {
+	if ref == nil || ref.Value == nil {
+		return ref
+	}
+	return &openapi3.SchemaRef{Value: optionalFields(ref.Value, done)}
+}
+
+// optionalRefs applies optionalFields to each schema of a composition
+func optionalRefs(refs openapi3.SchemaRefs, done map[*openapi3.Schema]*openapi3.Schema) openapi3.SchemaRefs {
+	if len(refs) == 0 {
+		return nil
+	}
+	result := make(openapi3.SchemaRefs, len(refs))
+	for i, ref := range refs {
+		result[i] = optionalRef(ref, done)
+	}
+	return result
+}
+
+// isJSONPointer reports whether s is an RFC 6901 JSON Pointer: empty, or "/"-prefixed
+// reference tokens in which "~" only appears as the escapes "~0" and "~1"
+func isJSONPointer(s string) bool {
+	if s == "" {
+		return true
+	}
+	if !strings.HasPrefix(s, "/") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '~' && (i+1 == len(s) || (s[i+1] != '0' && s[i+1] != '1')) {
+			return false
+		}
+	}
+	return true
+}
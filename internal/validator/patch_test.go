@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"testing"
+)
+
+const patchSpec = `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    patch:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/merge-patch+json:
+            schema:
+              $ref: '#/components/schemas/User'
+          application/json-patch+json:
+            schema:
+              type: array
+              items:
+                type: object
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    User:
+      type: object
+      required: [name, email]
+      properties:
+        name:
+          type: string
+        email:
+          type: string
+        age:
+          type: integer
+          minimum: 0
+        address:
+          type: object
+          required: [city]
+          properties:
+            city:
+              type: string
+            zip:
+              type: string
+`
+
+func TestValidateRequestPatchMediaTypes(t *testing.T) {
+	operation := loadOperation(t, patchSpec, "PATCH", "/users/{id}")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expectErr   bool
+	}{
+		{name: "merge patch with one field", contentType: MergePatchMediaType, body: `{"age": 30}`},
+		{name: "merge patch removing a field", contentType: MergePatchMediaType, body: `{"email": null}`},
+		{name: "merge patch nested fields optional", contentType: MergePatchMediaType + "; charset=utf-8", body: `{"address": {"zip": "12345"}}`},
+		{name: "merge patch wrong type", contentType: MergePatchMediaType, body: `{"age": "old"}`, expectErr: true},
+		{name: "merge patch constraint still applies", contentType: MergePatchMediaType, body: `{"age": -1}`, expectErr: true},
+		{name: "merge patch not an object", contentType: MergePatchMediaType, body: `[1, 2]`, expectErr: true},
+		{
+			name:        "json patch operations",
+			contentType: JSONPatchMediaType,
+			body:        `[{"op": "replace", "path": "/name", "value": "Ann"}, {"op": "remove", "path": "/address/zip"}, {"op": "move", "from": "/a~1b", "path": "/c"}]`,
+		},
+		{name: "json patch unknown op", contentType: JSONPatchMediaType, body: `[{"op": "merge", "path": "/name"}]`, expectErr: true},
+		{name: "json patch missing value", contentType: JSONPatchMediaType, body: `[{"op": "add", "path": "/name"}]`, expectErr: true},
+		{name: "json patch bad pointer", contentType: JSONPatchMediaType, body: `[{"op": "remove", "path": "name"}]`, expectErr: true},
+		{name: "json patch bad escape", contentType: JSONPatchMediaType, body: `[{"op": "remove", "path": "/a~2"}]`, expectErr: true},
+		{name: "json patch missing from", contentType: JSONPatchMediaType, body: `[{"op": "copy", "path": "/name"}]`, expectErr: true},
+		{name: "json patch not an array", contentType: JSONPatchMediaType, body: `{"op": "remove", "path": "/name"}`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequest(operation, tt.contentType, []byte(tt.body))
+			if tt.expectErr && err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestPlainJSON(t *testing.T) {
+	operation := loadOperation(t, allOfSpec, "POST", "/users")
+
+	if err := ValidateRequest(operation, "application/json", []byte(`{"name": "Ann"}`)); err == nil {
+		t.Error("Expected plain JSON bodies to keep their required fields")
+	}
+	if err := ValidateRequest(operation, "application/json", []byte(`{"email": "a@example.com", "name": "Ann"}`)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RequestSchema returns the JSON request body schema declared by an operation, or nil if none
+func RequestSchema(operation *openapi3.Operation) *openapi3.Schema {
+	if operation == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil
+	}
+
+	jsonContent := operation.RequestBody.Value.Content.Get("application/json")
+	if jsonContent == nil || jsonContent.Schema == nil {
+		return nil
+	}
+	return jsonContent.Schema.Value
+}
+
+// ResponseSchema returns the JSON body schema declared for a response status, falling
+// back to the "default" response, or nil if none
+func ResponseSchema(operation *openapi3.Operation, status string) *openapi3.Schema {
+	if operation == nil || operation.Responses == nil {
+		return nil
+	}
+
+	responseRef := operation.Responses.Value(status)
+	if responseRef == nil {
+		responseRef = operation.Responses.Default()
+	}
+	if responseRef == nil || responseRef.Value == nil {
+		return nil
+	}
+
+	jsonContent := responseRef.Value.Content.Get("application/json")
+	if jsonContent == nil || jsonContent.Schema == nil {
+		return nil
+	}
+	return jsonContent.Schema.Value
+}
+
+// ValidateResponseBody checks a raw JSON response body against the schema declared
+// for the given status
+func ValidateResponseBody(operation *openapi3.Operation, status string, body []byte) error {
+	schema := ResponseSchema(operation, status)
+	if schema == nil {
+		return fmt.Errorf("no JSON response declared for status %s", status)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+
+	return ValidateValue(schema, value)
+}
+
+// ValidateRequestBody checks a raw JSON request body against the operation's request schema.
+// Composed schemas are validated as a whole, so required fields inherited through
+// allOf are enforced alongside the schema's own.
+func ValidateRequestBody(operation *openapi3.Operation, body []byte) error {
+	schema := RequestSchema(operation)
+	if schema == nil {
+		return nil
+	}
+
+	if len(body) == 0 {
+		if operation.RequestBody.Value.Required {
+			return fmt.Errorf("request body is required")
+		}
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+
+	return ValidateValue(schema, value)
+}
+
+// ValidateParameter checks a raw parameter value, such as a path segment, against the
+// parameter's schema after converting it to the schema's type
+func ValidateParameter(param *openapi3.Parameter, raw string) error {
+	if param == nil || param.Schema == nil || param.Schema.Value == nil {
+		return nil
+	}
+	schema := param.Schema.Value
+
+	var value interface{} = raw
+	switch {
+	case schema.Type.Is("integer"):
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return &ValidationError{Errors: []FieldError{{Field: param.Name, Message: "value must be an integer"}}}
+		}
+		value = float64(n)
+	case schema.Type.Is("number"):
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return &ValidationError{Errors: []FieldError{{Field: param.Name, Message: "value must be a number"}}}
+		}
+		value = n
+	case schema.Type.Is("boolean"):
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return &ValidationError{Errors: []FieldError{{Field: param.Name, Message: "value must be a boolean"}}}
+		}
+		value = b
+	}
+
+	err := ValidateValue(schema, value)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		for i := range validationErr.Errors {
+			validationErr.Errors[i].Field = param.Name
+		}
+		return validationErr
+	}
+	return err
+}
+
+// FieldError describes a single schema violation at a location in the value
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every schema violation found in a value, sorted by field
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		if fieldErr.Field == "" {
+			parts[i] = fieldErr.Message
+		} else {
+			parts[i] = fmt.Sprintf("field '%s': %s", fieldErr.Field, fieldErr.Message)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ValidateValue checks a decoded JSON value against a schema, reporting every violation
+func ValidateValue(schema *openapi3.Schema, value interface{}) error {
+	err := schema.VisitJSON(value, openapi3.MultiErrors())
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors []FieldError
+	collectFieldErrors(err, &fieldErrors)
+	sort.Slice(fieldErrors, func(i, j int) bool {
+		if fieldErrors[i].Field != fieldErrors[j].Field {
+			return fieldErrors[i].Field < fieldErrors[j].Field
+		}
+		return fieldErrors[i].Message < fieldErrors[j].Message
+	})
+	fieldErrors = slices.Compact(fieldErrors)
+
+	return fmt.Errorf("schema validation failed: %w", &ValidationError{Errors: fieldErrors})
+}
+
+// collectFieldErrors flattens kin-openapi's nested multi-errors into field errors
+func collectFieldErrors(err error, out *[]FieldError) {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, inner := range multi {
+			collectFieldErrors(inner, out)
+		}
+		return
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		*out = append(*out, FieldError{
+			Field:   strings.Join(schemaErr.JSONPointer(), "."),
+			Message: schemaErrorReason(schemaErr),
+		})
+		return
+	}
+
+	*out = append(*out, FieldError{Message: err.Error()})
+}
+
+// schemaErrorReason describes a schema error; kin-openapi leaves the reason empty for
+// some keywords, such as a value matching a not subschema
+func schemaErrorReason(err *openapi3.SchemaError) string {
+	if err.Reason != "" {
+		return err.Reason
+	}
+	if err.SchemaField == "not" {
+		return "value matches a schema it must not match"
+	}
+	return fmt.Sprintf("value doesn't match the schema's %s", err.SchemaField)
+}
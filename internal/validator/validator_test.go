@@ -0,0 +1,245 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const allOfSpec = `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewUser'
+      responses:
+        '201':
+          description: Created
+components:
+  schemas:
+    BaseUser:
+      type: object
+      required: [email]
+      properties:
+        email:
+          type: string
+    NewUser:
+      allOf:
+        - $ref: '#/components/schemas/BaseUser'
+        - type: object
+          required: [name]
+          properties:
+            name:
+              type: string
+`
+
+// loadOperation parses an inline spec and returns the operation for method and path
+func loadOperation(t *testing.T, spec, method, path string) *openapi3.Operation {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("Invalid spec: %v", err)
+	}
+	return doc.Paths.Value(path).GetOperation(method)
+}
+
+func TestValidateRequestBodyAllOf(t *testing.T) {
+	operation := loadOperation(t, allOfSpec, "POST", "/users")
+
+	tests := []struct {
+		name      string
+		body      string
+		expectErr bool
+	}{
+		{name: "all inherited fields present", body: `{"email": "a@example.com", "name": "Ann"}`},
+		{name: "missing inherited required field", body: `{"name": "Ann"}`, expectErr: true},
+		{name: "missing own required field", body: `{"email": "a@example.com"}`, expectErr: true},
+		{name: "empty required body", body: ``, expectErr: true},
+		{name: "malformed JSON", body: `{"email": `, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequestBody(operation, []byte(tt.body))
+			if tt.expectErr && err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+const usersSpec = `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, email]
+              properties:
+                name:
+                  type: string
+                email:
+                  type: string
+                age:
+                  type: integer
+                  minimum: 0
+      responses:
+        '201':
+          description: Created
+`
+
+func TestValidateRequestBodyFieldErrors(t *testing.T) {
+	operation := loadOperation(t, usersSpec, "POST", "/users")
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{
+			name:     "missing required fields",
+			body:     `{"age": 3}`,
+			expected: []string{"email", "name"},
+		},
+		{
+			name:     "type mismatch and range",
+			body:     `{"name": 7, "email": "a@example.com", "age": -1}`,
+			expected: []string{"age", "name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequestBody(operation, []byte(tt.body))
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+
+			var fields []string
+			for _, fieldErr := range validationErr.Errors {
+				if fieldErr.Message == "" {
+					t.Errorf("Expected a message for field %q", fieldErr.Field)
+				}
+				fields = append(fields, fieldErr.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected errors for %v, got %+v", tt.expected, validationErr.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateValueNot(t *testing.T) {
+	// A username that is any string but "admin", in an object that can't hold both
+	// a password and a token
+	schema := openapi3.NewObjectSchema().WithProperty("username", &openapi3.Schema{
+		Type: &openapi3.Types{"string"},
+		Not:  openapi3.NewSchemaRef("", &openapi3.Schema{Enum: []interface{}{"admin"}}),
+	})
+	schema.Not = openapi3.NewSchemaRef("", &openapi3.Schema{Required: []string{"password", "token"}})
+
+	tests := []struct {
+		name     string
+		value    map[string]interface{}
+		expected string // field of the expected error; empty means valid
+	}{
+		{name: "allowed value", value: map[string]interface{}{"username": "ann", "password": "x"}},
+		{name: "forbidden property value", value: map[string]interface{}{"username": "admin"}, expected: "username"},
+		{name: "forbidden shape", value: map[string]interface{}{"password": "x", "token": "y"}, expected: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue(schema, tt.value)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+				t.Fatalf("Expected one field error, got %v", err)
+			}
+			fieldErr := validationErr.Errors[0]
+			if field := fieldErr.Field; (tt.expected == "-" && field != "") || (tt.expected != "-" && field != tt.expected) {
+				t.Errorf("Expected the error on %q, got %q", tt.expected, field)
+			}
+			if !strings.Contains(fieldErr.Message, "must not match") {
+				t.Errorf("Expected a message about the not constraint, got %q", fieldErr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateParameter(t *testing.T) {
+	minimum := 1.0
+	status := &openapi3.Parameter{Name: "status", In: "path", Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type: &openapi3.Types{"string"},
+		Enum: []interface{}{"open", "closed"},
+	}}}
+	page := &openapi3.Parameter{Name: "page", In: "path", Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type: &openapi3.Types{"integer"},
+		Min:  &minimum,
+	}}}
+
+	tests := []struct {
+		name      string
+		param     *openapi3.Parameter
+		raw       string
+		expectErr bool
+	}{
+		{name: "enum member", param: status, raw: "open"},
+		{name: "outside enum", param: status, raw: "pending", expectErr: true},
+		{name: "integer in range", param: page, raw: "3"},
+		{name: "not an integer", param: page, raw: "three", expectErr: true},
+		{name: "below minimum", param: page, raw: "0", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParameter(tt.param, tt.raw)
+			if !tt.expectErr {
+				if err != nil {
+					t.Errorf("Unexpected validation error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if validationErr.Errors[0].Field != tt.param.Name {
+				t.Errorf("Expected error for %q, got %+v", tt.param.Name, validationErr.Errors)
+			}
+		})
+	}
+}